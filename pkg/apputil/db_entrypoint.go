@@ -85,6 +85,11 @@ func (e *DBEntryPoint[T]) Main() {
 		cliutil.MaybeFatal(err)
 	}
 	defer conn.Close()
+	dialect, err := conn.DetectDialect(ctx)
+	if err != nil {
+		cliutil.MaybeFatal(err)
+	}
+	logger.Debug("using database dialect", log.String("dialect", string(dialect)))
 
 	if e.flagDatabaseMigrate && e.Migrate != nil {
 		logger.Info("applying database migrations")
@@ -0,0 +1,35 @@
+package apputil
+
+import (
+	"context"
+	"net/http"
+
+	"sandman/pkg/log"
+)
+
+// HTTPMuxes groups the ancillary HTTP endpoints (expvar, health,
+// metrics) by listen address, so endpoints configured with the same
+// address share one listener.
+type HTTPMuxes map[string]*http.ServeMux
+
+// For returns the mux for an address, creating it on first use.
+func (hm HTTPMuxes) For(addr string) *http.ServeMux {
+	if mux, ok := hm[addr]; ok {
+		return mux
+	}
+	mux := http.NewServeMux()
+	hm[addr] = mux
+	return mux
+}
+
+// ListenAndServe serves each mux on its address in the background,
+// logging listener failures.
+func (hm HTTPMuxes) ListenAndServe(ctx context.Context) {
+	for addr, mux := range hm {
+		go func() {
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				log.GetLogger(ctx).Error("http server error", log.String("addr", addr), log.Any("err", err))
+			}
+		}()
+	}
+}
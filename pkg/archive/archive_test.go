@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sandman/pkg/assert"
+	"sandman/pkg/model"
+	"sandman/pkg/selector"
+	"sandman/pkg/uuid"
+)
+
+func testTimers(start time.Time, count int) []model.Timer {
+	output := make([]model.Timer, count)
+	for x := range output {
+		delivered := start.Add(time.Duration(x)*time.Minute + time.Second)
+		env := "prod"
+		if x%2 == 1 {
+			env = "dev"
+		}
+		output[x] = model.Timer{
+			ID:           uuid.V4(),
+			Name:         "timer-" + string(rune('a'+x)),
+			Labels:       map[string]string{"env": env},
+			DueUTC:       start.Add(time.Duration(x) * time.Minute),
+			HookURL:      "https://example.com/hook",
+			HookBody:     []byte(`{"x":1}`),
+			DeliveredUTC: &delivered,
+		}
+	}
+	return output
+}
+
+func Test_Writer_rotatesAndSearches(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(Config{Dir: dir, SegmentMaxRows: 3})
+	assert.Nil(t, err)
+	now := time.Date(2024, 10, 19, 20, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { now = now.Add(time.Millisecond); return now }
+
+	timers := testTimers(time.Date(2024, 10, 19, 12, 0, 0, 0, time.UTC), 8)
+	assert.Nil(t, w.Write(timers[:5]))
+	assert.Nil(t, w.Write(timers[5:]))
+
+	manifests, err := ReadManifests(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(manifests))
+	assert.Equal(t, 3, manifests[0].Rows)
+	assert.True(t, manifests[0].IsClosed())
+	assert.Equal(t, timers[0].DueUTC, manifests[0].MinDueUTC)
+	assert.Equal(t, timers[2].DueUTC, manifests[0].MaxDueUTC)
+	assert.Equal(t, 2, manifests[2].Rows)
+	assert.False(t, manifests[2].IsClosed(), "the last segment is still open")
+
+	var found []model.Timer
+	collect := func(t model.Timer) error { found = append(found, t); return nil }
+
+	// the open segment is readable up to its last sync.
+	assert.Nil(t, Search(dir, Query{}, collect))
+	assert.Equal(t, 8, len(found))
+	assert.Equal(t, timers[3].ID, found[3].ID)
+	assert.Equal(t, timers[3].HookBody, found[3].HookBody)
+
+	found = nil
+	sel, err := selector.Parse("env=prod")
+	assert.Nil(t, err)
+	assert.Nil(t, Search(dir, Query{Selector: sel, DueAfter: timers[1].DueUTC, DueBefore: timers[7].DueUTC}, collect))
+	assert.Equal(t, 3, len(found)) // timers 2, 4 and 6
+	assert.Equal(t, timers[2].ID, found[0].ID)
+
+	found = nil
+	assert.Nil(t, Search(dir, Query{DeliveredAfter: *timers[5].DeliveredUTC}, collect))
+	assert.Equal(t, 2, len(found))
+
+	assert.Nil(t, w.Close())
+	manifests, err = ReadManifests(dir)
+	assert.Nil(t, err)
+	assert.True(t, manifests[2].IsClosed())
+}
+
+func Test_Writer_Prune(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(Config{Dir: dir, SegmentMaxRows: 1, Retention: time.Hour})
+	assert.Nil(t, err)
+	now := time.Date(2024, 10, 19, 20, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	timers := testTimers(now, 2)
+	assert.Nil(t, w.Write(timers[:1]))
+	now = now.Add(2 * time.Hour)
+	assert.Nil(t, w.Write(timers[1:]))
+
+	removed, err := w.Prune()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, removed)
+
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries), "the recent segment and its manifest remain")
+	_, err = os.Stat(filepath.Join(dir, segmentName(now)))
+	assert.Nil(t, err)
+}
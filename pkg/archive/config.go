@@ -0,0 +1,39 @@
+package archive
+
+import "time"
+
+// Config configures where culled timers are archived.
+type Config struct {
+	// Dir is the directory segments are written to. Empty disables
+	// archiving; culled timers are deleted outright.
+	Dir string `yaml:"dir"`
+	// SegmentMaxRows is how many timers a segment holds before a new one
+	// is started.
+	SegmentMaxRows int `yaml:"segment_max_rows"`
+	// Retention is how long a segment is kept after it's closed.
+	Retention time.Duration `yaml:"retention"`
+}
+
+const (
+	DefaultSegmentMaxRows               = 100_000
+	DefaultRetention      time.Duration = 90 * 24 * time.Hour
+)
+
+// IsEnabled returns if archiving is configured.
+func (c Config) IsEnabled() bool {
+	return c.Dir != ""
+}
+
+func (c Config) SegmentMaxRowsOrDefault() int {
+	if c.SegmentMaxRows > 0 {
+		return c.SegmentMaxRows
+	}
+	return DefaultSegmentMaxRows
+}
+
+func (c Config) RetentionOrDefault() time.Duration {
+	if c.Retention > 0 {
+		return c.Retention
+	}
+	return DefaultRetention
+}
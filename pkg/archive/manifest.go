@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sandman/pkg/model"
+)
+
+const (
+	segmentPrefix     = "segment-"
+	segmentExtension  = ".jsonl.gz"
+	manifestExtension = ".manifest.json"
+	segmentTimeFormat = "20060102T150405.000000000Z"
+)
+
+// Manifest describes a segment file. It is rewritten after every batch
+// so it always matches what has been flushed to the segment.
+type Manifest struct {
+	// Segment is the segment file name, relative to the archive dir.
+	Segment   string     `json:"segment"`
+	Rows      int        `json:"rows"`
+	OpenedUTC time.Time  `json:"opened_utc"`
+	ClosedUTC *time.Time `json:"closed_utc,omitempty"`
+
+	MinDueUTC       time.Time  `json:"min_due_utc"`
+	MaxDueUTC       time.Time  `json:"max_due_utc"`
+	MinDeliveredUTC *time.Time `json:"min_delivered_utc,omitempty"`
+	MaxDeliveredUTC *time.Time `json:"max_delivered_utc,omitempty"`
+}
+
+// IsClosed returns if the segment has been rotated out.
+func (m Manifest) IsClosed() bool {
+	return m.ClosedUTC != nil
+}
+
+// include widens the manifest's time ranges to cover a timer.
+func (m *Manifest) include(t model.Timer) {
+	if m.Rows == 0 || t.DueUTC.Before(m.MinDueUTC) {
+		m.MinDueUTC = t.DueUTC
+	}
+	if m.Rows == 0 || t.DueUTC.After(m.MaxDueUTC) {
+		m.MaxDueUTC = t.DueUTC
+	}
+	if t.DeliveredUTC != nil {
+		if m.MinDeliveredUTC == nil || t.DeliveredUTC.Before(*m.MinDeliveredUTC) {
+			m.MinDeliveredUTC = t.DeliveredUTC
+		}
+		if m.MaxDeliveredUTC == nil || t.DeliveredUTC.After(*m.MaxDeliveredUTC) {
+			m.MaxDeliveredUTC = t.DeliveredUTC
+		}
+	}
+	m.Rows++
+}
+
+func segmentName(opened time.Time) string {
+	return segmentPrefix + opened.UTC().Format(segmentTimeFormat) + segmentExtension
+}
+
+func manifestName(segment string) string {
+	return strings.TrimSuffix(segment, segmentExtension) + manifestExtension
+}
+
+// writeManifest writes a manifest atomically (write then rename) so a
+// reader never sees a partial one.
+func writeManifest(dir string, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, manifestName(m.Segment))
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadManifests returns the manifests in an archive dir, oldest first.
+func ReadManifests(dir string) (output []Manifest, err error) {
+	paths, err := filepath.Glob(filepath.Join(dir, segmentPrefix+"*"+manifestExtension))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var m Manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		output = append(output, m)
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].OpenedUTC.Before(output[j].OpenedUTC)
+	})
+	return output, nil
+}
@@ -0,0 +1,130 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sandman/pkg/model"
+	"sandman/pkg/selector"
+)
+
+// Query filters archived timers. Zero times are unbounded; bounds are
+// exclusive, matching `ListTimers`.
+type Query struct {
+	Selector        selector.Selector
+	DueAfter        time.Time
+	DueBefore       time.Time
+	DeliveredAfter  time.Time
+	DeliveredBefore time.Time
+}
+
+// Matches returns if a timer matches the query.
+func (q Query) Matches(t model.Timer) bool {
+	if !q.DueAfter.IsZero() && !t.DueUTC.After(q.DueAfter) {
+		return false
+	}
+	if !q.DueBefore.IsZero() && !t.DueUTC.Before(q.DueBefore) {
+		return false
+	}
+	if !q.DeliveredAfter.IsZero() || !q.DeliveredBefore.IsZero() {
+		if t.DeliveredUTC == nil {
+			return false
+		}
+		if !q.DeliveredAfter.IsZero() && !t.DeliveredUTC.After(q.DeliveredAfter) {
+			return false
+		}
+		if !q.DeliveredBefore.IsZero() && !t.DeliveredUTC.Before(q.DeliveredBefore) {
+			return false
+		}
+	}
+	return q.Selector == nil || q.Selector.Matches(t.MatchLabels())
+}
+
+// mayMatch returns if a segment could hold timers matching the query,
+// judging by its manifest.
+func (q Query) mayMatch(m Manifest) bool {
+	if m.Rows == 0 {
+		return false
+	}
+	if !q.DueAfter.IsZero() && !m.MaxDueUTC.After(q.DueAfter) {
+		return false
+	}
+	if !q.DueBefore.IsZero() && !m.MinDueUTC.Before(q.DueBefore) {
+		return false
+	}
+	if !q.DeliveredAfter.IsZero() || !q.DeliveredBefore.IsZero() {
+		if m.MinDeliveredUTC == nil {
+			return false
+		}
+		if !q.DeliveredAfter.IsZero() && !m.MaxDeliveredUTC.After(q.DeliveredAfter) {
+			return false
+		}
+		if !q.DeliveredBefore.IsZero() && !m.MinDeliveredUTC.Before(q.DeliveredBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// maxLineBytes bounds a single archived timer (hook bodies included).
+const maxLineBytes = 64 << 20
+
+// Search calls fn for every archived timer in dir matching the query,
+// skipping segments whose manifest rules them out.
+//
+// Only the rows a manifest accounts for are read, so a segment still
+// being written (or cut short by a crash) is read up to its last sync.
+// A timer that was archived but whose delete failed can appear twice.
+func Search(dir string, q Query, fn func(model.Timer) error) error {
+	manifests, err := ReadManifests(dir)
+	if err != nil {
+		return fmt.Errorf("archive; read manifests: %w", err)
+	}
+	for _, m := range manifests {
+		if !q.mayMatch(m) {
+			continue
+		}
+		if err := searchSegment(filepath.Join(dir, m.Segment), m.Rows, q, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func searchSegment(path string, rows int, q Query, fn func(model.Timer) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("archive; open segment: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("archive; read segment %s: %w", filepath.Base(path), err)
+	}
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(nil, maxLineBytes)
+	for read := 0; read < rows; read++ {
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return fmt.Errorf("archive; read segment %s row %d: %w", filepath.Base(path), read+1, err)
+			}
+			return fmt.Errorf("archive; read segment %s: %d rows missing", filepath.Base(path), rows-read)
+		}
+		var t model.Timer
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil {
+			return fmt.Errorf("archive; read segment %s row %d: %w", filepath.Base(path), read+1, err)
+		}
+		if !q.Matches(t) {
+			continue
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
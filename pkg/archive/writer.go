@@ -0,0 +1,154 @@
+package archive
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"sandman/pkg/model"
+)
+
+// NewWriter returns a writer for the configured archive dir, creating
+// the dir if needed.
+//
+// A writer never appends to an existing segment; the first write after
+// start opens a new one.
+func NewWriter(cfg Config) (*Writer, error) {
+	if !cfg.IsEnabled() {
+		return nil, fmt.Errorf("archive; dir is unset")
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("archive; create dir: %w", err)
+	}
+	return &Writer{
+		cfg: cfg,
+		now: func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// Writer appends timers to rotating gzip jsonl segments.
+type Writer struct {
+	cfg Config
+	now func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	gz       *gzip.Writer
+	enc      *json.Encoder
+	manifest Manifest
+}
+
+// Write appends timers to the current segment, rotating as segments fill
+// up. When it returns without error the timers are synced to disk and
+// the manifests reflect them, so the rows are safe to delete.
+func (w *Writer) Write(timers []model.Timer) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, t := range timers {
+		if w.file == nil {
+			if err := w.open(); err != nil {
+				return err
+			}
+		}
+		if err := w.enc.Encode(t); err != nil {
+			return fmt.Errorf("archive; write: %w", err)
+		}
+		w.manifest.include(t)
+		if w.manifest.Rows >= w.cfg.SegmentMaxRowsOrDefault() {
+			if err := w.close(); err != nil {
+				return err
+			}
+		}
+	}
+	if w.file == nil {
+		return nil
+	}
+	return w.sync()
+}
+
+// Close closes the current segment, if any.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.close()
+}
+
+// Prune removes closed segments older than the configured retention,
+// returning how many were removed.
+func (w *Writer) Prune() (removed int, err error) {
+	manifests, err := ReadManifests(w.cfg.Dir)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := w.now().Add(-w.cfg.RetentionOrDefault())
+	for _, m := range manifests {
+		if !m.IsClosed() || m.ClosedUTC.After(cutoff) {
+			continue
+		}
+		if err = os.Remove(filepath.Join(w.cfg.Dir, m.Segment)); err != nil && !os.IsNotExist(err) {
+			return
+		}
+		if err = os.Remove(filepath.Join(w.cfg.Dir, manifestName(m.Segment))); err != nil && !os.IsNotExist(err) {
+			return
+		}
+		err = nil
+		removed++
+	}
+	return
+}
+
+func (w *Writer) open() error {
+	opened := w.now()
+	name := segmentName(opened)
+	file, err := os.OpenFile(filepath.Join(w.cfg.Dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("archive; open segment: %w", err)
+	}
+	w.file = file
+	w.gz = gzip.NewWriter(file)
+	w.enc = json.NewEncoder(w.gz)
+	w.manifest = Manifest{
+		Segment:   name,
+		OpenedUTC: opened,
+	}
+	return nil
+}
+
+func (w *Writer) sync() error {
+	if err := w.gz.Flush(); err != nil {
+		return fmt.Errorf("archive; flush segment: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("archive; sync segment: %w", err)
+	}
+	if err := writeManifest(w.cfg.Dir, w.manifest); err != nil {
+		return fmt.Errorf("archive; write manifest: %w", err)
+	}
+	return nil
+}
+
+func (w *Writer) close() error {
+	if err := w.gz.Close(); err != nil {
+		return fmt.Errorf("archive; close segment: %w", err)
+	}
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("archive; sync segment: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("archive; close segment: %w", err)
+	}
+	closed := w.now()
+	w.manifest.ClosedUTC = &closed
+	if err := writeManifest(w.cfg.Dir, w.manifest); err != nil {
+		return fmt.Errorf("archive; write manifest: %w", err)
+	}
+	w.file, w.gz, w.enc = nil, nil, nil
+	return nil
+}
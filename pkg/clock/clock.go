@@ -0,0 +1,62 @@
+// Package clock abstracts the wall clock so code that reads the time,
+// or waits on it, can be driven by a Fake in tests instead of sleeping.
+package clock
+
+import "time"
+
+// Clock tells the time and makes tickers and timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+	// NewTicker returns a ticker that ticks every d; see time.NewTicker.
+	NewTicker(d time.Duration) Ticker
+	// NewTimer returns a timer that fires once after d; see
+	// time.NewTimer.
+	NewTimer(d time.Duration) Timer
+}
+
+// Ticker is a time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Timer is a time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Real returns the wall clock.
+func Real() Clock { return realClock{} }
+
+// OrReal returns c, or the wall clock if c is nil, so optional clocks
+// can be threaded through without nil checks.
+func OrReal(c Clock) Clock {
+	if c != nil {
+		return c
+	}
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
+
+type realTimer struct{ *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
@@ -0,0 +1,156 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+var _ Clock = (*Fake)(nil)
+
+// NewFake returns a fake clock stopped at now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Fake is a clock that only moves when it's told to. Tickers and timers
+// made from it fire as Advance or Set carries the clock past them; like
+// the real ones, a tick that isn't received before the next is dropped.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+// Now returns the fake's current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Since returns the time elapsed on the fake since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Advance moves the clock forward by d, firing whatever comes due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(f.now.Add(d))
+}
+
+// Set moves the clock to now, firing whatever comes due. Moving it
+// backwards fires nothing.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(now)
+}
+
+// Waiters returns how many tickers and active timers are waiting on the
+// clock, so a test can wait for a goroutine to start waiting before it
+// advances the clock.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// NewTicker returns a ticker that ticks every d of fake time.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{fake: f, c: make(chan time.Time, 1), next: f.now.Add(d), period: d}
+	f.waiters = append(f.waiters, w)
+	return fakeTicker{w}
+}
+
+// NewTimer returns a timer that fires once d of fake time from now.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{fake: f, c: make(chan time.Time, 1), next: f.now.Add(d)}
+	f.waiters = append(f.waiters, w)
+	f.fireLocked()
+	return w
+}
+
+func (f *Fake) setLocked(now time.Time) {
+	if now.After(f.now) {
+		f.now = now
+	}
+	f.fireLocked()
+}
+
+// fireLocked sends on every waiter that's come due; timers stop waiting
+// once they fire, tickers move on to their next tick.
+func (f *Fake) fireLocked() {
+	waiting := f.waiters[:0]
+	for _, w := range f.waiters {
+		for !w.next.After(f.now) {
+			select {
+			case w.c <- w.next:
+			default:
+			}
+			if w.period == 0 {
+				break
+			}
+			w.next = w.next.Add(w.period)
+		}
+		if w.period > 0 || w.next.After(f.now) {
+			waiting = append(waiting, w)
+		}
+	}
+	clear(f.waiters[len(waiting):])
+	f.waiters = waiting
+}
+
+func (f *Fake) removeLocked(w *fakeWaiter) bool {
+	for index, waiting := range f.waiters {
+		if waiting == w {
+			f.waiters = append(f.waiters[:index], f.waiters[index+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// fakeWaiter is a fake ticker (period > 0) or timer.
+type fakeWaiter struct {
+	fake   *Fake
+	c      chan time.Time
+	next   time.Time
+	period time.Duration
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.c }
+
+func (w *fakeWaiter) Stop() bool {
+	w.fake.mu.Lock()
+	defer w.fake.mu.Unlock()
+	return w.fake.removeLocked(w)
+}
+
+// Reset restarts a timer to fire d from now, dropping a fire that
+// hasn't been received, as time.Timer does since Go 1.23.
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	w.fake.mu.Lock()
+	defer w.fake.mu.Unlock()
+	active := w.fake.removeLocked(w)
+	select {
+	case <-w.c:
+	default:
+	}
+	w.next = w.fake.now.Add(d)
+	w.fake.waiters = append(w.fake.waiters, w)
+	w.fake.fireLocked()
+	return active
+}
+
+type fakeTicker struct{ *fakeWaiter }
+
+func (t fakeTicker) Stop() { t.fakeWaiter.Stop() }
@@ -0,0 +1,92 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+var anchor = time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case ts := <-c:
+		return ts, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFake_Advance(t *testing.T) {
+	f := NewFake(anchor)
+	f.Advance(time.Minute)
+	if got := f.Now(); !got.Equal(anchor.Add(time.Minute)) {
+		t.Fatalf("Now: got %v want %v", got, anchor.Add(time.Minute))
+	}
+	if got := f.Since(anchor); got != time.Minute {
+		t.Fatalf("Since: got %v want 1m", got)
+	}
+	f.Set(anchor)
+	if got := f.Now(); !got.Equal(anchor.Add(time.Minute)) {
+		t.Fatalf("Set backwards moved the clock to %v", got)
+	}
+}
+
+func TestFake_Ticker(t *testing.T) {
+	f := NewFake(anchor)
+	tick := f.NewTicker(time.Second)
+	if _, ok := received(tick.C()); ok {
+		t.Fatal("ticked before the clock moved")
+	}
+	f.Advance(999 * time.Millisecond)
+	if _, ok := received(tick.C()); ok {
+		t.Fatal("ticked early")
+	}
+	f.Advance(time.Millisecond)
+	if ts, ok := received(tick.C()); !ok || !ts.Equal(anchor.Add(time.Second)) {
+		t.Fatalf("expected a tick at +1s, got %v %v", ts, ok)
+	}
+	// ticks nobody receives are dropped, as with time.Ticker.
+	f.Advance(5 * time.Second)
+	if ts, ok := received(tick.C()); !ok || !ts.Equal(anchor.Add(2*time.Second)) {
+		t.Fatalf("expected the first missed tick at +2s, got %v %v", ts, ok)
+	}
+	if _, ok := received(tick.C()); ok {
+		t.Fatal("expected later missed ticks to be dropped")
+	}
+	tick.Stop()
+	f.Advance(time.Minute)
+	if _, ok := received(tick.C()); ok {
+		t.Fatal("ticked after Stop")
+	}
+	if got := f.Waiters(); got != 0 {
+		t.Fatalf("Waiters: got %d want 0", got)
+	}
+}
+
+func TestFake_Timer(t *testing.T) {
+	f := NewFake(anchor)
+	timer := f.NewTimer(time.Second)
+	if got := f.Waiters(); got != 1 {
+		t.Fatalf("Waiters: got %d want 1", got)
+	}
+	f.Advance(time.Second)
+	if _, ok := received(timer.C()); !ok {
+		t.Fatal("timer didn't fire")
+	}
+	if got := f.Waiters(); got != 0 {
+		t.Fatalf("Waiters after firing: got %d want 0", got)
+	}
+	if timer.Reset(time.Second) {
+		t.Fatal("Reset of a fired timer reported it active")
+	}
+	if !timer.Stop() {
+		t.Fatal("Stop of a reset timer reported it inactive")
+	}
+	f.Advance(time.Minute)
+	if _, ok := received(timer.C()); ok {
+		t.Fatal("timer fired after Stop")
+	}
+	if immediate := f.NewTimer(0); func() bool { _, ok := received(immediate.C()); return !ok }() {
+		t.Fatal("a zero timer should fire immediately")
+	}
+}
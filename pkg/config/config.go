@@ -2,7 +2,11 @@ package config
 
 import (
 	"context"
+	"sandman/pkg/archive"
+	"sandman/pkg/egress"
 	"sandman/pkg/grpcutil"
+	"sandman/pkg/health"
+	"sandman/pkg/metrics"
 	"strings"
 	"time"
 
@@ -19,6 +23,18 @@ type Config struct {
 	DBHosts []string        `yaml:"dbHosts,omitempty"`
 	Server  grpcutil.Config `yaml:"server"`
 	Worker  WorkerConfig    `yaml:"worker"`
+	// Egress restricts where timer hooks may point. It is checked by
+	// sandman-srv when timers are created and by workers when they dial.
+	Egress egress.Config `yaml:"egress"`
+	// Archive is where the controller writes culled timers. sandman-srv
+	// also searches it when replaying, if the dir is reachable from it.
+	Archive archive.Config `yaml:"archive"`
+	// Health configures the /healthz, /readyz and /drain endpoints of
+	// sandman-srv and sandman-worker.
+	Health health.Config `yaml:"health"`
+	// Metrics configures the Prometheus /metrics endpoint of all three
+	// binaries.
+	Metrics metrics.Config `yaml:"metrics"`
 }
 
 // DefaultDBMaxLifetime bounds how long a pooled connection sticks to one
@@ -33,6 +49,8 @@ func (c *Config) Resolve(ctx context.Context) error {
 		configutil.Set(&c.Config.DB.Database, configutil.Lazy(&c.Config.DB.Database), configutil.Const("sandman")),
 		(&c.Config).Resolve,
 		(&c.Server).Resolve,
+		(&c.Health).Resolve,
+		(&c.Metrics).Resolve,
 	); err != nil {
 		return err
 	}
@@ -54,13 +72,73 @@ func (c *Config) Resolve(ctx context.Context) error {
 type WorkerConfig struct {
 	BatchSize       int           `yaml:"batch_size"`
 	PollingInterval time.Duration `yaml:"polling_interval"`
+	// MinBatchSize and MaxBatchSize bound the adaptive claim batch. If
+	// MaxBatchSize is zero (default) the batch stays at BatchSize.
+	MinBatchSize int `yaml:"min_batch_size"`
+	MaxBatchSize int `yaml:"max_batch_size"`
+	// CapacityWeight is the worker's share of the shard space relative
+	// to its peers; a weight-2 worker owns about twice the shards of a
+	// weight-1 worker. Zero means the default of 1.
+	CapacityWeight int `yaml:"capacity_weight"`
 	// PrefetchWindow turns on wheel-mode dispatch. Zero (default)
 	// keeps the original "claim due now, fire, mark delivered" tick
 	// loop. Anything > 0 swaps in three loops (prefetch, dispatch,
 	// flush) keyed off an in-memory hash wheel.
 	PrefetchWindow       time.Duration `yaml:"prefetch_window"`
+	// WheelResolution is the width of the wheel's finest slots, e.g.
+	// 10ms to fire timers within 10ms of due rather than rounding them
+	// to the second. Must evenly divide a second. Zero means 1s.
+	WheelResolution time.Duration `yaml:"wheel_resolution"`
+	// WheelMaxOverflow caps how many timers due beyond the wheel's
+	// horizon the worker holds; the rest are relinquished as soon as
+	// they're claimed. Zero means unbounded.
+	WheelMaxOverflow int `yaml:"wheel_max_overflow"`
+	// WheelMaxHeld and WheelMaxHeldBytes budget the timers the wheel
+	// holds, by count and by approximate memory including hook bodies.
+	// Near either budget the prefetch claims less or skips a tick. Zero
+	// means unbounded.
+	WheelMaxHeld      int   `yaml:"wheel_max_held"`
+	WheelMaxHeldBytes int64 `yaml:"wheel_max_held_bytes"`
+	// DispatchTickInterval is how often the wheel is advanced. Zero
+	// means once per WheelResolution.
 	DispatchTickInterval time.Duration `yaml:"dispatch_tick_interval"`
+	// PacedDispatch spreads each dispatch tick's timers across the tick,
+	// highest priority first, instead of firing them in one burst.
+	// PacingTolerance is how long past due_utc pacing may hold a timer;
+	// zero fires each timer at its due_utc.
+	PacedDispatch   bool          `yaml:"paced_dispatch"`
+	PacingTolerance time.Duration `yaml:"pacing_tolerance"`
+	// PrewarmLead turns on connection pre-warming in wheel mode: hosts
+	// of timers due within the lead get a connection opened ahead of
+	// time with an `OPTIONS *` request, at most PrewarmMaxHosts
+	// (default 16) at a time. Zero (default) turns it off.
+	PrewarmLead     time.Duration `yaml:"prewarm_lead"`
+	PrewarmMaxHosts int           `yaml:"prewarm_max_hosts"`
+	// DNSCacheTTL, if set, caches hook host lookups for this long.
+	DNSCacheTTL time.Duration `yaml:"dns_cache_ttl"`
 	FlushInterval        time.Duration `yaml:"flush_interval"`
+	// LeaseRenewInterval is how often wheel mode extends the leases of
+	// timers still parked in the wheel. Zero means a third of the lease
+	// safety margin (10s).
+	LeaseRenewInterval time.Duration `yaml:"lease_renew_interval"`
+	// DrainTimeout bounds how long a wheel-mode drain waits for the wheel
+	// to empty before relinquishing the rest. Zero means 20s.
+	DrainTimeout time.Duration `yaml:"drain_timeout"`
+	// ShardKeyWeights gives selected shard keys a larger share of each
+	// claim; unlisted keys have weight 1.
+	ShardKeyWeights map[string]uint32 `yaml:"shard_key_weights,omitempty"`
+	// MaxClaimsPerKey caps how many timers a single shard key can take
+	// from one claim (times its weight). Zero means no cap.
+	MaxClaimsPerKey int `yaml:"max_claims_per_key"`
+	// HookSigningSecret, if set, is used to sign every hook request with
+	// a `Sandman-Signature` header receivers can verify with `pkg/hook`.
+	HookSigningSecret string `yaml:"hook_signing_secret"`
+	// WakeupAddrs are sandman-srv gRPC addresses the worker watches for
+	// timers created due before its next poll, so it can claim them
+	// straight away. Each server only announces the timers created
+	// through it, so list every server. On Postgres the worker also
+	// listens for them on the database, which covers every server.
+	WakeupAddrs []string `yaml:"wakeup_addrs,omitempty"`
 }
 
 const (
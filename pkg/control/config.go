@@ -14,9 +14,19 @@ type Config struct {
 	// never delays scale decisions.
 	CullInterval time.Duration
 	// CullRetention is how long a delivered timer is kept past its
-	// due_utc before it becomes eligible for deletion. Gives operators
-	// a grace period to inspect recently-delivered rows before they go.
+	// due_utc (and past its fired_utc, if it fired late) before it
+	// becomes eligible for deletion. Gives operators a grace period to
+	// inspect recently-delivered rows before they go.
 	CullRetention time.Duration
+	// CullBatchSize is how many timers are archived and deleted at a
+	// time when an archiver is set.
+	CullBatchSize int
+	// StatsRetention is how long delivery stats rollups are kept.
+	StatsRetention time.Duration
+	// WheelOccupancyTarget is the mean worker wheel occupancy the
+	// controller scales out to stay under; workers nearer their wheel
+	// budgets than this claim less than the due timers need.
+	WheelOccupancyTarget float64
 }
 
 const (
@@ -24,6 +34,9 @@ const (
 	DefaultMinReplicas       int32     = 3
 	DefaultCullInterval                = 1 * time.Minute
 	DefaultCullRetention               = 5 * time.Minute
+	DefaultCullBatchSize               = 1000
+	DefaultStatsRetention              = 30 * 24 * time.Hour
+	DefaultWheelOccupancyTarget        = 0.75
 )
 
 func (c Config) EvaluationIntervalOrDefault() time.Duration {
@@ -68,6 +81,27 @@ func (c Config) CullRetentionOrDefault() time.Duration {
 	return DefaultCullRetention
 }
 
+func (c Config) CullBatchSizeOrDefault() int {
+	if c.CullBatchSize > 0 {
+		return c.CullBatchSize
+	}
+	return DefaultCullBatchSize
+}
+
+func (c Config) StatsRetentionOrDefault() time.Duration {
+	if c.StatsRetention > 0 {
+		return c.StatsRetention
+	}
+	return DefaultStatsRetention
+}
+
+func (c Config) WheelOccupancyTargetOrDefault() float64 {
+	if c.WheelOccupancyTarget > 0 {
+		return c.WheelOccupancyTarget
+	}
+	return DefaultWheelOccupancyTarget
+}
+
 // K8sConfig holds Kubernetes-specific controller settings.
 type K8sConfig struct {
 	Namespace  string `yaml:"namespace"`
@@ -5,7 +5,10 @@ import (
 	"math"
 	"time"
 
+	"sandman/pkg/archive"
+	"sandman/pkg/clock"
 	"sandman/pkg/log"
+	"sandman/pkg/uuid"
 
 	"sandman/pkg/model"
 )
@@ -14,8 +17,17 @@ import (
 // and delegates scaling actions to the configured Scaler.
 type Controller struct {
 	Config Config
-	Model  *model.Manager
+	Model  model.TimerStore
 	Scaler Scaler
+	// Archiver, if set, is written every cull batch before it's deleted.
+	Archiver *archive.Writer
+	// Clock, if set, is what the controller reads the time and ticks
+	// from. Nil uses the wall clock.
+	Clock clock.Clock
+}
+
+func (c *Controller) clockOrDefault() clock.Clock {
+	return clock.OrReal(c.Clock)
 }
 
 // Run starts the control loop, evaluating desired scale every EvaluationInterval
@@ -33,45 +45,104 @@ func (c *Controller) Run(ctx context.Context) error {
 		log.Duration("worker_polling_interval", c.Config.WorkerPollingIntervalOrDefault()),
 		log.Duration("cull_interval", cullInterval),
 		log.Duration("cull_retention", cullRetention),
+		log.Duration("stats_retention", c.Config.StatsRetentionOrDefault()),
+		log.Float64("wheel_occupancy_target", c.Config.WheelOccupancyTargetOrDefault()),
 	)
 
 	go c.runCullLoop(ctx, cullInterval, cullRetention)
 
 	c.evaluate(ctx)
-	tick := time.NewTicker(evalInterval)
+	tick := c.clockOrDefault().NewTicker(evalInterval)
 	defer tick.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-tick.C:
+		case <-tick.C():
 			c.evaluate(ctx)
 		}
 	}
 }
 
+// runCullLoop rolls up delivery stats ahead of every cull so delivered
+// timers are summarized before they're deleted.
 func (c *Controller) runCullLoop(ctx context.Context, interval, retention time.Duration) {
-	tick := time.NewTicker(interval)
+	tick := c.clockOrDefault().NewTicker(interval)
 	defer tick.Stop()
+	c.rollupStats(ctx)
 	c.cull(ctx, retention)
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-tick.C:
+		case <-tick.C():
+			c.rollupStats(ctx)
 			c.cull(ctx, retention)
 		}
 	}
 }
 
+const (
+	// statsRollupSettle is how long after a bucket closes it is rolled
+	// up, giving workers time to flush the deliveries fired within it.
+	statsRollupSettle = time.Minute
+	// statsRollupMaxLookback caps how far back a rollup reaches, e.g.
+	// on first start or after the controller was down.
+	statsRollupMaxLookback = time.Hour
+)
+
+// rollupStats summarizes the buckets closed since the last rollup into
+// delivery_stats and drops rollups past the stats retention.
+//
+// Each bucket is rolled up once, so a delivery written more than
+// statsRollupSettle after its bucket closed is missed. Culling holds a
+// timer until its fired_utc passes the cull retention, which must
+// exceed the settle time plus the cull interval.
+func (c *Controller) rollupStats(ctx context.Context) {
+	logger := log.GetLogger(ctx)
+	nowUTC := c.clockOrDefault().Now().UTC()
+	before := nowUTC.Add(-statsRollupSettle).Truncate(model.DeliveryStatsBucket)
+	after := before.Add(-statsRollupMaxLookback)
+	watermark, found, err := c.Model.GetDeliveryStatsWatermark(ctx)
+	if err != nil {
+		logger.Error("controller; stats rollup failed", log.Any("err", err))
+		return
+	}
+	if found && watermark.Add(model.DeliveryStatsBucket).After(after) {
+		after = watermark.Add(model.DeliveryStatsBucket)
+	}
+	if after.Before(before) {
+		buckets, err := c.Model.RollupDeliveryStats(ctx, after, before)
+		if err != nil {
+			logger.Error("controller; stats rollup failed", log.Any("err", err))
+			return
+		}
+		if buckets > 0 {
+			logger.Info("controller; stats rollup complete",
+				log.Int("buckets", buckets),
+				log.Time("after", after),
+				log.Time("before", before),
+			)
+		}
+	}
+	if _, err := c.Model.CullDeliveryStats(ctx, nowUTC.Add(-c.Config.StatsRetentionOrDefault())); err != nil {
+		logger.Error("controller; stats cull failed", log.Any("err", err))
+	}
+}
+
 func (c *Controller) cull(ctx context.Context, retention time.Duration) {
 	logger := log.GetLogger(ctx)
-	cutoff := time.Now().UTC().Add(-retention)
+	cutoff := c.clockOrDefault().Now().UTC().Add(-retention)
+	if c.Archiver != nil {
+		c.cullArchived(ctx, cutoff)
+		return
+	}
 	rowsAffected, err := c.Model.CullTimers(ctx, cutoff)
 	if err != nil {
 		logger.Error("controller; cull failed", log.Any("err", err))
 		return
 	}
+	metricTimersCulled.Add(float64(rowsAffected))
 	if rowsAffected > 0 {
 		logger.Info("controller; cull complete",
 			log.Int("rows_deleted", int(rowsAffected)),
@@ -80,9 +151,62 @@ func (c *Controller) cull(ctx context.Context, retention time.Duration) {
 	}
 }
 
+// cullArchived culls in batches, writing each batch to the archive
+// before deleting it. A batch that fails to archive is left in place for
+// the next cull; one that archives but fails to delete is archived again
+// next time.
+func (c *Controller) cullArchived(ctx context.Context, cutoff time.Time) {
+	logger := log.GetLogger(ctx)
+	batchSize := c.Config.CullBatchSizeOrDefault()
+	var rowsArchived, rowsDeleted int64
+	defer func() {
+		if rowsArchived > 0 {
+			logger.Info("controller; cull complete",
+				log.Int("rows_archived", int(rowsArchived)),
+				log.Int("rows_deleted", int(rowsDeleted)),
+				log.Time("cutoff", cutoff),
+			)
+		}
+	}()
+	for {
+		batch, err := c.Model.GetCullableTimers(ctx, cutoff, batchSize)
+		if err != nil {
+			logger.Error("controller; cull failed", log.Any("err", err))
+			return
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if err := c.Archiver.Write(batch); err != nil {
+			logger.Error("controller; cull archive failed", log.Any("err", err))
+			return
+		}
+		rowsArchived += int64(len(batch))
+		ids := make([]uuid.UUID, 0, len(batch))
+		for _, t := range batch {
+			ids = append(ids, t.ID)
+		}
+		deleted, err := c.Model.BulkDeleteTimers(ctx, ids)
+		if err != nil {
+			logger.Error("controller; cull failed", log.Any("err", err))
+			return
+		}
+		rowsDeleted += deleted
+		metricTimersCulled.Add(float64(deleted))
+		if len(batch) < batchSize {
+			break
+		}
+	}
+	if removed, err := c.Archiver.Prune(); err != nil {
+		logger.Error("controller; archive prune failed", log.Any("err", err))
+	} else if removed > 0 {
+		logger.Info("controller; archive pruned", log.Int("segments_removed", removed))
+	}
+}
+
 func (c *Controller) evaluate(ctx context.Context) {
 	logger := log.GetLogger(ctx)
-	now := time.Now().UTC()
+	now := c.clockOrDefault().Now().UTC()
 	evalInterval := c.Config.EvaluationIntervalOrDefault()
 	windowEnd := now.Add(evalInterval)
 	pollingInterval := c.Config.WorkerPollingIntervalOrDefault()
@@ -117,6 +241,17 @@ func (c *Controller) evaluate(ctx context.Context) {
 	if effectivePeak > 0 {
 		desiredReplicas = int32(math.Ceil(float64(effectivePeak) / float64(batchSize)))
 	}
+
+	// Workers near their wheel budgets claim less than their batch, so
+	// the due counts above overstate what the fleet can absorb; scale
+	// out until the mean occupancy would fall back to the target.
+	liveWorkers, occupancy, err := c.wheelOccupancy(ctx, now)
+	if err != nil {
+		logger.Error("controller; failed to get worker wheel occupancy", log.Any("err", err))
+	}
+	if target := c.Config.WheelOccupancyTargetOrDefault(); occupancy > target {
+		desiredReplicas = max(desiredReplicas, int32(math.Ceil(float64(liveWorkers)*occupancy/target)))
+	}
 	minReplicas := c.Config.MinReplicasOrDefault()
 	if desiredReplicas < minReplicas {
 		desiredReplicas = minReplicas
@@ -125,11 +260,19 @@ func (c *Controller) evaluate(ctx context.Context) {
 		desiredReplicas = c.Config.MaxReplicas
 	}
 
+	metricPeakTimers.Set(float64(peakCount))
+	metricOverdueTimers.Set(float64(overdueCount))
+	metricBacklogPerTick.Set(float64(backlogPerTick))
+	metricDesiredReplicas.Set(float64(desiredReplicas))
+	metricWheelOccupancy.Set(occupancy)
+
 	logger.Info("controller; evaluation complete",
 		log.Int("peak_timers", int(peakCount)),
 		log.Int("overdue_timers", int(overdueCount)),
 		log.Int("backlog_per_tick", int(backlogPerTick)),
 		log.Int("effective_peak", int(effectivePeak)),
+		log.Int("live_workers", liveWorkers),
+		log.Float64("wheel_occupancy", occupancy),
 		log.Int("desired_replicas", int(desiredReplicas)),
 		log.Int("batch_size", batchSize),
 		log.Duration("polling_interval", pollingInterval),
@@ -140,3 +283,21 @@ func (c *Controller) evaluate(ctx context.Context) {
 		logger.Error("controller; failed to set desired scale", log.Any("err", err))
 	}
 }
+
+// workerSeenWithin is how recently a worker must have heartbeated to
+// count as live, matching the workers' own shard assignment.
+const workerSeenWithin = 30 * time.Second
+
+// wheelOccupancy returns how many workers are live and their mean wheel
+// occupancy.
+func (c *Controller) wheelOccupancy(ctx context.Context, now time.Time) (live int, mean float64, err error) {
+	workers, err := c.Model.GetWorkers(ctx, now.Add(-workerSeenWithin))
+	if err != nil || len(workers) == 0 {
+		return
+	}
+	var total float64
+	for _, w := range workers {
+		total += w.WheelOccupancy
+	}
+	return len(workers), total / float64(len(workers)), nil
+}
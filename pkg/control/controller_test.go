@@ -0,0 +1,69 @@
+package control
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"sandman/pkg/assert"
+	"sandman/pkg/clock"
+	"sandman/pkg/model"
+)
+
+type recordingScaler struct {
+	desired []int32
+}
+
+func (s *recordingScaler) SetDesiredScale(_ context.Context, desired int32) error {
+	s.desired = append(s.desired, desired)
+	return nil
+}
+
+func createTimers(t *testing.T, store model.TimerStore, prefix string, count int, dueUTC time.Time) {
+	t.Helper()
+	for index := range count {
+		timer := model.Timer{Name: fmt.Sprintf("%s-%02d", prefix, index), DueUTC: dueUTC, HookURL: "http://localhost/hook"}
+		assert.Nil(t, store.CreateTimer(context.Background(), &timer))
+	}
+}
+
+func Test_Controller_evaluate(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	store := model.NewMemoryStore()
+	scaler := new(recordingScaler)
+	c := &Controller{
+		Config: Config{
+			EvaluationInterval:    10 * time.Second,
+			MinReplicas:           1,
+			WorkerBatchSize:       10,
+			WorkerPollingInterval: time.Second,
+		},
+		Model:  store,
+		Scaler: scaler,
+		Clock:  clk,
+	}
+
+	// 25 due in one tick plus 60 overdue, drained over the 10 ticks of
+	// the evaluation interval: 25 + 6 per tick is 4 workers' batches.
+	createTimers(t, store, "upcoming", 25, clk.Now().Add(5*time.Second))
+	createTimers(t, store, "overdue", 60, clk.Now().Add(-time.Minute))
+	c.evaluate(ctx)
+	assert.Equal(t, []int32{4}, scaler.desired)
+
+	// workers whose wheels are 90% full against a 75% target need 20%
+	// more of them.
+	for index := range 5 {
+		assert.Nil(t, store.WorkerSeen(ctx, fmt.Sprintf("worker-%02d", index), clk.Now(), 1, 0.9))
+	}
+	c.evaluate(ctx)
+	assert.Equal(t, []int32{4, 6}, scaler.desired)
+
+	// once the workers stop heartbeating their occupancy no longer
+	// counts, and the upcoming timers have joined the backlog: 85
+	// overdue is 9 per tick, within one batch.
+	clk.Advance(workerSeenWithin + 10*time.Second)
+	c.evaluate(ctx)
+	assert.Equal(t, []int32{4, 6, 1}, scaler.desired)
+}
@@ -0,0 +1,31 @@
+package control
+
+import "sandman/pkg/metrics"
+
+// Prometheus metrics, served on /metrics when metrics are enabled.
+var (
+	metricDesiredReplicas = metrics.NewGauge(
+		"sandman_control_desired_replicas",
+		"Worker replicas the last evaluation asked the scaler for.",
+	)
+	metricOverdueTimers = metrics.NewGauge(
+		"sandman_control_overdue_timers",
+		"Pending timers past due as of the last evaluation.",
+	)
+	metricBacklogPerTick = metrics.NewGauge(
+		"sandman_control_backlog_per_tick",
+		"Overdue timers each polling tick must absorb to drain the backlog within one evaluation interval.",
+	)
+	metricPeakTimers = metrics.NewGauge(
+		"sandman_control_peak_timers",
+		"Most timers due in any one polling interval over the next evaluation interval.",
+	)
+	metricWheelOccupancy = metrics.NewGauge(
+		"sandman_control_worker_wheel_occupancy",
+		"Mean wheel occupancy of the live workers as of the last evaluation.",
+	)
+	metricTimersCulled = metrics.NewCounter(
+		"sandman_control_timers_culled_total",
+		"Timers deleted by the cull sweep.",
+	)
+)
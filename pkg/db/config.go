@@ -73,7 +73,7 @@ const (
 	// pool size on a connection in a connection pool.
 	EnvVarDBBufferPoolSize = "DB_BUFFER_POOL_SIZE"
 	// EnvVarDBDialect is the environment variable used to set the dialect
-	// on a connection configuration (e.g. `postgres` or `cockroachdb`).
+	// on a connection configuration (e.g. `psql` or `cockroachdb`).
 	EnvVarDBDialect = "DB_DIALECT"
 )
 
@@ -199,6 +199,7 @@ type Config struct {
 	// BufferPoolSize is the number of query composition buffers to maintain.
 	BufferPoolSize int `json:"bufferPoolSize,omitempty" yaml:"bufferPoolSize,omitempty"`
 	// Dialect includes hints to tweak specific sql semantics by database connection.
+	// If unset it can be detected from the server with `Connection.DetectDialect`.
 	Dialect string `json:"dialect,omitempty" yaml:"dialect,omitempty"`
 	// LoadBalanceHosts, when true and when `Host` contains multiple comma-separated
 	// `host:port` entries, shuffles the order pgx tries them on every new connection
@@ -235,7 +236,7 @@ func (c *Config) Resolve(ctx context.Context) error {
 		configutil.Set(&c.MaxLifetime, configutil.Env[time.Duration](EnvVarDBMaxLifetime), configutil.Lazy(&c.MaxLifetime), configutil.Const(DefaultMaxLifetime)),
 		configutil.Set(&c.MaxIdleTime, configutil.Env[time.Duration](EnvVarDBMaxIdleTime), configutil.Lazy(&c.MaxIdleTime), configutil.Const(DefaultMaxIdleTime)),
 		configutil.Set(&c.BufferPoolSize, configutil.Env[int](EnvVarDBBufferPoolSize), configutil.Lazy(&c.BufferPoolSize), configutil.Const(DefaultBufferPoolSize)),
-		configutil.Set(&c.Dialect, configutil.Env[string](EnvVarDBDialect), configutil.Lazy(&c.Dialect), configutil.Const(string(DialectPostgres))),
+		configutil.Set(&c.Dialect, configutil.Env[string](EnvVarDBDialect), configutil.Lazy(&c.Dialect)),
 	)
 }
 
@@ -134,6 +134,14 @@ func (dbc *Connection) TypeMetaFromType(identifier string, t reflect.Type) *Type
 	return metadata
 }
 
+// Ping verifies the connection to the database is still alive.
+func (dbc *Connection) Ping(ctx context.Context) error {
+	if dbc.conn == nil {
+		return ErrConnectionClosed
+	}
+	return dbc.conn.PingContext(ctx)
+}
+
 // BeginTx starts a new transaction in a givent context.
 func (dbc *Connection) BeginTx(ctx context.Context, opts ...func(*sql.TxOptions)) (*sql.Tx, error) {
 	if dbc.conn == nil {
@@ -109,7 +109,8 @@ func dbTypeForFieldType(t reflect.Type) string {
 		arrayType := t.Elem()
 		switch arrayType.Kind() {
 		case reflect.Uint8:
-			return "BYTES"
+			// BYTEA rather than BYTES; cockroachdb accepts both.
+			return "BYTEA"
 		}
 	default:
 	}
@@ -1,6 +1,9 @@
 package db
 
-import "strings"
+import (
+	"context"
+	"strings"
+)
 
 // Dialect is the flavor of sql.
 type Dialect string
@@ -25,3 +28,36 @@ var (
 	// DialectRedshift is the redshift dialect.
 	DialectRedshift Dialect = "redshift"
 )
+
+// DialectFromVersion infers the dialect from a server's `version()`
+// string. Redshift and CockroachDB both report a PostgreSQL compatible
+// version alongside their own name, so they're checked first.
+func DialectFromVersion(version string) Dialect {
+	version = strings.ToLower(version)
+	switch {
+	case strings.Contains(version, "redshift"):
+		return DialectRedshift
+	case strings.Contains(version, "cockroachdb"), strings.Contains(version, "mikoshi"):
+		return DialectCockroachDB
+	case strings.Contains(version, "postgresql"):
+		return DialectPostgres
+	default:
+		return DialectUnknown
+	}
+}
+
+// DetectDialect sets the connection's dialect from the server's version
+// if the config doesn't already name one, and returns it.
+//
+// The connection must be open.
+func (dbc *Connection) DetectDialect(ctx context.Context) (Dialect, error) {
+	if dbc.Config.Dialect != "" {
+		return Dialect(dbc.Config.Dialect), nil
+	}
+	var version string
+	if err := dbc.conn.QueryRowContext(ctx, "SELECT version()").Scan(&version); err != nil {
+		return DialectUnknown, err
+	}
+	dbc.Config.Dialect = string(DialectFromVersion(version))
+	return Dialect(dbc.Config.Dialect), nil
+}
@@ -0,0 +1,114 @@
+// Package dnscache caches host lookups for outbound dials.
+//
+// The cache only resolves names; dialing still goes through the wrapped
+// dial function, so a net.Dialer control (e.g. the egress policy's
+// private address check) sees every address actually dialed, cached or
+// not.
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is how long `New` keeps a lookup when given a non-positive
+// ttl. The stdlib resolver doesn't expose record TTLs, so every entry
+// lives for the same fixed time.
+const DefaultTTL = 30 * time.Second
+
+// New returns a cache that keeps each successful lookup for ttl.
+func New(ttl time.Duration) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Cache{
+		ttl:    ttl,
+		lookup: net.DefaultResolver.LookupNetIP,
+		now:    time.Now,
+		hosts:  make(map[string]entry),
+	}
+}
+
+// Cache is a TTL cache of host lookups. Failed lookups aren't cached.
+type Cache struct {
+	ttl    time.Duration
+	lookup func(ctx context.Context, network, host string) ([]netip.Addr, error)
+	now    func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]entry
+}
+
+type entry struct {
+	addrs     []netip.Addr
+	expiresAt time.Time
+}
+
+// Lookup returns the addresses for host, from the cache if it resolved
+// within the ttl. IP literals are returned as is.
+func (c *Cache) Lookup(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+	now := c.now()
+	c.mu.Lock()
+	cached, ok := c.hosts[host]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.addrs, nil
+	}
+	addrs, err := c.lookup(ctx, "ip", host)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.hosts[host] = entry{addrs: addrs, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return addrs, nil
+}
+
+// Len returns how many hosts are cached, including expired entries not
+// yet replaced.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.hosts)
+}
+
+// DialFunc is the signature of `net.Dialer.DialContext` and
+// `http.Transport.DialContext`.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// DialContext wraps dial so the host of each address is resolved through
+// the cache, then dialed address by address until one connects.
+func (c *Cache) DialContext(dial DialFunc) DialFunc {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		addrs, err := c.Lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var errs []error
+		for _, addr := range addrs {
+			conn, err := dial(ctx, network, net.JoinHostPort(addr.Unmap().String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if len(errs) == 0 {
+			return nil, &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}
+		}
+		return nil, errors.Join(errs...)
+	}
+}
@@ -0,0 +1,114 @@
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"sandman/pkg/egress"
+)
+
+func testCache(addrs ...string) (*Cache, *int, *time.Time) {
+	var lookups int
+	now := time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)
+	c := New(time.Minute)
+	c.now = func() time.Time { return now }
+	c.lookup = func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		lookups++
+		if host == "missing.example.com" {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		var output []netip.Addr
+		for _, raw := range addrs {
+			output = append(output, netip.MustParseAddr(raw))
+		}
+		return output, nil
+	}
+	return c, &lookups, &now
+}
+
+func TestCache_Lookup_cachesWithinTTL(t *testing.T) {
+	c, lookups, now := testCache("203.0.113.10")
+	for range 3 {
+		addrs, err := c.Lookup(context.Background(), "hooks.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(addrs) != 1 || addrs[0] != netip.MustParseAddr("203.0.113.10") {
+			t.Fatalf("unexpected addrs %v", addrs)
+		}
+	}
+	if *lookups != 1 {
+		t.Fatalf("expected 1 lookup, got %d", *lookups)
+	}
+	*now = now.Add(time.Minute)
+	if _, err := c.Lookup(context.Background(), "hooks.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if *lookups != 2 {
+		t.Fatalf("expected a fresh lookup once the ttl passed, got %d lookups", *lookups)
+	}
+}
+
+func TestCache_Lookup_doesNotCacheFailures(t *testing.T) {
+	c, lookups, _ := testCache()
+	for range 2 {
+		if _, err := c.Lookup(context.Background(), "missing.example.com"); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if *lookups != 2 {
+		t.Fatalf("expected 2 lookups, got %d", *lookups)
+	}
+	if _, err := c.Lookup(context.Background(), "192.0.2.1"); err != nil {
+		t.Fatal(err)
+	}
+	if *lookups != 2 {
+		t.Fatalf("expected IP literals to skip the lookup, got %d lookups", *lookups)
+	}
+}
+
+func TestCache_DialContext_dialsEachAddress(t *testing.T) {
+	c, _, _ := testCache("203.0.113.10", "203.0.113.11")
+	refused := errors.New("refused")
+	var dialed []string
+	dial := c.DialContext(func(_ context.Context, _, address string) (net.Conn, error) {
+		dialed = append(dialed, address)
+		if address == "203.0.113.10:443" {
+			return nil, refused
+		}
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	})
+	conn, err := dial(context.Background(), "tcp", "hooks.example.com:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+	if len(dialed) != 2 || dialed[1] != "203.0.113.11:443" {
+		t.Fatalf("expected a fallback to the second address, dialed %v", dialed)
+	}
+
+	dial = c.DialContext(func(context.Context, string, string) (net.Conn, error) {
+		return nil, refused
+	})
+	if _, err := dial(context.Background(), "tcp", "hooks.example.com:443"); !errors.Is(err, refused) {
+		t.Fatalf("expected the dial error, got %v", err)
+	}
+}
+
+func TestCache_DialContext_keepsDialerControl(t *testing.T) {
+	c, _, _ := testCache("10.0.0.5")
+	policy, err := egress.New(egress.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dial := c.DialContext((&net.Dialer{Control: policy.Control}).DialContext)
+	if _, err := dial(context.Background(), "tcp", "internal.example.com:443"); !errors.Is(err, egress.ErrAddressNotAllowed) {
+		t.Fatalf("expected the egress policy to reject the cached address, got %v", err)
+	}
+}
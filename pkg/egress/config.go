@@ -0,0 +1,33 @@
+package egress
+
+// Config is the egress policy for timer hooks.
+//
+// Host patterns are matched with stringutil.Glob against the url's
+// hostname (no port), e.g. "*.internal.example.com" or "hooks.*".
+// Address overrides are CIDRs (or bare IPs) that are allowed even though
+// they fall in a private, loopback or link-local range.
+type Config struct {
+	// AllowedSchemes defaults to http and https when empty.
+	AllowedSchemes []string `yaml:"allowed_schemes,omitempty"`
+	// AllowedHosts, when non-empty, is the set of host patterns hooks
+	// may target; anything else is rejected at create time.
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty"`
+	// DeniedHosts are host patterns that are always rejected, even if
+	// they also match AllowedHosts.
+	DeniedHosts []string `yaml:"denied_hosts,omitempty"`
+	// AllowedAddresses are CIDRs exempt from the private address check
+	// applied when the worker dials a resolved address.
+	AllowedAddresses []string `yaml:"allowed_addresses,omitempty"`
+}
+
+// DefaultAllowedSchemes are the schemes allowed when
+// Config.AllowedSchemes is empty.
+var DefaultAllowedSchemes = []string{"http", "https"}
+
+// AllowedSchemesOrDefault returns the allowed schemes or a default.
+func (c Config) AllowedSchemesOrDefault() []string {
+	if len(c.AllowedSchemes) > 0 {
+		return c.AllowedSchemes
+	}
+	return DefaultAllowedSchemes
+}
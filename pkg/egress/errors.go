@@ -0,0 +1,21 @@
+package egress
+
+// Error is a hard alias to string.
+type Error string
+
+// Error implements `error`
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrSchemeNotAllowed is returned for hook urls with a scheme outside
+	// the allowed set.
+	ErrSchemeNotAllowed Error = "egress; scheme not allowed"
+	// ErrHostNotAllowed is returned for hook urls whose host matches a
+	// denied pattern or misses every allowed pattern.
+	ErrHostNotAllowed Error = "egress; host not allowed"
+	// ErrAddressNotAllowed is returned when a hook resolves to (or is) a
+	// private, loopback or link-local address that isn't overridden.
+	ErrAddressNotAllowed Error = "egress; address not allowed"
+)
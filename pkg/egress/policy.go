@@ -0,0 +1,155 @@
+// Package egress enforces where timer hooks are allowed to send requests.
+//
+// A Policy is checked in two places: when a timer is created (scheme
+// and host patterns, plus literal IPs) and when the worker actually
+// dials (the resolved address), so DNS names that point at internal
+// addresses and redirects to them are caught too.
+package egress
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+
+	"sandman/pkg/stringutil"
+)
+
+// New returns a new policy from a config, parsing the address overrides.
+func New(cfg Config) (*Policy, error) {
+	p := &Policy{
+		cfg: cfg,
+	}
+	for _, raw := range cfg.AllowedAddresses {
+		prefix, err := parsePrefix(raw)
+		if err != nil {
+			return nil, fmt.Errorf("egress; invalid allowed address %q: %w", raw, err)
+		}
+		p.allowedAddresses = append(p.allowedAddresses, prefix)
+	}
+	return p, nil
+}
+
+// Policy decides whether a hook url or dialed address is permitted.
+//
+// A nil *Policy allows everything, so callers can thread an optional
+// policy through without nil checks.
+type Policy struct {
+	cfg              Config
+	allowedAddresses []netip.Prefix
+}
+
+// CheckURL validates a hook url's scheme and host against the policy.
+// Literal IP hosts are also run through CheckAddr so obviously internal
+// targets are rejected at create time rather than on every attempt.
+func (p *Policy) CheckURL(rawURL string) error {
+	if p == nil {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if !containsFold(p.cfg.AllowedSchemesOrDefault(), scheme) {
+		return fmt.Errorf("%w: %q", ErrSchemeNotAllowed, u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: url has no host", ErrHostNotAllowed)
+	}
+	if stringutil.GlobAny(host, p.cfg.DeniedHosts...) {
+		return fmt.Errorf("%w: %q is denied", ErrHostNotAllowed, host)
+	}
+	if len(p.cfg.AllowedHosts) > 0 && !stringutil.GlobAny(host, p.cfg.AllowedHosts...) {
+		return fmt.Errorf("%w: %q is not in the allow list", ErrHostNotAllowed, host)
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.CheckAddr(addr)
+	}
+	return nil
+}
+
+// CheckAddr rejects private, carrier-grade NAT, NAT64, loopback,
+// link-local and unspecified addresses unless they fall inside one of
+// the allowed overrides.
+func (p *Policy) CheckAddr(addr netip.Addr) error {
+	if p == nil {
+		return nil
+	}
+	addr = addr.Unmap()
+	for _, prefix := range p.allowedAddresses {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	if isInternal(addr) {
+		return fmt.Errorf("%w: %s", ErrAddressNotAllowed, addr)
+	}
+	return nil
+}
+
+// Control is a net.Dialer control function that applies CheckAddr to
+// the address actually being dialed, i.e. after DNS resolution.
+func (p *Policy) Control(network, address string, _ syscall.RawConn) error {
+	if p == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot parse dialed address %q", ErrAddressNotAllowed, address)
+	}
+	return p.CheckAddr(addr)
+}
+
+// internalPrefixes are internal ranges netip has no predicate for:
+// carrier-grade NAT (RFC 6598) and the NAT64 well-known prefix (RFC
+// 6052), which translates to IPv4 addresses behind the gateway.
+var internalPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+func isInternal(addr netip.Addr) bool {
+	for _, prefix := range internalPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return addr.IsPrivate() ||
+		addr.IsLoopback() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsUnspecified()
+}
+
+func parsePrefix(raw string) (netip.Prefix, error) {
+	if strings.Contains(raw, "/") {
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(raw)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package egress
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestCheckURL(t *testing.T) {
+	p, err := New(Config{
+		AllowedHosts:     []string{"*.example.com", "hooks.partner.io", "10.1.2.3"},
+		DeniedHosts:      []string{"admin.example.com"},
+		AllowedAddresses: []string{"10.1.2.0/24"},
+	})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	testCases := []struct {
+		url  string
+		want error
+	}{
+		{"https://api.example.com/hook", nil},
+		{"http://hooks.partner.io:8080/x", nil},
+		{"https://API.Example.com/hook", nil},
+		{"ftp://api.example.com/hook", ErrSchemeNotAllowed},
+		{"https://admin.example.com/hook", ErrHostNotAllowed},
+		{"https://evil.com/hook", ErrHostNotAllowed},
+		{"https:///no-host", ErrHostNotAllowed},
+		{"http://10.1.2.3/hook", nil},
+	}
+	for _, tc := range testCases {
+		err := p.CheckURL(tc.url)
+		if tc.want == nil && err != nil {
+			t.Errorf("%s: expected allowed, got %v", tc.url, err)
+		}
+		if tc.want != nil && !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.url, tc.want, err)
+		}
+	}
+}
+
+func TestCheckURL_literalInternalAddresses(t *testing.T) {
+	p, err := New(Config{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	for _, u := range []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://127.0.0.1:8080/",
+		"http://[::1]/",
+		"http://192.168.1.10/",
+		"http://0.0.0.0/",
+		"http://100.64.0.1/",
+		"http://100.100.100.200/latest/meta-data",
+		"http://[64:ff9b::a9fe:a9fe]/",
+		"http://[64:ff9b::5db8:d822]/",
+	} {
+		if err := p.CheckURL(u); !errors.Is(err, ErrAddressNotAllowed) {
+			t.Errorf("%s: expected ErrAddressNotAllowed, got %v", u, err)
+		}
+	}
+	if err := p.CheckURL("https://93.184.216.34/"); err != nil {
+		t.Errorf("public literal ip: expected allowed, got %v", err)
+	}
+	if err := p.CheckURL("https://100.128.0.1/"); err != nil {
+		t.Errorf("literal ip past the cgnat range: expected allowed, got %v", err)
+	}
+}
+
+func TestControl(t *testing.T) {
+	p, err := New(Config{AllowedAddresses: []string{"127.0.0.1"}})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := p.Control("tcp4", "127.0.0.1:8080", nil); err != nil {
+		t.Errorf("overridden loopback: expected allowed, got %v", err)
+	}
+	if err := p.Control("tcp4", "127.0.0.2:8080", nil); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("loopback outside override: expected ErrAddressNotAllowed, got %v", err)
+	}
+	if err := p.Control("tcp6", "[fe80::1]:443", nil); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("link-local v6: expected ErrAddressNotAllowed, got %v", err)
+	}
+	if err := p.Control("tcp4", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("public address: expected allowed, got %v", err)
+	}
+}
+
+func TestNilPolicyAllowsEverything(t *testing.T) {
+	var p *Policy
+	if err := p.CheckURL("http://169.254.169.254/"); err != nil {
+		t.Errorf("expected nil policy to allow, got %v", err)
+	}
+	if err := p.CheckAddr(netip.MustParseAddr("127.0.0.1")); err != nil {
+		t.Errorf("expected nil policy to allow, got %v", err)
+	}
+}
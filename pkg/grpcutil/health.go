@@ -0,0 +1,45 @@
+package grpcutil
+
+import (
+	"context"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"sandman/pkg/log"
+)
+
+// HealthInterval is how often MonitorHealth refreshes the gRPC health
+// statuses.
+const HealthInterval = 5 * time.Second
+
+// MonitorHealth keeps the gRPC health statuses of the given services in
+// line with whether ping succeeds, e.g. whether the DB is reachable,
+// until ctx is done. Once the health server is shut down (drained) its
+// statuses are frozen at NOT_SERVING and these updates are ignored.
+func MonitorHealth(ctx context.Context, ping func(context.Context) error, hs *grpchealth.Server, services []string, timeout time.Duration) {
+	check := func() {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		status := healthpb.HealthCheckResponse_SERVING
+		if err := ping(pingCtx); err != nil {
+			log.GetLogger(ctx).Error("health; ping failed", log.Any("err", err))
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		for _, service := range services {
+			hs.SetServingStatus(service, status)
+		}
+	}
+	check()
+	tick := time.NewTicker(HealthInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			check()
+		}
+	}
+}
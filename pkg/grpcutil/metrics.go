@@ -0,0 +1,45 @@
+package grpcutil
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"sandman/pkg/metrics"
+)
+
+// metricHandlingSeconds is RPC latency by method and status code,
+// served on /metrics when metrics are enabled.
+var metricHandlingSeconds = metrics.NewHistogramVec(
+	"sandman_grpc_server_handling_seconds",
+	"RPC latency in seconds by full method and status code; for streams, the life of the stream.",
+	metrics.DefaultBuckets,
+	"method", "code",
+)
+
+// Metrics returns a unary server interceptor that records RPC latency.
+func Metrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, args interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		started := time.Now()
+		result, err := handler(ctx, args)
+		observeRPC(info.FullMethod, err, time.Since(started))
+		return result, err
+	}
+}
+
+// StreamMetrics returns a stream server interceptor that records RPC
+// latency.
+func StreamMetrics() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		started := time.Now()
+		err := handler(srv, ss)
+		observeRPC(info.FullMethod, err, time.Since(started))
+		return err
+	}
+}
+
+func observeRPC(method string, err error, elapsed time.Duration) {
+	metricHandlingSeconds.WithLabelValues(method, status.Code(err).String()).ObserveDuration(elapsed)
+}
@@ -0,0 +1,39 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"sandman/pkg/configutil"
+)
+
+// Config configures the health endpoints.
+type Config struct {
+	// BindAddr is where /healthz, /readyz and /drain are served. Empty
+	// disables the endpoints.
+	BindAddr string `yaml:"bindAddr"`
+	// CheckTimeout bounds each individual check.
+	CheckTimeout time.Duration `yaml:"checkTimeout"`
+}
+
+// DefaultCheckTimeout is the default per check timeout.
+const DefaultCheckTimeout = 2 * time.Second
+
+// Resolve resolves the config.
+func (c *Config) Resolve(ctx context.Context) error {
+	return configutil.Resolve(ctx,
+		configutil.Set(&c.BindAddr, configutil.Lazy(&c.BindAddr), configutil.Env[string]("HEALTH_BIND_ADDR")),
+	)
+}
+
+// IsEnabled returns if the health endpoints should be served.
+func (c Config) IsEnabled() bool {
+	return c.BindAddr != ""
+}
+
+func (c Config) CheckTimeoutOrDefault() time.Duration {
+	if c.CheckTimeout > 0 {
+		return c.CheckTimeout
+	}
+	return DefaultCheckTimeout
+}
@@ -0,0 +1,15 @@
+package health
+
+// Error is a hard alias to string.
+type Error string
+
+// Error implements `error`
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrDraining is reported by the readiness probe once a drain has
+	// been requested.
+	ErrDraining Error = "health; draining"
+)
@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check reports an error if whatever it checks is unhealthy.
+type Check func(context.Context) error
+
+// Option mutates a handler.
+type Option func(*Handler)
+
+// OptLiveness adds a check to /healthz. A failing liveness check means
+// the process is wedged and should be restarted.
+func OptLiveness(name string, check Check) Option {
+	return func(h *Handler) {
+		h.liveness = append(h.liveness, namedCheck{name: name, check: check})
+	}
+}
+
+// OptReadiness adds a check to /readyz. A failing readiness check means
+// the process is up but shouldn't be sent work right now.
+func OptReadiness(name string, check Check) Option {
+	return func(h *Handler) {
+		h.readiness = append(h.readiness, namedCheck{name: name, check: check})
+	}
+}
+
+// OptDrain sets the function `POST /drain` triggers. It is called at
+// most once and must not block; readiness fails from the moment a drain
+// is requested.
+func OptDrain(drain func()) Option {
+	return func(h *Handler) {
+		h.drain = drain
+	}
+}
+
+// OptCheckTimeout bounds each individual check.
+func OptCheckTimeout(d time.Duration) Option {
+	return func(h *Handler) {
+		h.checkTimeout = d
+	}
+}
+
+// New returns a new handler.
+func New(opts ...Option) *Handler {
+	h := new(Handler)
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Handler serves the liveness, readiness and drain endpoints:
+//
+//	GET  /healthz  200 if every liveness check passes, 503 otherwise
+//	GET  /readyz   200 if every readiness check passes and the process
+//	               isn't draining, 503 otherwise
+//	POST /drain    starts a drain, 202
+//
+// Failing probes list each failed check as `<name>: <err>` in the body.
+type Handler struct {
+	liveness     []namedCheck
+	readiness    []namedCheck
+	drain        func()
+	checkTimeout time.Duration
+
+	drainOnce sync.Once
+	draining  atomic.Bool
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Register adds the handler's routes to the mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", h.handleHealthz)
+	mux.HandleFunc("/readyz", h.handleReadyz)
+	mux.HandleFunc("/drain", h.handleDrain)
+}
+
+// Drain starts a drain as if `POST /drain` was called.
+func (h *Handler) Drain() {
+	h.drainOnce.Do(func() {
+		h.draining.Store(true)
+		if h.drain != nil {
+			h.drain()
+		}
+	})
+}
+
+// Draining returns if a drain has been requested.
+func (h *Handler) Draining() bool {
+	return h.draining.Load()
+}
+
+func (h *Handler) handleHealthz(rw http.ResponseWriter, req *http.Request) {
+	h.writeResult(rw, h.run(req.Context(), h.liveness))
+}
+
+func (h *Handler) handleReadyz(rw http.ResponseWriter, req *http.Request) {
+	if h.Draining() {
+		h.writeResult(rw, []string{ErrDraining.Error()})
+		return
+	}
+	h.writeResult(rw, h.run(req.Context(), h.readiness))
+}
+
+func (h *Handler) handleDrain(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h.Drain()
+	rw.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprintln(rw, "draining")
+}
+
+// run runs the checks concurrently and returns a line per failure.
+func (h *Handler) run(ctx context.Context, checks []namedCheck) []string {
+	timeout := h.checkTimeout
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	failures := make([]string, len(checks))
+	var wg sync.WaitGroup
+	for index, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			if err := c.check(checkCtx); err != nil {
+				failures[index] = fmt.Sprintf("%s: %v", c.name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	var output []string
+	for _, f := range failures {
+		if f != "" {
+			output = append(output, f)
+		}
+	}
+	return output
+}
+
+func (h *Handler) writeResult(rw http.ResponseWriter, failures []string) {
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if len(failures) > 0 {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintln(rw, strings.Join(failures, "\n"))
+		return
+	}
+	rw.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintln(rw, "ok")
+}
@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func probe(t *testing.T, mux *http.ServeMux, method, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestHandler_probes(t *testing.T) {
+	var dbErr error
+	h := New(
+		OptLiveness("loop", func(context.Context) error { return nil }),
+		OptReadiness("db", func(context.Context) error { return dbErr }),
+	)
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	if code, _ := probe(t, mux, http.MethodGet, "/healthz"); code != http.StatusOK {
+		t.Fatalf("healthz: got %d", code)
+	}
+	if code, _ := probe(t, mux, http.MethodGet, "/readyz"); code != http.StatusOK {
+		t.Fatalf("readyz: got %d", code)
+	}
+
+	dbErr = errors.New("connection refused")
+	code, body := probe(t, mux, http.MethodGet, "/readyz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "db: connection refused") {
+		t.Fatalf("readyz with failing db: got %d %q", code, body)
+	}
+	if code, _ := probe(t, mux, http.MethodGet, "/healthz"); code != http.StatusOK {
+		t.Fatalf("readiness failures must not fail liveness, got %d", code)
+	}
+}
+
+func TestHandler_checkTimeout(t *testing.T) {
+	h := New(
+		OptCheckTimeout(10*time.Millisecond),
+		OptLiveness("hung", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	mux := http.NewServeMux()
+	h.Register(mux)
+	code, body := probe(t, mux, http.MethodGet, "/healthz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, "hung: context deadline exceeded") {
+		t.Fatalf("healthz with hung check: got %d %q", code, body)
+	}
+}
+
+func TestHandler_drain(t *testing.T) {
+	var drains int
+	h := New(OptDrain(func() { drains++ }))
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	if code, _ := probe(t, mux, http.MethodGet, "/drain"); code != http.StatusMethodNotAllowed {
+		t.Fatalf("GET /drain: got %d", code)
+	}
+	if drains != 0 || h.Draining() {
+		t.Fatal("GET /drain must not start a drain")
+	}
+	for range 2 {
+		if code, _ := probe(t, mux, http.MethodPost, "/drain"); code != http.StatusAccepted {
+			t.Fatalf("POST /drain: got %d", code)
+		}
+	}
+	if drains != 1 {
+		t.Fatalf("expected the drain func to run once, ran %d times", drains)
+	}
+	code, body := probe(t, mux, http.MethodGet, "/readyz")
+	if code != http.StatusServiceUnavailable || !strings.Contains(body, ErrDraining.Error()) {
+		t.Fatalf("readyz while draining: got %d %q", code, body)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Package hook helps http receivers handle sandman timer deliveries.
+
+Delivery is at-least-once; a worker that fails after sending a hook but
+before recording it as delivered will send it again. Every delivery
+carries the timer id, attempt and due time as headers along with an
+`Idempotency-Key` that is stable across attempts, and `Middleware` uses
+that key to drop duplicates before they reach your handler.
+*/
+package hook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sandman/pkg/uuid"
+)
+
+// Delivery headers sent by sandman workers with every hook.
+const (
+	HeaderTimerID        = "Sandman-Timer-Id"
+	HeaderAttempt        = "Sandman-Attempt"
+	HeaderDueUTC         = "Sandman-Due-UTC"
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderSignature      = "Sandman-Signature"
+)
+
+// Delivery is the sandman metadata attached to a hook request.
+type Delivery struct {
+	TimerID uuid.UUID
+	// Attempt is the 1-based claim attempt that sent this request.
+	Attempt        uint32
+	DueUTC         time.Time
+	IdempotencyKey string
+}
+
+// Header writes the delivery metadata to the given header.
+func (d Delivery) Header(h http.Header) {
+	h.Set(HeaderTimerID, d.TimerID.String())
+	h.Set(HeaderAttempt, strconv.FormatUint(uint64(d.Attempt), 10))
+	h.Set(HeaderDueUTC, d.DueUTC.UTC().Format(time.RFC3339Nano))
+	h.Set(HeaderIdempotencyKey, d.IdempotencyKey)
+}
+
+// ParseDelivery reads the delivery metadata from a request's headers.
+func ParseDelivery(r *http.Request) (d Delivery, err error) {
+	for _, key := range []string{HeaderTimerID, HeaderAttempt, HeaderDueUTC, HeaderIdempotencyKey} {
+		if r.Header.Get(key) == "" {
+			err = fmt.Errorf("%w; %s", ErrMissingHeader, key)
+			return
+		}
+	}
+	d.TimerID, err = uuid.Parse(r.Header.Get(HeaderTimerID))
+	if err != nil {
+		err = fmt.Errorf("%w; %s: %v", ErrInvalidHeader, HeaderTimerID, err)
+		return
+	}
+	attempt, err := strconv.ParseUint(r.Header.Get(HeaderAttempt), 10, 32)
+	if err != nil {
+		err = fmt.Errorf("%w; %s: %v", ErrInvalidHeader, HeaderAttempt, err)
+		return
+	}
+	d.Attempt = uint32(attempt)
+	d.DueUTC, err = time.Parse(time.RFC3339Nano, r.Header.Get(HeaderDueUTC))
+	if err != nil {
+		err = fmt.Errorf("%w; %s: %v", ErrInvalidHeader, HeaderDueUTC, err)
+		return
+	}
+	d.IdempotencyKey = r.Header.Get(HeaderIdempotencyKey)
+	return
+}
+
+type deliveryKey struct{}
+
+// WithDelivery adds a delivery to a context.
+func WithDelivery(ctx context.Context, d Delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, d)
+}
+
+// GetDelivery returns the delivery the middleware attached to a request
+// context, if any.
+func GetDelivery(ctx context.Context) (d Delivery, ok bool) {
+	d, ok = ctx.Value(deliveryKey{}).(Delivery)
+	return
+}
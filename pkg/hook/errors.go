@@ -0,0 +1,21 @@
+package hook
+
+// Error is a hard alias to string.
+type Error string
+
+// Error implements `error`
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrMissingHeader is returned when a delivery is missing one of the
+	// sandman metadata headers.
+	ErrMissingHeader Error = "hook; missing delivery header"
+	// ErrInvalidHeader is returned when a delivery header is present but
+	// cannot be parsed.
+	ErrInvalidHeader Error = "hook; invalid delivery header"
+	// ErrInvalidSignature is returned when a signature is required and
+	// is missing, malformed, stale, or does not match the body.
+	ErrInvalidSignature Error = "hook; invalid signature"
+)
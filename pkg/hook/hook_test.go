@@ -0,0 +1,192 @@
+package hook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sandman/pkg/assert"
+	"sandman/pkg/uuid"
+)
+
+func newDeliveryRequest(t *testing.T, d Delivery, body string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	d.Header(req.Header)
+	return req
+}
+
+func testDelivery() Delivery {
+	id := uuid.V4()
+	return Delivery{
+		TimerID:        id,
+		Attempt:        2,
+		DueUTC:         time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		IdempotencyKey: id.String(),
+	}
+}
+
+func Test_ParseDelivery(t *testing.T) {
+	d := testDelivery()
+	parsed, err := ParseDelivery(newDeliveryRequest(t, d, ""))
+	assert.Nil(t, err)
+	assert.Equal(t, d, parsed)
+
+	req := newDeliveryRequest(t, d, "")
+	req.Header.Del(HeaderIdempotencyKey)
+	_, err = ParseDelivery(req)
+	assert.True(t, errors.Is(err, ErrMissingHeader))
+
+	req = newDeliveryRequest(t, d, "")
+	req.Header.Set(HeaderAttempt, "not-a-number")
+	_, err = ParseDelivery(req)
+	assert.True(t, errors.Is(err, ErrInvalidHeader))
+}
+
+func Test_SignVerify(t *testing.T) {
+	secret := []byte("hunter2")
+	now := time.Now()
+	body := []byte(`{"hello":"world"}`)
+	sig := Sign(secret, now, body)
+
+	assert.Nil(t, Verify(secret, sig, body, now, 0))
+	assert.Nil(t, Verify(secret, sig, body, now.Add(time.Minute), 0))
+	assert.True(t, errors.Is(Verify(secret, sig, []byte(`{}`), now, 0), ErrInvalidSignature))
+	assert.True(t, errors.Is(Verify([]byte("wrong"), sig, body, now, 0), ErrInvalidSignature))
+	assert.True(t, errors.Is(Verify(secret, sig, body, now.Add(time.Hour), 0), ErrInvalidSignature))
+	assert.True(t, errors.Is(Verify(secret, "garbage", body, now, 0), ErrInvalidSignature))
+}
+
+func Test_LRUStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUStore(2)
+
+	state, _ := s.Claim(ctx, "a")
+	assert.Equal(t, ClaimAcquired, state)
+	state, _ = s.Claim(ctx, "a")
+	assert.Equal(t, ClaimInFlight, state)
+	_ = s.Complete(ctx, "a")
+	state, _ = s.Claim(ctx, "a")
+	assert.Equal(t, ClaimDone, state)
+
+	_, _ = s.Claim(ctx, "b")
+	_ = s.Complete(ctx, "b")
+	_, _ = s.Claim(ctx, "a") // touch "a" so "b" is the eviction candidate
+	_, _ = s.Claim(ctx, "c")
+	_ = s.Complete(ctx, "c")
+	assert.Equal(t, 2, s.Len())
+
+	state, _ = s.Claim(ctx, "b")
+	assert.Equal(t, ClaimAcquired, state, "b should have been evicted")
+
+	_ = s.Release(ctx, "b")
+	_ = s.Release(ctx, "c")
+	state, _ = s.Claim(ctx, "c")
+	assert.Equal(t, ClaimAcquired, state, "c should have been released")
+	state, _ = s.Claim(ctx, "b")
+	assert.Equal(t, ClaimAcquired, state, "b should have been released while in flight")
+}
+
+func Test_Middleware_dedupes(t *testing.T) {
+	var calls int
+	h := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		calls++
+		d, ok := GetDelivery(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, uint32(2), d.Attempt)
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	d := testDelivery()
+	for range 3 {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, newDeliveryRequest(t, d, "body"))
+		assert.True(t, rec.Code >= 200 && rec.Code < 300)
+	}
+	assert.Equal(t, 1, calls)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/hook", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func Test_Middleware_inFlight(t *testing.T) {
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	h := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+
+	d := testDelivery()
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(first, newDeliveryRequest(t, d, ""))
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newDeliveryRequest(t, d, ""))
+	assert.Equal(t, http.StatusConflict, rec.Code, "a duplicate of an in-flight delivery isn't acknowledged")
+
+	close(finish)
+	<-done
+	assert.Equal(t, http.StatusNoContent, first.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDeliveryRequest(t, d, ""))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func Test_Middleware_releasesOnFailure(t *testing.T) {
+	var calls int
+	h := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(rw, "try again", http.StatusInternalServerError)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+
+	d := testDelivery()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newDeliveryRequest(t, d, ""))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, newDeliveryRequest(t, d, ""))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func Test_Middleware_verifiesSignature(t *testing.T) {
+	secret := []byte("hunter2")
+	var gotBody string
+	h := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		gotBody = string(raw)
+	}), OptSecret(secret))
+
+	d := testDelivery()
+	req := newDeliveryRequest(t, d, "payload")
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), []byte("payload")))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "payload", gotBody)
+
+	req = newDeliveryRequest(t, testDelivery(), "tampered")
+	req.Header.Set(HeaderSignature, Sign(secret, time.Now(), []byte("payload")))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
@@ -0,0 +1,158 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// MiddlewareOption mutates middleware options.
+type MiddlewareOption func(*middleware)
+
+// OptSecret requires every delivery to carry a valid signature made with
+// the given secret.
+func OptSecret(secret []byte) MiddlewareOption {
+	return func(m *middleware) {
+		m.secret = secret
+	}
+}
+
+// OptSignatureTolerance sets how far a signature timestamp may drift.
+func OptSignatureTolerance(tolerance time.Duration) MiddlewareOption {
+	return func(m *middleware) {
+		m.tolerance = tolerance
+	}
+}
+
+// OptStore sets the dedupe store; the default is an in-memory LRU.
+func OptStore(store Store) MiddlewareOption {
+	return func(m *middleware) {
+		m.store = store
+	}
+}
+
+// OptMaxBodyBytes caps how much of a request body is read to verify a
+// signature.
+func OptMaxBodyBytes(maxBytes int64) MiddlewareOption {
+	return func(m *middleware) {
+		m.maxBodyBytes = maxBytes
+	}
+}
+
+// DefaultMaxBodyBytes is the default signature verification body cap.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Middleware wraps a handler that receives sandman hooks.
+//
+// Requests without delivery headers are rejected with 400, and requests
+// with a bad signature (when a secret is set) with 401. A delivery whose
+// idempotency key was already handled is acknowledged with 200 without
+// calling `next`, and one whose key is still being handled is answered
+// with 409 so the worker retries it later. If `next` responds with a
+// non-2xx status the key is released so the worker's retry is handled.
+//
+// The parsed delivery is available to `next` via `GetDelivery`.
+func Middleware(next http.Handler, opts ...MiddlewareOption) http.Handler {
+	m := &middleware{
+		next:         next,
+		maxBodyBytes: DefaultMaxBodyBytes,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.store == nil {
+		m.store = NewLRUStore(0)
+	}
+	return m
+}
+
+type middleware struct {
+	next         http.Handler
+	secret       []byte
+	tolerance    time.Duration
+	store        Store
+	maxBodyBytes int64
+	now          func() time.Time
+}
+
+func (m *middleware) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	delivery, err := ParseDelivery(r)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(m.secret) > 0 {
+		body, err := io.ReadAll(io.LimitReader(r.Body, m.maxBodyBytes+1))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) > m.maxBodyBytes {
+			http.Error(rw, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if err := Verify(m.secret, r.Header.Get(HeaderSignature), body, m.now(), m.tolerance); err != nil {
+			http.Error(rw, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	ctx := r.Context()
+	state, err := m.store.Claim(ctx, delivery.IdempotencyKey)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	switch state {
+	case ClaimDone:
+		rw.WriteHeader(http.StatusOK)
+		return
+	case ClaimInFlight:
+		http.Error(rw, "delivery in flight", http.StatusConflict)
+		return
+	}
+
+	// the key is completed or released even if the request is canceled
+	// mid delivery, else it'd be stuck in flight.
+	storeCtx := context.WithoutCancel(ctx)
+	sw := &statusWriter{ResponseWriter: rw, status: http.StatusOK}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = m.store.Release(storeCtx, delivery.IdempotencyKey)
+			panic(p)
+		}
+		if sw.status < 200 || sw.status > 299 {
+			_ = m.store.Release(storeCtx, delivery.IdempotencyKey)
+			return
+		}
+		_ = m.store.Complete(storeCtx, delivery.IdempotencyKey)
+	}()
+	m.next.ServeHTTP(sw, r.WithContext(WithDelivery(ctx, delivery)))
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	if !sw.wroteHeader {
+		sw.status = status
+		sw.wroteHeader = true
+	}
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+func (sw *statusWriter) Write(p []byte) (int, error) {
+	sw.wroteHeader = true
+	return sw.ResponseWriter.Write(p)
+}
+
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
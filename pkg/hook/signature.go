@@ -0,0 +1,76 @@
+package hook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSignatureTolerance is how far a signature timestamp may drift
+// from the receiver's clock before the request is rejected.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// Sign returns a `Sandman-Signature` header value for a body sent at a
+// given time, in the form `t=<unix seconds>,v1=<hex hmac-sha256>`.
+//
+// The mac covers `<unix seconds>.<body>` so a captured request can't be
+// replayed outside the tolerance window.
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac(secret, ts, body)))
+}
+
+// Verify checks a `Sandman-Signature` header value against a body.
+//
+// Signatures whose timestamp is more than `tolerance` away from `now`
+// are rejected; a zero tolerance uses `DefaultSignatureTolerance`.
+func Verify(secret []byte, header string, body []byte, now time.Time, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	var ts string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	if ts == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w; malformed header", ErrInvalidSignature)
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w; malformed timestamp", ErrInvalidSignature)
+	}
+	if skew := now.Sub(time.Unix(unix, 0)).Abs(); skew > tolerance {
+		return fmt.Errorf("%w; timestamp outside tolerance", ErrInvalidSignature)
+	}
+	expected := mac(secret, ts, body)
+	for _, sig := range signatures {
+		if hmac.Equal(expected, sig) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w; signature mismatch", ErrInvalidSignature)
+}
+
+func mac(secret []byte, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,123 @@
+package hook
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// ClaimState is the outcome of claiming an idempotency key.
+type ClaimState int
+
+const (
+	// ClaimAcquired means the key is new and is now in flight; the
+	// caller handles the delivery and then completes or releases it.
+	ClaimAcquired ClaimState = iota
+	// ClaimInFlight means another delivery of the key is being handled.
+	ClaimInFlight
+	// ClaimDone means the key has already been handled.
+	ClaimDone
+)
+
+// Store records which idempotency keys are being handled and which
+// already have been.
+//
+// Implementations must make `Claim` atomic; two concurrent deliveries of
+// the same key must not both see `ClaimAcquired`. A shared store should
+// expire in-flight keys after a while, so a receiver that dies mid
+// delivery doesn't block the key's retries forever.
+type Store interface {
+	// Claim marks a new key as in flight, or reports that it's already
+	// in flight or done.
+	Claim(ctx context.Context, key string) (ClaimState, error)
+	// Complete marks an in-flight key as done; it's called when the
+	// wrapped handler succeeds.
+	Complete(ctx context.Context, key string) error
+	// Release forgets a key so a later retry is handled again; it's
+	// called when the wrapped handler fails.
+	Release(ctx context.Context, key string) error
+}
+
+// DefaultLRUStoreCapacity is the number of keys `NewLRUStore` keeps when
+// given a non-positive capacity.
+const DefaultLRUStoreCapacity = 65536
+
+// NewLRUStore returns an in-memory store that remembers the most
+// recently handled `capacity` keys, plus whichever are in flight.
+//
+// Keys are lost on restart and are not shared between processes; use a
+// shared store if you run more than one receiver.
+func NewLRUStore(capacity int) *LRUStore {
+	if capacity <= 0 {
+		capacity = DefaultLRUStoreCapacity
+	}
+	return &LRUStore{
+		capacity: capacity,
+		order:    list.New(),
+		keys:     make(map[string]*list.Element),
+		inFlight: make(map[string]struct{}),
+	}
+}
+
+var _ Store = (*LRUStore)(nil)
+
+// LRUStore is a bounded in-memory `Store`.
+type LRUStore struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	keys     map[string]*list.Element
+	inFlight map[string]struct{}
+}
+
+// Claim implements `Store`.
+func (s *LRUStore) Claim(_ context.Context, key string) (ClaimState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.inFlight[key]; ok {
+		return ClaimInFlight, nil
+	}
+	if el, ok := s.keys[key]; ok {
+		s.order.MoveToFront(el)
+		return ClaimDone, nil
+	}
+	s.inFlight[key] = struct{}{}
+	return ClaimAcquired, nil
+}
+
+// Complete implements `Store`.
+func (s *LRUStore) Complete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, key)
+	if el, ok := s.keys[key]; ok {
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.keys[key] = s.order.PushFront(key)
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.keys, oldest.Value.(string))
+	}
+	return nil
+}
+
+// Release implements `Store`.
+func (s *LRUStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inFlight, key)
+	if el, ok := s.keys[key]; ok {
+		s.order.Remove(el)
+		delete(s.keys, key)
+	}
+	return nil
+}
+
+// Len returns the number of handled keys currently held.
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
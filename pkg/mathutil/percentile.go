@@ -21,11 +21,14 @@ func PercentileSorted[T Operatable](sortedInput []T, percent float64) (percentil
 		return
 	}
 	index := (percent / 100.0) * float64(len(sortedInput))
-	i := int(math.RoundToEven(index))
-	if index == float64(int64(index)) {
+	if index == float64(int64(index)) && index >= 1 && int(index) < len(sortedInput) {
+		i := int(index)
 		percentile = (sortedInput[i-1] + sortedInput[i]) / 2.0
-	} else {
-		percentile = sortedInput[i-1]
+		return
 	}
-	return percentile
+	// nearest rank, clamped so small inputs and the extremes of the
+	// interval stay in bounds.
+	rank := min(max(int(math.Ceil(index)), 1), len(sortedInput))
+	percentile = sortedInput[rank-1]
+	return
 }
@@ -0,0 +1,48 @@
+package mathutil
+
+import (
+	"fmt"
+	"testing"
+
+	"sandman/pkg/assert"
+)
+
+func Test_PercentileSorted(t *testing.T) {
+	oneToHundred := make([]float64, 100)
+	for index := range oneToHundred {
+		oneToHundred[index] = float64(index + 1)
+	}
+
+	testCases := []struct {
+		input    []float64
+		percent  float64
+		expected float64
+	}{
+		{nil, 50, 0},
+		{[]float64{5}, 0, 5},
+		{[]float64{5}, 50, 5},
+		{[]float64{5}, 99, 5},
+		{[]float64{5}, 100, 5},
+		{[]float64{1, 2}, 50, 1.5},
+		{[]float64{1, 2}, 99, 2},
+		{[]float64{1, 2}, 100, 2},
+		{[]float64{1, 2, 3}, 50, 2},
+		{[]float64{1, 2, 3}, 99, 3},
+		{oneToHundred, 0, 1},
+		{oneToHundred, 50, 50.5},
+		{oneToHundred, 99, 99.5},
+		{oneToHundred, 100, 100},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d values p%v", len(tc.input), tc.percent), func(t *testing.T) {
+			assert.Equal(t, tc.expected, PercentileSorted(tc.input, tc.percent))
+		})
+	}
+}
+
+func Test_Percentile_unsorted(t *testing.T) {
+	input := []int{9, 1, 5, 3, 7}
+	assert.Equal(t, 5, Percentile(input, 50))
+	assert.Equal(t, 9, Percentile(input, 100))
+	assert.Equal(t, []int{9, 1, 5, 3, 7}, input, "the input is left unsorted")
+}
@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"context"
+
+	"sandman/pkg/configutil"
+)
+
+// Config configures the /metrics endpoint.
+type Config struct {
+	// BindAddr is where /metrics is served. Empty disables it. It can be
+	// the same address as the health endpoints.
+	BindAddr string `yaml:"bindAddr"`
+}
+
+// Resolve resolves the config.
+func (c *Config) Resolve(ctx context.Context) error {
+	return configutil.Resolve(ctx,
+		configutil.Set(&c.BindAddr, configutil.Lazy(&c.BindAddr), configutil.Env[string]("METRICS_BIND_ADDR")),
+	)
+}
+
+// IsEnabled returns if /metrics should be served.
+func (c Config) IsEnabled() bool {
+	return c.BindAddr != ""
+}
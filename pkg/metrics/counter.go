@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+)
+
+// Counter is a monotonically increasing value.
+type Counter struct {
+	value atomicFloat
+}
+
+// Inc adds one.
+func (c *Counter) Inc() { c.value.Add(1) }
+
+// Add adds delta, which must not be negative.
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.value.Add(delta)
+}
+
+// Value returns the current value.
+func (c *Counter) Value() float64 { return c.value.Load() }
+
+// Gauge is a value that can go up and down.
+type Gauge struct {
+	value atomicFloat
+}
+
+// Set sets the value.
+func (g *Gauge) Set(v float64) { g.value.Store(v) }
+
+// Add adds delta, which may be negative.
+func (g *Gauge) Add(delta float64) { g.value.Add(delta) }
+
+// Value returns the current value.
+func (g *Gauge) Value() float64 { return g.value.Load() }
+
+// NewCounter registers a counter with the default registry.
+func NewCounter(name, help string) *Counter { return Default.NewCounter(name, help) }
+
+// NewCounterVec registers a labeled counter with the default registry.
+func NewCounterVec(name, help string, labels ...string) *Vec[*Counter] {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+// NewGauge registers a gauge with the default registry.
+func NewGauge(name, help string) *Gauge { return Default.NewGauge(name, help) }
+
+// NewGaugeVec registers a labeled gauge with the default registry.
+func NewGaugeVec(name, help string, labels ...string) *Vec[*Gauge] {
+	return Default.NewGaugeVec(name, help, labels...)
+}
+
+// NewCounter registers a counter.
+func (r *Registry) NewCounter(name, help string) *Counter {
+	return r.NewCounterVec(name, help).WithLabelValues()
+}
+
+// NewCounterVec registers a counter partitioned by the given labels.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *Vec[*Counter] {
+	v := newVec(help, "counter", labels, func() *Counter { return new(Counter) }, writeValue[*Counter])
+	r.register(name, v)
+	return v
+}
+
+// NewGauge registers a gauge.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	return r.NewGaugeVec(name, help).WithLabelValues()
+}
+
+// NewGaugeVec registers a gauge partitioned by the given labels.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *Vec[*Gauge] {
+	v := newVec(help, "gauge", labels, func() *Gauge { return new(Gauge) }, writeValue[*Gauge])
+	r.register(name, v)
+	return v
+}
+
+type valuer interface{ Value() float64 }
+
+func writeValue[T valuer](w *bufio.Writer, name string, labelNames, labelValues []string, m T) {
+	fmt.Fprintf(w, "%s%s %s\n", name, formatLabels(labelNames, labelValues), formatFloat(m.Value()))
+}
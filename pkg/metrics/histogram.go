@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// DefaultBuckets suit request latencies in seconds.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// LatenessBuckets suit delivery lateness in seconds, from well
+	// within a dispatch tick out to a worker outage.
+	LatenessBuckets = []float64{.05, .1, .25, .5, 1, 2, 5, 10, 30, 60, 300, 900, 3600}
+	// SizeBuckets suit batch sizes.
+	SizeBuckets = []float64{1, 2, 4, 8, 16, 32, 64, 128, 256, 512, 1024, 2048, 4096, 8192}
+)
+
+// Histogram counts observations into cumulative buckets.
+type Histogram struct {
+	upperBounds []float64
+	counts      []atomic.Uint64
+	count       atomic.Uint64
+	sum         atomicFloat
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		upperBounds: buckets,
+		counts:      make([]atomic.Uint64, len(buckets)),
+	}
+}
+
+// Observe records a value.
+func (h *Histogram) Observe(v float64) {
+	if index := sort.SearchFloat64s(h.upperBounds, v); index < len(h.upperBounds) {
+		h.counts[index].Add(1)
+	}
+	h.count.Add(1)
+	h.sum.Add(v)
+}
+
+// ObserveDuration records a duration in seconds.
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 { return h.count.Load() }
+
+// Sum returns the sum of observations.
+func (h *Histogram) Sum() float64 { return h.sum.Load() }
+
+// NewHistogram registers a histogram with the default registry.
+func NewHistogram(name, help string, buckets []float64) *Histogram {
+	return Default.NewHistogram(name, help, buckets)
+}
+
+// NewHistogramVec registers a labeled histogram with the default registry.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *Vec[*Histogram] {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// NewHistogram registers a histogram.
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	return r.NewHistogramVec(name, help, buckets).WithLabelValues()
+}
+
+// NewHistogramVec registers a histogram partitioned by the given labels.
+// Buckets are upper bounds and must be sorted ascending; +Inf is
+// implied.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *Vec[*Histogram] {
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metrics; histogram %q buckets must be sorted", name))
+	}
+	v := newVec(help, "histogram", labels, func() *Histogram { return newHistogram(buckets) }, writeHistogram)
+	r.register(name, v)
+	return v
+}
+
+func writeHistogram(w *bufio.Writer, name string, labelNames, labelValues []string, h *Histogram) {
+	// read count first so the buckets never exceed it in a scrape.
+	count := h.count.Load()
+	var cumulative uint64
+	for index, bound := range h.upperBounds {
+		cumulative += h.counts[index].Load()
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(labelNames, labelValues, "le", formatFloat(bound)), min(cumulative, count))
+	}
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, formatLabels(labelNames, labelValues, "le", "+Inf"), count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, formatLabels(labelNames, labelValues), formatFloat(h.sum.Load()))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, formatLabels(labelNames, labelValues), count)
+}
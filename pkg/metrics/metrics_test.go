@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := r.WritePrometheus(&buf); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRegistry_textFormat(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("jobs_total", "Jobs run.").Add(3)
+	r.NewGauge("queue_length", "Queue length.").Set(7.5)
+	codes := r.NewCounterVec("responses_total", "Responses by code.", "code")
+	codes.WithLabelValues("500").Inc()
+	codes.WithLabelValues("200").Add(2)
+	r.NewCounterVec("quoted_total", "Escaping.", "value").WithLabelValues("a\"b\\c\nd").Inc()
+	depths := r.NewGaugeVec("wheel_length", "Wheel length by worker.", "worker")
+	depths.WithLabelValues("w-01").Set(4)
+	depths.WithLabelValues("w-00").Set(2)
+
+	expected := strings.Join([]string{
+		"# HELP jobs_total Jobs run.",
+		"# TYPE jobs_total counter",
+		"jobs_total 3",
+		"# HELP queue_length Queue length.",
+		"# TYPE queue_length gauge",
+		"queue_length 7.5",
+		"# HELP quoted_total Escaping.",
+		"# TYPE quoted_total counter",
+		`quoted_total{value="a\"b\\c\nd"} 1`,
+		"# HELP responses_total Responses by code.",
+		"# TYPE responses_total counter",
+		`responses_total{code="200"} 2`,
+		`responses_total{code="500"} 1`,
+		"# HELP wheel_length Wheel length by worker.",
+		"# TYPE wheel_length gauge",
+		`wheel_length{worker="w-00"} 2`,
+		`wheel_length{worker="w-01"} 4`,
+		"",
+	}, "\n")
+	if got := scrape(t, r); got != expected {
+		t.Fatalf("unexpected exposition:\n%s\nwant:\n%s", got, expected)
+	}
+}
+
+func TestHistogram_cumulativeBuckets(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogramVec("latency_seconds", "Latency.", []float64{.1, 1}, "method")
+	m := h.WithLabelValues("Get")
+	m.Observe(.05)
+	m.Observe(.1)
+	m.ObserveDuration(500 * time.Millisecond)
+	m.Observe(30)
+
+	got := scrape(t, r)
+	for _, line := range []string{
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{method="Get",le="0.1"} 2`,
+		`latency_seconds_bucket{method="Get",le="1"} 3`,
+		`latency_seconds_bucket{method="Get",le="+Inf"} 4`,
+		`latency_seconds_sum{method="Get"} 30.65`,
+		`latency_seconds_count{method="Get"} 4`,
+	} {
+		if !strings.Contains(got, line+"\n") {
+			t.Fatalf("expected %q in:\n%s", line, got)
+		}
+	}
+}
+
+func TestRegistry_duplicateNamePanics(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("dupe_total", "")
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic registering a duplicate name")
+		}
+	}()
+	r.NewGauge("dupe_total", "")
+}
+
+func TestRegistry_serveHTTP(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("served_total", "Served.").Inc()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "served_total 1\n") {
+		t.Fatalf("unexpected body:\n%s", rec.Body.String())
+	}
+}
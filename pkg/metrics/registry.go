@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default is the registry the package level constructors register
+// with, and the one Handler serves.
+var Default = NewRegistry()
+
+// Handler serves the default registry in the Prometheus text format.
+func Handler() http.Handler {
+	return Default
+}
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		families: make(map[string]family),
+	}
+}
+
+// Registry is a set of metric families that can be written out in the
+// Prometheus text exposition format (version 0.0.4).
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]family
+}
+
+// family is a named metric and all of its label combinations.
+type family interface {
+	help() string
+	kind() string
+	// samples writes every sample line of the family.
+	samples(w *bufio.Writer, name string)
+}
+
+// register adds a family, panicking if the name is taken or invalid;
+// like expvar.Publish, a collision is a programming error.
+func (r *Registry) register(name string, f family) {
+	if !validName(name) {
+		panic(fmt.Sprintf("metrics; invalid metric name %q", name))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.families[name]; ok {
+		panic(fmt.Sprintf("metrics; metric %q already registered", name))
+	}
+	r.families[name] = f
+}
+
+// WritePrometheus writes every family, sorted by name.
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	families := make([]family, len(names))
+	sort.Strings(names)
+	for index, name := range names {
+		families[index] = r.families[name]
+	}
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for index, name := range names {
+		f := families[index]
+		fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeHelp(f.help()))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, f.kind())
+		f.samples(bw, name)
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Registry) ServeHTTP(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WritePrometheus(rw)
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for index, c := range name {
+		switch {
+		case c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		case c >= '0' && c <= '9' && index > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+// formatLabels renders `{a="x",b="y"}`, with extra appended last (used
+// for histogram `le`). Returns "" for no labels.
+func formatLabels(names, values []string, extra ...string) string {
+	if len(names) == 0 && len(extra) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for index := range names {
+		if index > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(names[index])
+		sb.WriteString(`="`)
+		sb.WriteString(labelValueEscaper.Replace(values[index]))
+		sb.WriteByte('"')
+	}
+	for index := 0; index+1 < len(extra); index += 2 {
+		if len(names) > 0 || index > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(extra[index])
+		sb.WriteString(`="`)
+		sb.WriteString(labelValueEscaper.Replace(extra[index+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Vec is a metric family partitioned by label values; each distinct set
+// of values gets its own child metric, created on first use.
+type Vec[T any] struct {
+	helpText  string
+	kindText  string
+	labels    []string
+	newMetric func() T
+	write     func(w *bufio.Writer, name string, labelNames, labelValues []string, m T)
+
+	mu       sync.RWMutex
+	children map[string]*vecChild[T]
+}
+
+type vecChild[T any] struct {
+	values []string
+	metric T
+}
+
+func newVec[T any](help, kind string, labels []string, newMetric func() T, write func(*bufio.Writer, string, []string, []string, T)) *Vec[T] {
+	return &Vec[T]{
+		helpText:  help,
+		kindText:  kind,
+		labels:    labels,
+		newMetric: newMetric,
+		write:     write,
+		children:  make(map[string]*vecChild[T]),
+	}
+}
+
+// WithLabelValues returns the child metric for the label values, given
+// in the order the labels were declared. It panics if the count is
+// wrong.
+func (v *Vec[T]) WithLabelValues(values ...string) T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics; expected %d label values, got %d", len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child.metric
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if child, ok = v.children[key]; ok {
+		return child.metric
+	}
+	child = &vecChild[T]{values: append([]string(nil), values...), metric: v.newMetric()}
+	v.children[key] = child
+	return child.metric
+}
+
+func (v *Vec[T]) help() string { return v.helpText }
+func (v *Vec[T]) kind() string { return v.kindText }
+
+func (v *Vec[T]) samples(w *bufio.Writer, name string) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	children := make([]*vecChild[T], len(keys))
+	for index, key := range keys {
+		children[index] = v.children[key]
+	}
+	v.mu.RUnlock()
+	for _, child := range children {
+		v.write(w, name, v.labels, child.values, child.metric)
+	}
+}
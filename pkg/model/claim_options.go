@@ -0,0 +1,85 @@
+package model
+
+// ClaimOption tunes how a single GetDueTimers / GetDueTimersWindowed call
+// divides its batch between shard keys.
+type ClaimOption func(*ClaimOptions)
+
+// ClaimOptions are the resolved fairness settings for a claim.
+//
+// The candidate set holds each shard key's earliest due timers, as many
+// as the key could be given in one claim (see candidatesPerKey). Within
+// it each key is ranked by (priority DESC, due_utc ASC) and the batch
+// is filled round-robin: every key gets `weight` timers per round
+// before any key gets a second round. Keys without an explicit weight
+// get DefaultClaimKeyWeight. MaxPerKey caps
+// how many rounds a single key can take in one claim, so a key with a
+// huge backlog can never take more than MaxPerKey*weight slots of a tick
+// even when nobody else is due.
+type ClaimOptions struct {
+	KeyWeights map[string]uint32
+	MaxPerKey  int
+}
+
+// DefaultClaimKeyWeight is the per-round share of a shard key that has
+// no entry in ClaimOptions.KeyWeights.
+const DefaultClaimKeyWeight = 1
+
+// OptClaimKeyWeights sets per shard key weights. A key with weight 3 is
+// given three timers for every one timer of a default-weight key.
+func OptClaimKeyWeights(weights map[string]uint32) ClaimOption {
+	return func(co *ClaimOptions) {
+		co.KeyWeights = weights
+	}
+}
+
+// OptClaimMaxPerKey caps the number of timers a default-weight shard key
+// can receive in a single claim; weighted keys get MaxPerKey*weight.
+// Zero (the default) leaves the claim bounded only by the batch size.
+func OptClaimMaxPerKey(maxPerKey int) ClaimOption {
+	return func(co *ClaimOptions) {
+		co.MaxPerKey = maxPerKey
+	}
+}
+
+// shardWeights flattens KeyWeights into parallel (shard, weight) arrays
+// for the claim query. Keys are hashed with StableHash so they line up
+// with the shard column CreateTimer wrote; the empty key maps to shard 0
+// the same way CreateTimer leaves it. Arrays are never nil so the driver
+// always binds an (empty) INT8[] rather than NULL.
+func (co ClaimOptions) shardWeights() (shards, weights []int64) {
+	shards = make([]int64, 0, len(co.KeyWeights))
+	weights = make([]int64, 0, len(co.KeyWeights))
+	for key, weight := range co.KeyWeights {
+		if weight == 0 {
+			continue
+		}
+		var shard uint32
+		if key != "" {
+			shard = StableHash([]byte(key))
+		}
+		shards = append(shards, int64(shard))
+		weights = append(weights, int64(weight))
+	}
+	return
+}
+
+// maxPerKeyOrDefault resolves a zero MaxPerKey to batchSize, which makes
+// the cap a no-op (no key can rank higher than the batch it is part of).
+func (co ClaimOptions) maxPerKeyOrDefault(batchSize int) int {
+	if co.MaxPerKey > 0 {
+		return co.MaxPerKey
+	}
+	return batchSize
+}
+
+// candidatesPerKey is how many of each shard key's earliest due timers
+// a claim considers: the most the heaviest weighted key could be given,
+// and never more than the batch. Bounding it keeps a claim's cost
+// independent of how deep any one key's backlog is.
+func (co ClaimOptions) candidatesPerKey(batchSize int) int {
+	maxWeight := DefaultClaimKeyWeight
+	for _, weight := range co.KeyWeights {
+		maxWeight = max(maxWeight, int(weight))
+	}
+	return max(min(co.maxPerKeyOrDefault(batchSize)*maxWeight, batchSize), 0)
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"sandman/pkg/db"
+	"sandman/pkg/mathutil"
+)
+
+var (
+	_                      db.TableNameProvider = (*DeliveryStats)(nil)
+	deliveryStatsTypeMeta                       = db.TypeMetaFor(DeliveryStats{})
+	deliveryStatsTableName                      = db.TableName(DeliveryStats{})
+	deliveryStatsColumns                        = deliveryStatsTypeMeta.Columns()
+)
+
+// DeliveryStatsBucket is the width of a DeliveryStats rollup.
+const DeliveryStatsBucket = time.Minute
+
+// DeliveryStats summarizes the lateness (fired_utc - due_utc) of the
+// deliveries of one shard key fired within one bucket.
+//
+// Percentiles of different buckets can't be combined exactly; a range
+// is reported bucket by bucket.
+type DeliveryStats struct {
+	BucketUTC   time.Time     `db:"bucket_utc,pk"`
+	ShardKey    string        `db:"shard_key,pk"`
+	Count       int64         `db:"delivery_count"`
+	LatenessP50 time.Duration `db:"lateness_p50"`
+	LatenessP99 time.Duration `db:"lateness_p99"`
+	LatenessMax time.Duration `db:"lateness_max"`
+}
+
+// TableName returns the table name.
+func (ds DeliveryStats) TableName() string { return "delivery_stats" }
+
+// MatchLabels returns the labels stats selectors match against.
+func (ds DeliveryStats) MatchLabels() map[string]string {
+	return map[string]string{
+		"shard_key": ds.ShardKey,
+	}
+}
+
+// timerFiring is the part of a delivered timer the rollup reads.
+type timerFiring struct {
+	ShardKey string
+	DueUTC   time.Time
+	FiredUTC time.Time
+}
+
+// summarizeLateness groups firings by bucket and shard key and
+// summarizes each group, in (bucket, shard key) order.
+func summarizeLateness(firings []timerFiring) (output []DeliveryStats) {
+	type groupKey struct {
+		bucketUTC time.Time
+		shardKey  string
+	}
+	groups := make(map[groupKey][]time.Duration)
+	for _, f := range firings {
+		k := groupKey{bucketUTC: f.FiredUTC.UTC().Truncate(DeliveryStatsBucket), shardKey: f.ShardKey}
+		groups[k] = append(groups[k], f.FiredUTC.Sub(f.DueUTC))
+	}
+	output = make([]DeliveryStats, 0, len(groups))
+	for k, lateness := range groups {
+		slices.Sort(lateness)
+		output = append(output, DeliveryStats{
+			BucketUTC:   k.bucketUTC,
+			ShardKey:    k.shardKey,
+			Count:       int64(len(lateness)),
+			LatenessP50: mathutil.PercentileSorted(lateness, 50),
+			LatenessP99: mathutil.PercentileSorted(lateness, 99),
+			LatenessMax: lateness[len(lateness)-1],
+		})
+	}
+	slices.SortFunc(output, func(a, b DeliveryStats) int {
+		if c := a.BucketUTC.Compare(b.BucketUTC); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ShardKey, b.ShardKey)
+	})
+	return
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"sandman/pkg/db"
@@ -9,14 +10,12 @@ import (
 	"sandman/pkg/testutil"
 )
 
+// TestMain runs the package tests against a local cockroachdb node, or
+// against stock Postgres with `DB_DIALECT=psql` (and the usual DB_HOST,
+// DB_PORT, DB_USER etc. to find it); see `make test-postgres`.
 func TestMain(m *testing.M) {
 	testutil.New(m,
-		testutil.OptWithDefaultDB(
-			db.OptLog(log.New()),
-			db.OptDialect(db.DialectCockroachDB),
-			db.OptUsername("root"),
-			db.OptPort("26257"),
-		),
+		testutil.OptWithDefaultDB(testDBOptions()...),
 		testutil.OptBefore(
 			func(ctx context.Context) error {
 				return Migrations().Apply(ctx, testutil.DefaultDB())
@@ -24,3 +23,18 @@ func TestMain(m *testing.M) {
 		),
 	).Run()
 }
+
+func testDBOptions() []db.Option {
+	if db.Dialect(os.Getenv(db.EnvVarDBDialect)).Is(db.DialectPostgres) {
+		return []db.Option{
+			db.OptLog(log.New()),
+			db.OptDialect(db.DialectPostgres),
+		}
+	}
+	return []db.Option{
+		db.OptLog(log.New()),
+		db.OptDialect(db.DialectCockroachDB),
+		db.OptUsername("root"),
+		db.OptPort("26257"),
+	}
+}
@@ -3,7 +3,9 @@ package model
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,25 +18,42 @@ import (
 type Manager struct {
 	dbutil.BaseManager
 
-	getDueTimers        *sql.Stmt
-	getTimerByName      *sql.Stmt
-	getTimersDueBetween *sql.Stmt
-	cullTimers          *sql.Stmt
-	markAttempted       *sql.Stmt
-	bulkMarkAttempted   *sql.Stmt
-	bulkRelinquish      *sql.Stmt
-	deleteTimerByID     *sql.Stmt
-	deleteTimerByName   *sql.Stmt
-	bulkMarkDelivered   *sql.Stmt
-	workerSeen          *sql.Stmt
-	deleteWorker          *sql.Stmt
-	getWorkers            *sql.Stmt
-	getPeakTimersDueCount *sql.Stmt
-	getOverdueTimerCount  *sql.Stmt
+	getDueTimers              *sql.Stmt
+	getTimerByName            *sql.Stmt
+	getTimersDueBetween       *sql.Stmt
+	cullTimers                *sql.Stmt
+	markAttempted             *sql.Stmt
+	bulkMarkAttempted         *sql.Stmt
+	bulkRelinquish            *sql.Stmt
+	deleteTimerByID           *sql.Stmt
+	deleteTimerByName         *sql.Stmt
+	bulkMarkDelivered         *sql.Stmt
+	bulkMarkExpired           *sql.Stmt
+	workerSeen                *sql.Stmt
+	deleteWorker              *sql.Stmt
+	getWorkers                *sql.Stmt
+	getPeakTimersDueCount     *sql.Stmt
+	getOverdueTimerCount      *sql.Stmt
+	exportDeliveredTimers     *sql.Stmt
+	getCullableTimers         *sql.Stmt
+	bulkDeleteTimers          *sql.Stmt
+	exportTimers              *sql.Stmt
+	importTimerDoNothing      *sql.Stmt
+	importTimerReplace        *sql.Stmt
+	renewLeases               *sql.Stmt
+	getTimerFiringsBetween    *sql.Stmt
+	upsertDeliveryStats       *sql.Stmt
+	getDeliveryStatsWatermark *sql.Stmt
+	getDeliveryStats          *sql.Stmt
+	cullDeliveryStats         *sql.Stmt
 }
 
 func (m *Manager) Initialize(ctx context.Context) (err error) {
-	m.getDueTimers, err = m.Invoke(ctx).Prepare(queryGetDueTimers)
+	queryGetDueTimersForDialect := queryGetDueTimers
+	if isPostgres(m.Conn) {
+		queryGetDueTimersForDialect = queryGetDueTimersPostgres
+	}
+	m.getDueTimers, err = m.Invoke(ctx).Prepare(queryGetDueTimersForDialect)
 	if err != nil {
 		err = fmt.Errorf("getDueTimers: %w", err)
 		return
@@ -84,6 +103,11 @@ func (m *Manager) Initialize(ctx context.Context) (err error) {
 		err = fmt.Errorf("bulkMarkDelivered: %w", err)
 		return
 	}
+	m.bulkMarkExpired, err = m.Invoke(ctx).Prepare(execBulkMarkExpired)
+	if err != nil {
+		err = fmt.Errorf("bulkMarkExpired: %w", err)
+		return
+	}
 	m.workerSeen, err = m.Invoke(ctx).Prepare(execWorkerSeen)
 	if err != nil {
 		err = fmt.Errorf("workerSeen: %w", err)
@@ -109,6 +133,66 @@ func (m *Manager) Initialize(ctx context.Context) (err error) {
 		err = fmt.Errorf("getOverdueTimerCount: %w", err)
 		return
 	}
+	m.exportDeliveredTimers, err = m.Invoke(ctx).Prepare(queryExportDeliveredTimers)
+	if err != nil {
+		err = fmt.Errorf("exportDeliveredTimers: %w", err)
+		return
+	}
+	m.getCullableTimers, err = m.Invoke(ctx).Prepare(queryGetCullableTimers)
+	if err != nil {
+		err = fmt.Errorf("getCullableTimers: %w", err)
+		return
+	}
+	m.bulkDeleteTimers, err = m.Invoke(ctx).Prepare(execBulkDeleteTimers)
+	if err != nil {
+		err = fmt.Errorf("bulkDeleteTimers: %w", err)
+		return
+	}
+	m.exportTimers, err = m.Invoke(ctx).Prepare(queryExportTimers)
+	if err != nil {
+		err = fmt.Errorf("exportTimers: %w", err)
+		return
+	}
+	m.importTimerDoNothing, err = m.Invoke(ctx).Prepare(execImportTimerDoNothing)
+	if err != nil {
+		err = fmt.Errorf("importTimerDoNothing: %w", err)
+		return
+	}
+	m.importTimerReplace, err = m.Invoke(ctx).Prepare(execImportTimerReplace)
+	if err != nil {
+		err = fmt.Errorf("importTimerReplace: %w", err)
+		return
+	}
+	m.renewLeases, err = m.Invoke(ctx).Prepare(queryRenewLeases)
+	if err != nil {
+		err = fmt.Errorf("renewLeases: %w", err)
+		return
+	}
+	m.getTimerFiringsBetween, err = m.Invoke(ctx).Prepare(queryGetTimerFiringsBetween)
+	if err != nil {
+		err = fmt.Errorf("getTimerFiringsBetween: %w", err)
+		return
+	}
+	m.upsertDeliveryStats, err = m.Invoke(ctx).Prepare(execUpsertDeliveryStats)
+	if err != nil {
+		err = fmt.Errorf("upsertDeliveryStats: %w", err)
+		return
+	}
+	m.getDeliveryStatsWatermark, err = m.Invoke(ctx).Prepare(queryGetDeliveryStatsWatermark)
+	if err != nil {
+		err = fmt.Errorf("getDeliveryStatsWatermark: %w", err)
+		return
+	}
+	m.getDeliveryStats, err = m.Invoke(ctx).Prepare(queryGetDeliveryStats)
+	if err != nil {
+		err = fmt.Errorf("getDeliveryStats: %w", err)
+		return
+	}
+	m.cullDeliveryStats, err = m.Invoke(ctx).Prepare(execCullDeliveryStats)
+	if err != nil {
+		err = fmt.Errorf("cullDeliveryStats: %w", err)
+		return
+	}
 	return
 }
 
@@ -143,6 +227,9 @@ func (m Manager) Close() error {
 	if err := m.bulkMarkDelivered.Close(); err != nil {
 		return err
 	}
+	if err := m.bulkMarkExpired.Close(); err != nil {
+		return err
+	}
 	if err := m.workerSeen.Close(); err != nil {
 		return err
 	}
@@ -158,9 +245,56 @@ func (m Manager) Close() error {
 	if err := m.getOverdueTimerCount.Close(); err != nil {
 		return err
 	}
+	if err := m.exportDeliveredTimers.Close(); err != nil {
+		return err
+	}
+	if err := m.getCullableTimers.Close(); err != nil {
+		return err
+	}
+	if err := m.bulkDeleteTimers.Close(); err != nil {
+		return err
+	}
+	if err := m.exportTimers.Close(); err != nil {
+		return err
+	}
+	if err := m.importTimerDoNothing.Close(); err != nil {
+		return err
+	}
+	if err := m.importTimerReplace.Close(); err != nil {
+		return err
+	}
+	if err := m.renewLeases.Close(); err != nil {
+		return err
+	}
+	if err := m.getTimerFiringsBetween.Close(); err != nil {
+		return err
+	}
+	if err := m.upsertDeliveryStats.Close(); err != nil {
+		return err
+	}
+	if err := m.getDeliveryStatsWatermark.Close(); err != nil {
+		return err
+	}
+	if err := m.getDeliveryStats.Close(); err != nil {
+		return err
+	}
+	if err := m.cullDeliveryStats.Close(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// CreateTimer inserts a timer, setting its ID if it wasn't already set.
+func (m Manager) CreateTimer(ctx context.Context, t *Timer) error {
+	return m.Invoke(ctx).Create(t)
+}
+
+// GetTimerByID returns the timer with the given id, if any.
+func (m Manager) GetTimerByID(ctx context.Context, id uuid.UUID) (out Timer, found bool, err error) {
+	found, err = m.Invoke(ctx).Get(&out, id)
+	return
+}
+
 var queryGetTimerByName = fmt.Sprintf(`SELECT %s FROM %s WHERE name = $1`, db.ColumnNamesCSV(timerColumns), timerTableName)
 
 func (m Manager) GetTimerByName(ctx context.Context, name string) (out Timer, found bool, err error) {
@@ -188,7 +322,7 @@ WHERE
 	due_utc > $1 AND due_utc < $2
 `, db.ColumnNamesCSV(timerColumns), timerTableName)
 
-func (m Manager) GetTimersDueBetween(ctx context.Context, after, before time.Time, s selector.Selector, opts ...db.InvocationOption) (output []Timer, err error) {
+func (m Manager) GetTimersDueBetween(ctx context.Context, after, before time.Time, s selector.Selector) (output []Timer, err error) {
 	var rows *sql.Rows
 	rows, err = m.getTimersDueBetween.QueryContext(ctx, after, before)
 	if err != nil {
@@ -212,11 +346,238 @@ func (m Manager) GetTimersDueBetween(ctx context.Context, after, before time.Tim
 	return
 }
 
+// queryExportDeliveredTimers pages through delivered (not yet culled)
+// timers in (delivered_utc, id) order, off ix_timers_delivered_utc.
+//
+// $1 = after, $2 = before, $3 / $4 = cursor delivered_utc / id; a null
+// $4 starts from the beginning of the range, $5 = page size.
+var queryExportDeliveredTimers = fmt.Sprintf(`SELECT
+	%s
+FROM
+	%s
+WHERE
+	delivered_utc IS NOT NULL
+	AND delivered_utc > $1 AND delivered_utc < $2
+	AND ($4::UUID IS NULL OR (delivered_utc, id) > ($3, $4::UUID))
+ORDER BY delivered_utc, id
+LIMIT $5
+`, db.ColumnNamesCSV(timerColumns), timerTableName)
+
+// ExportDeliveredTimers calls fn for every (not yet culled) timer
+// delivered between after and before that matches the selector, in
+// delivery order.
+//
+// Like ExportTimers, timers are read a page at a time, so fn can stop
+// the scan early by returning an error.
+func (m Manager) ExportDeliveredTimers(ctx context.Context, after, before time.Time, s selector.Selector, fn func(Timer) error) error {
+	cursorDelivered := after
+	var cursorID uuid.UUID
+	for {
+		rows, err := m.exportDeliveredTimers.QueryContext(ctx, after, before, cursorDelivered, cursorID, exportPageSize)
+		if err != nil {
+			return err
+		}
+		var page []Timer
+		for rows.Next() {
+			var t Timer
+			if err = db.PopulateInOrder(&t, rows, timerColumns); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			page = append(page, t)
+		}
+		if err = rows.Err(); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		_ = rows.Close()
+		for _, t := range page {
+			if s != nil && !s.Matches(t.MatchLabels()) {
+				continue
+			}
+			if err = fn(t); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+		cursorDelivered, cursorID = *page[len(page)-1].DeliveredUTC, page[len(page)-1].ID
+	}
+}
+
+// exportPageSize is how many timers ExportTimers reads per query.
+const exportPageSize = 1024
+
+// queryExportTimers pages through pending timers in (due_utc, id) order.
+// Exhausted timers (out of attempts) are left out; they'd never fire.
+//
+// $1 = after, $2 = before, $3 / $4 = cursor due_utc / id; a null $4
+// starts from the beginning of the range, $5 = page size.
+var queryExportTimers = fmt.Sprintf(`SELECT
+	%s
+FROM
+	%s
+WHERE
+	delivered_utc IS NULL
+	AND expired_utc IS NULL
+	AND attempt < 5
+	AND due_utc > $1 AND due_utc < $2
+	AND ($4::UUID IS NULL OR (due_utc, id) > ($3, $4::UUID))
+ORDER BY due_utc, id
+LIMIT $5
+`, db.ColumnNamesCSV(timerColumns), timerTableName)
+
+// ExportTimers calls fn for every pending (not delivered, expired or
+// exhausted) timer due between after and before that matches the
+// selector, in due order.
+//
+// Timers are read a page at a time rather than in one long-lived query,
+// so timers created or claimed during the export may or may not be seen.
+func (m Manager) ExportTimers(ctx context.Context, after, before time.Time, s selector.Selector, fn func(Timer) error) error {
+	cursorDue := after
+	var cursorID uuid.UUID
+	for {
+		rows, err := m.exportTimers.QueryContext(ctx, after, before, cursorDue, cursorID, exportPageSize)
+		if err != nil {
+			return err
+		}
+		var page []Timer
+		for rows.Next() {
+			var t Timer
+			if err = db.PopulateInOrder(&t, rows, timerColumns); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			page = append(page, t)
+		}
+		if err = rows.Err(); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		_ = rows.Close()
+		for _, t := range page {
+			if s != nil && !s.Matches(t.MatchLabels()) {
+				continue
+			}
+			if err = fn(t); err != nil {
+				return err
+			}
+		}
+		if len(page) < exportPageSize {
+			return nil
+		}
+		cursorDue, cursorID = page[len(page)-1].DueUTC, page[len(page)-1].ID
+	}
+}
+
+// ImportConflict is what ImportTimer does when a timer with the same
+// name already exists.
+type ImportConflict int
+
+// ImportConflict values.
+const (
+	ImportConflictFail ImportConflict = iota
+	ImportConflictSkip
+	ImportConflictReplace
+)
+
+// ImportResult is what ImportTimer did with a timer.
+type ImportResult int
+
+// ImportResult values.
+const (
+	ImportCreated ImportResult = iota
+	ImportSkipped
+	ImportReplaced
+)
+
+// ErrTimerNameExists is returned by ImportTimer under ImportConflictFail
+// when the timer's name is already taken.
+var ErrTimerNameExists = errors.New("timer name already exists")
+
+var (
+	timerImportColumns = timerTypeMeta.InsertColumns()
+
+	// execImportTimer inserts a timer keyed on its unique name. The prev
+	// CTE reads the table as of before the insert so we can tell a
+	// replace from a create.
+	execImportTimerTemplate = fmt.Sprintf(`WITH prev AS (
+	SELECT id FROM %[1]s WHERE name = $%[2]d
+)
+INSERT INTO %[1]s (%[3]s) VALUES (%[4]s)
+ON CONFLICT (name) DO %%s
+RETURNING id, EXISTS (SELECT 1 FROM prev)
+`,
+		timerTableName,
+		slices.IndexFunc(timerImportColumns, func(c *db.Column) bool { return c.ColumnName == "name" })+1,
+		db.ColumnNamesCSV(timerImportColumns),
+		placeholdersCSV(len(timerImportColumns)),
+	)
+	execImportTimerDoNothing = fmt.Sprintf(execImportTimerTemplate, "NOTHING")
+	// execImportTimerReplace bumps the replaced row's lease token rather
+	// than taking the import's, so a worker still holding a lease on the
+	// old timer is fenced off it.
+	execImportTimerReplace = fmt.Sprintf(execImportTimerTemplate,
+		"UPDATE SET "+excludedAssignmentsCSV(timerImportColumns, "name", "lease_token")+
+			fmt.Sprintf(",lease_token = %s.lease_token + 1", timerTableName),
+	)
+)
+
+// ImportTimer creates a timer, resolving a name conflict with the given
+// policy. On success the timer's ID is set to the created (or replaced)
+// row's id.
+func (m Manager) ImportTimer(ctx context.Context, t *Timer, onConflict ImportConflict) (result ImportResult, err error) {
+	stmt := m.importTimerDoNothing
+	if onConflict == ImportConflictReplace {
+		stmt = m.importTimerReplace
+	}
+	var existed bool
+	err = stmt.QueryRowContext(ctx, db.ColumnValues(timerImportColumns, t)...).Scan(&t.ID, &existed)
+	if errors.Is(err, sql.ErrNoRows) {
+		if onConflict == ImportConflictSkip {
+			return ImportSkipped, nil
+		}
+		return ImportCreated, fmt.Errorf("%w; %q", ErrTimerNameExists, t.Name)
+	}
+	if err != nil {
+		return
+	}
+	if existed {
+		result = ImportReplaced
+	}
+	return
+}
+
+func placeholdersCSV(count int) string {
+	placeholders := make([]string, count)
+	for index := range placeholders {
+		placeholders[index] = fmt.Sprintf("$%d", index+1)
+	}
+	return strings.Join(placeholders, ",")
+}
+
+func excludedAssignmentsCSV(cols []*db.Column, skip ...string) string {
+	var assignments []string
+	for _, c := range cols {
+		if slices.Contains(skip, c.ColumnName) {
+			continue
+		}
+		assignments = append(assignments, fmt.Sprintf("%[1]s = excluded.%[1]s", c.ColumnName))
+	}
+	return strings.Join(assignments, ",")
+}
+
 // queryGetDueTimers parameters:
-//   $1 = worker identity, $2 = asOf, $3 = batch size,
-//   $4 = shard low (inclusive), $5 = shard high (exclusive),
-//   $6 = due_cutoff (= asOf + window): claim everything due before this,
-//   $7 = lease_until (= asOf + lease): how long the claim should be held.
+//
+//	$1 = worker identity, $2 = asOf, $3 = batch size,
+//	$4 / $5 = shard range lows (inclusive) / highs (exclusive), parallel
+//	INT8[] arrays of the worker's owned sub-ranges,
+//	$6 = due_cutoff (= asOf + window): claim everything due before this,
+//	$7 = lease_until (= asOf + lease): how long the claim should be held,
+//	$8 = weighted shards, $9 = weights (parallel INT8[] arrays),
+//	$10 = max rounds per shard key,
+//	$11 = candidates per shard key (see ClaimOptions.candidatesPerKey).
 //
 // $6 and $7 are precomputed as timestamps in Go rather than derived
 // from $2 + interval inside SQL — pgx defaults numeric-cast placeholders
@@ -224,55 +585,133 @@ func (m Manager) GetTimersDueBetween(ctx context.Context, after, before time.Tim
 // the surrounding context is interval arithmetic. Passing concrete
 // timestamps sidesteps that and keeps the query plan trivial.
 //
-// The shuffle-shard priority boost still uses asOf alone so the
-// per-tick fairness ordering is unchanged when window > 0.
+// Fairness is weighted round-robin across shard keys (the shard column
+// is the hash of shard_key). candidates takes each shard's $11 earliest
+// due timers and ranks them by (priority DESC, due_utc ASC); selected
+// then fills the batch a round at a time, where a shard with weight w
+// contributes its next w timers per round. A key with 1M due timers
+// therefore gets at most weight/(sum of weights) of a contended batch,
+// and a key with a single due timer is always claimed in the first
+// round. $10 additionally caps how many rounds any shard may take in one
+// claim. Ties within a round go to priority, then the most overdue
+// timer. Priority only reorders a key's candidates, so a high priority
+// timer behind more than $11 earlier due timers of its own key waits
+// for them.
+//
+// Timers with an ordering_key are only claimable at the head of their
+// (shard, ordering_key) stream: any earlier undelivered, unexpired,
+// unexhausted timer for the same key — pending, leased to a worker, or
+// waiting out a retry — blocks everything behind it. That makes delivery
+// per key strictly sequential in (due_utc, id) order, and because every
+// timer of a key shares a shard, only the worker owning that shard's
+// band ever claims the key.
+//
+// A worker owns many small sub-ranges of the shard space (see
+// ShardRange). shards walks each range for the distinct shards it holds
+// with a loose index scan, one seek per shard, and candidates reads each
+// shard's due rows in index order, stopping after $11. A claim therefore
+// costs O(shards in the band + $11 per shard) however deep the backlog,
+// plus whatever leased rows it steps over on the way. The scans are
+// index-only (STORING covers assigned_until_utc, retry_utc, priority,
+// ordering_key).
 //
-// The candidates scan is index-only (STORING covers assigned_until_utc,
-// retry_utc, priority) and non-locking. Under READ COMMITTED, mikoshi
+// The candidates scan is non-locking. Under READ COMMITTED, mikoshi
 // only validates locked + written spans at commit, so concurrent
 // BulkMarkDelivered writes to rows the scan touched-but-filtered-out
 // don't force a 40001. The assigned_until_utc guard is re-checked in the
 // outer UPDATE's WHERE so a row another worker claimed during a band
 // shift is a no-op rather than a double-claim.
-var queryGetDueTimers = fmt.Sprintf(`WITH candidates AS (
+//
+// Every claim bumps lease_token; the returned token fences the claimer's
+// completion writes (see BulkMarkDelivered).
+var queryGetDueTimers = fmt.Sprintf(`WITH RECURSIVE shards AS (
 	SELECT
-		id, priority, shard, due_utc
+		band.hi,
+		(
+			SELECT min(t.shard)
+			FROM %[1]s@ix_timers_shard_due_utc_ready AS t
+			WHERE t.shard >= band.lo AND t.shard < band.hi
+		) AS shard
 	FROM
-		%[1]s@ix_timers_shard_due_utc_pending
-	WHERE
-		shard >= $4 AND shard < $5
-		AND due_utc < $6
-		AND (
-			assigned_until_utc IS NULL OR (assigned_until_utc IS NOT NULL AND assigned_until_utc < $2)
-		)
-		AND (
-			retry_utc IS NULL OR (retry_utc IS NOT NULL AND retry_utc < $2)
+		unnest($4::INT8[], $5::INT8[]) AS band(lo, hi)
+	UNION ALL
+	SELECT
+		s.hi,
+		(
+			SELECT min(t.shard)
+			FROM %[1]s@ix_timers_shard_due_utc_ready AS t
+			WHERE t.shard > s.shard AND t.shard < s.hi
 		)
-		-- attempt < 5 AND delivered_utc IS NULL are implied by the
-		-- partial index predicate; mikoshi's optimizer elides them.
-	ORDER BY shard ASC, due_utc ASC
-	LIMIT $3 * 2
+	FROM
+		shards s
+	WHERE
+		s.shard IS NOT NULL
+), candidates AS (
+	SELECT
+		c.id, c.priority, c.due_utc,
+		ROW_NUMBER() OVER (PARTITION BY s.shard ORDER BY c.priority DESC, c.due_utc ASC, c.id ASC) AS key_rank,
+		COALESCE(w.weight, 1) AS key_weight
+	FROM
+		shards s
+	LEFT JOIN
+		unnest($8::INT8[], $9::INT8[]) AS w(shard, weight) ON w.shard = s.shard
+	JOIN LATERAL (
+		SELECT
+			t.id, t.priority, t.due_utc
+		FROM
+			%[1]s@ix_timers_shard_due_utc_ready AS t
+		WHERE
+			t.shard = s.shard
+			AND t.due_utc < $6
+			AND (
+				t.assigned_until_utc IS NULL OR (t.assigned_until_utc IS NOT NULL AND t.assigned_until_utc < $2)
+			)
+			AND (
+				t.retry_utc IS NULL OR (t.retry_utc IS NOT NULL AND t.retry_utc < $2)
+			)
+			-- attempt < 5, delivered_utc IS NULL and expired_utc IS NULL
+			-- are implied by the partial index predicate; mikoshi's
+			-- optimizer elides them.
+			AND (
+				t.ordering_key = ''
+				OR NOT EXISTS (
+					SELECT 1
+					FROM %[1]s@ix_timers_ordering_pending AS prev
+					WHERE
+						prev.shard = t.shard
+						AND prev.ordering_key = t.ordering_key
+						AND (prev.due_utc, prev.id) < (t.due_utc, t.id)
+						AND prev.ordering_key != ''
+						AND prev.delivered_utc IS NULL
+						AND prev.expired_utc IS NULL
+						AND prev.attempt < 5
+				)
+			)
+		ORDER BY
+			t.due_utc ASC, t.id ASC
+		LIMIT $11
+	) AS c ON true
+	WHERE
+		s.shard IS NOT NULL
 ), selected AS (
 	SELECT
 		id
 	FROM
 		candidates
+	WHERE
+		key_rank <= $10 * key_weight
 	ORDER BY
-		priority
-		+ (
-			(
-				MOD(shard, 3600) + cast(extract('minute', $2::timestamp) * extract('second', $2::timestamp) as BIGINT)
-			)
-			* 100
-		)
-		+ (cast(extract('epoch', $2::timestamp) - extract('epoch', due_utc) as BIGINT) * 100)
-	DESC
+		floor((key_rank - 1)::FLOAT8 / key_weight::FLOAT8) ASC
+		, priority DESC
+		, due_utc ASC
+		, id ASC
 	LIMIT $3
 )
 UPDATE %[1]s
 SET
 	assigned_worker = $1
 	, attempt = attempt + 1
+	, lease_token = lease_token + 1
 	, assigned_until_utc = $7
 	, retry_utc = $2::timestamp + interval '5 minutes'
 WHERE
@@ -281,6 +720,124 @@ WHERE
 RETURNING %[2]s
 `, timerTableName, db.ColumnNamesCSV(timerColumns))
 
+// queryGetDueTimersPostgres is queryGetDueTimers for stock Postgres,
+// with the same parameters and selection rules.
+//
+// Postgres has no index hints, so the partial index predicate is
+// spelled out for the planner to match in both the shards walk and the
+// per-shard scan. Only the selected timers are locked, FOR UPDATE SKIP
+// LOCKED with the lease re-checked: concurrent claimers skip each
+// other's picks rather than queueing behind them, at the cost of a
+// short batch when they pick the same timers.
+var queryGetDueTimersPostgres = fmt.Sprintf(`WITH RECURSIVE shards AS (
+	SELECT
+		band.hi,
+		(
+			SELECT min(t.shard)
+			FROM %[1]s AS t
+			WHERE
+				t.shard >= band.lo AND t.shard < band.hi
+				AND t.delivered_utc IS NULL AND t.expired_utc IS NULL AND t.attempt < 5
+		) AS shard
+	FROM
+		unnest($4::INT8[], $5::INT8[]) AS band(lo, hi)
+	UNION ALL
+	SELECT
+		s.hi,
+		(
+			SELECT min(t.shard)
+			FROM %[1]s AS t
+			WHERE
+				t.shard > s.shard AND t.shard < s.hi
+				AND t.delivered_utc IS NULL AND t.expired_utc IS NULL AND t.attempt < 5
+		)
+	FROM
+		shards s
+	WHERE
+		s.shard IS NOT NULL
+), candidates AS (
+	SELECT
+		c.id, c.priority, c.due_utc,
+		ROW_NUMBER() OVER (PARTITION BY s.shard ORDER BY c.priority DESC, c.due_utc ASC, c.id ASC) AS key_rank,
+		COALESCE(w.weight, 1) AS key_weight
+	FROM
+		shards s
+	LEFT JOIN
+		unnest($8::INT8[], $9::INT8[]) AS w(shard, weight) ON w.shard = s.shard
+	JOIN LATERAL (
+		SELECT
+			t.id, t.priority, t.due_utc
+		FROM
+			%[1]s AS t
+		WHERE
+			t.shard = s.shard
+			AND t.due_utc < $6
+			AND t.delivered_utc IS NULL
+			AND t.expired_utc IS NULL
+			AND t.attempt < 5
+			AND (
+				t.assigned_until_utc IS NULL OR t.assigned_until_utc < $2
+			)
+			AND (
+				t.retry_utc IS NULL OR t.retry_utc < $2
+			)
+			AND (
+				t.ordering_key = ''
+				OR NOT EXISTS (
+					SELECT 1
+					FROM %[1]s AS prev
+					WHERE
+						prev.shard = t.shard
+						AND prev.ordering_key = t.ordering_key
+						AND (prev.due_utc, prev.id) < (t.due_utc, t.id)
+						AND prev.ordering_key != ''
+						AND prev.delivered_utc IS NULL
+						AND prev.expired_utc IS NULL
+						AND prev.attempt < 5
+				)
+			)
+		ORDER BY
+			t.due_utc ASC, t.id ASC
+		LIMIT $11
+	) AS c ON true
+	WHERE
+		s.shard IS NOT NULL
+), selected AS (
+	SELECT
+		id
+	FROM
+		candidates
+	WHERE
+		key_rank <= $10 * key_weight
+	ORDER BY
+		floor((key_rank - 1)::FLOAT8 / key_weight::FLOAT8) ASC
+		, priority DESC
+		, due_utc ASC
+		, id ASC
+	LIMIT $3
+), locked AS (
+	SELECT
+		t.id
+	FROM
+		%[1]s AS t
+	WHERE
+		t.id IN (SELECT id FROM selected)
+		AND (t.assigned_until_utc IS NULL OR t.assigned_until_utc < $2)
+	FOR UPDATE SKIP LOCKED
+)
+UPDATE %[1]s
+SET
+	assigned_worker = $1
+	, attempt = attempt + 1
+	, lease_token = lease_token + 1
+	, assigned_until_utc = $7
+	, retry_utc = $2::timestamp + interval '5 minutes'
+WHERE
+	id in (SELECT id FROM locked)
+	AND (assigned_until_utc IS NULL OR assigned_until_utc < $2)
+RETURNING %[2]s
+`, timerTableName, db.ColumnNamesCSV(timerColumns))
+
 //
 
 // defaultLeaseSeconds matches the original 1-minute claim window used by
@@ -288,16 +845,16 @@ RETURNING %[2]s
 // lease covering windowSeconds + safety margin via GetDueTimersWindowed.
 const defaultLeaseSeconds = 60
 
-// GetDueTimers claims up to batchSize timers whose shard falls in
-// [shardLo, shardHi). The half-open range lets workers partition the
+// GetDueTimers claims up to batchSize timers whose shard falls in one of
+// the given ranges. The half-open ranges let workers partition the
 // uint32 shard space cleanly: a worker that owns the whole space passes
-// (0, 1<<32). `FOR UPDATE SKIP LOCKED` still protects against two
-// workers briefly overlapping bands during a membership change.
+// AllShards(). The outer UPDATE's lease re-check still protects against
+// two workers briefly overlapping during a membership change.
 //
 // This is the legacy "due now" entry point — equivalent to
 // GetDueTimersWindowed with windowSeconds=0 and the default lease.
-func (m Manager) GetDueTimers(ctx context.Context, workerIdentity string, asOf time.Time, batchSize int, shardLo, shardHi uint64) (output []Timer, err error) {
-	return m.GetDueTimersWindowed(ctx, workerIdentity, asOf, batchSize, shardLo, shardHi, 0, defaultLeaseSeconds)
+func (m Manager) GetDueTimers(ctx context.Context, workerIdentity string, asOf time.Time, batchSize int, shards []ShardRange, opts ...ClaimOption) (output []Timer, err error) {
+	return m.GetDueTimersWindowed(ctx, workerIdentity, asOf, batchSize, shards, 0, defaultLeaseSeconds, opts...)
 }
 
 // GetDueTimersWindowed is the wheel-mode claim path. Callers can ask for
@@ -310,14 +867,20 @@ func (m Manager) GetDueTimers(ctx context.Context, workerIdentity string, asOf t
 // dueCutoff and leaseUntil are derived as timestamps in Go and passed
 // directly so pgx never has to encode an integer placeholder used in
 // CRDB interval arithmetic — see the queryGetDueTimers comment.
-func (m Manager) GetDueTimersWindowed(ctx context.Context, workerIdentity string, asOf time.Time, batchSize int, shardLo, shardHi uint64, windowSeconds, leaseSeconds int) (output []Timer, err error) {
+func (m Manager) GetDueTimersWindowed(ctx context.Context, workerIdentity string, asOf time.Time, batchSize int, shards []ShardRange, windowSeconds, leaseSeconds int, opts ...ClaimOption) (output []Timer, err error) {
 	if leaseSeconds <= 0 {
 		leaseSeconds = defaultLeaseSeconds
 	}
+	var claimOptions ClaimOptions
+	for _, opt := range opts {
+		opt(&claimOptions)
+	}
+	weightedShards, weights := claimOptions.shardWeights()
+	shardLows, shardHighs := shardRangeBounds(shards)
 	dueCutoff := asOf.Add(time.Duration(windowSeconds) * time.Second)
 	leaseUntil := asOf.Add(time.Duration(leaseSeconds) * time.Second)
 	var rows *sql.Rows
-	rows, err = m.getDueTimers.QueryContext(ctx, workerIdentity, asOf, batchSize, shardLo, shardHi, dueCutoff, leaseUntil)
+	rows, err = m.getDueTimers.QueryContext(ctx, workerIdentity, asOf, batchSize, shardLows, shardHighs, dueCutoff, leaseUntil, weightedShards, weights, claimOptions.maxPerKeyOrDefault(batchSize), claimOptions.candidatesPerKey(batchSize))
 	if err != nil {
 		return
 	}
@@ -333,10 +896,14 @@ func (m Manager) GetDueTimersWindowed(ctx context.Context, workerIdentity string
 	return
 }
 
+// execCullTimers also holds a late timer until its fired_utc passes the
+// cutoff, so it stays around long enough to be rolled up into
+// delivery_stats.
 var execCullTimers = fmt.Sprintf(`DELETE FROM %s 
 WHERE 
-	(delivered_utc IS NOT NULL OR attempt >= 5)
+	(delivered_utc IS NOT NULL OR expired_utc IS NOT NULL OR attempt >= 5)
 	AND due_utc < $1
+	AND (fired_utc IS NULL OR fired_utc < $1)
 `, timerTableName)
 
 func (m Manager) CullTimers(ctx context.Context, cutoff time.Time) (rowsAffected int64, err error) {
@@ -348,23 +915,78 @@ func (m Manager) CullTimers(ctx context.Context, cutoff time.Time) (rowsAffected
 	return
 }
 
-var execMarkAttempted = fmt.Sprintf(`UPDATE %s
-SET
-	delivered_status_code = $2
-	, delivered_err = $3
-	, retry_utc = $4::timestamp + interval '5 minutes'
-	, assigned_until_utc = NULL
+var queryGetCullableTimers = fmt.Sprintf(`SELECT
+	%s
+FROM
+	%s
 WHERE
-	id = $1
-	AND attempt < 5
+	(delivered_utc IS NOT NULL OR expired_utc IS NOT NULL OR attempt >= 5)
+	AND due_utc < $1
+	AND (fired_utc IS NULL OR fired_utc < $1)
+ORDER BY due_utc, id
+LIMIT $2
+`, db.ColumnNamesCSV(timerColumns), timerTableName)
+
+// GetCullableTimers returns up to limit of the timers CullTimers would
+// delete for the cutoff, oldest first, so they can be archived before
+// being deleted with BulkDeleteTimers.
+func (m Manager) GetCullableTimers(ctx context.Context, cutoff time.Time, limit int) (output []Timer, err error) {
+	var rows *sql.Rows
+	rows, err = m.getCullableTimers.QueryContext(ctx, cutoff, limit)
+	if err != nil {
+		return
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var t Timer
+		if err = db.PopulateInOrder(&t, rows, timerColumns); err != nil {
+			return
+		}
+		output = append(output, t)
+	}
+	err = rows.Err()
+	return
+}
+
+var execBulkDeleteTimers = fmt.Sprintf(`DELETE FROM %s WHERE id = ANY($1)`, timerTableName)
+
+func (m Manager) BulkDeleteTimers(ctx context.Context, ids []uuid.UUID) (rowsAffected int64, err error) {
+	res, err := m.bulkDeleteTimers.ExecContext(ctx, ids)
+	if err != nil {
+		return
+	}
+	rowsAffected, _ = res.RowsAffected()
+	return
+}
+
+// execMarkAttempted is fenced on the lease token like the bulk
+// completion writes; the final SELECT reads the pre-update snapshot and
+// reports whether the write was stale.
+var execMarkAttempted = fmt.Sprintf(`WITH marked AS (
+	UPDATE %[1]s
+	SET
+		delivered_status_code = $2
+		, delivered_err = $3
+		, retry_utc = $4::timestamp + interval '5 minutes'
+		, assigned_until_utc = NULL
+	WHERE
+		id = $1
+		AND lease_token = $5
+		AND attempt < 5
+	RETURNING id
+)
+SELECT NOT EXISTS (SELECT 1 FROM %[1]s WHERE id = $1 AND lease_token = $5)
 `, timerTableName)
 
-func (m Manager) MarkAttempted(ctx context.Context, id uuid.UUID, deliveredStatus uint32, deliveredErr error, asOf time.Time) (err error) {
+// MarkAttempted records a failed delivery for a claimed timer. stale is
+// true if the lease was lost (the timer was re-claimed or deleted) and
+// nothing was written.
+func (m Manager) MarkAttempted(ctx context.Context, lease TimerLease, deliveredStatus uint32, deliveredErr error, asOf time.Time) (stale bool, err error) {
 	var deliveredErrString string
 	if deliveredErr != nil {
 		deliveredErrString = deliveredErr.Error()
 	}
-	_, err = m.markAttempted.ExecContext(ctx, id, deliveredStatus, deliveredErrString, asOf)
+	err = m.markAttempted.QueryRowContext(ctx, lease.ID, deliveredStatus, deliveredErrString, asOf, lease.Token).Scan(&stale)
 	return
 }
 
@@ -373,26 +995,34 @@ func (m Manager) MarkAttempted(ctx context.Context, id uuid.UUID, deliveredStatu
 // so the wheel-mode flush loop can write one row per failure family
 // instead of one per timer; the per-timer execMarkAttempted above is
 // preserved for the legacy single-shot tick path.
-var execBulkMarkAttempted = fmt.Sprintf(`UPDATE %s
-SET
-	delivered_status_code = $1
-	, delivered_err = $2
-	, retry_utc = $3::timestamp + interval '5 minutes'
-	, assigned_until_utc = NULL
-WHERE
-	id = ANY($4)
-	AND attempt < 5
-`, timerTableName)
+var execBulkMarkAttempted = fmt.Sprintf(`WITH leases AS (
+	SELECT id, token FROM unnest($4::UUID[], $5::INT8[]) AS l(id, token)
+), marked AS (
+	UPDATE %[1]s
+	SET
+		delivered_status_code = $1
+		, delivered_err = $2
+		, retry_utc = $3::timestamp + interval '5 minutes'
+		, assigned_until_utc = NULL
+	WHERE
+		(id, lease_token) IN (SELECT id, token FROM leases)
+		AND attempt < 5
+	RETURNING id
+)
+%[2]s`, timerTableName, queryCountStaleLeases)
 
-func (m Manager) BulkMarkAttempted(ctx context.Context, deliveredStatus uint32, deliveredErr error, asOf time.Time, ids []uuid.UUID) (err error) {
-	if len(ids) == 0 {
+// BulkMarkAttempted records a failed delivery for a batch of claimed
+// timers, returning how many of the writes were stale.
+func (m Manager) BulkMarkAttempted(ctx context.Context, deliveredStatus uint32, deliveredErr error, asOf time.Time, leases []TimerLease) (stale int64, err error) {
+	if len(leases) == 0 {
 		return
 	}
 	var deliveredErrString string
 	if deliveredErr != nil {
 		deliveredErrString = deliveredErr.Error()
 	}
-	_, err = m.bulkMarkAttempted.ExecContext(ctx, deliveredStatus, deliveredErrString, asOf, ids)
+	ids, tokens := leaseBounds(leases)
+	err = m.bulkMarkAttempted.QueryRowContext(ctx, deliveredStatus, deliveredErrString, asOf, ids, tokens).Scan(&stale)
 	return
 }
 
@@ -400,26 +1030,74 @@ func (m Manager) BulkMarkAttempted(ctx context.Context, deliveredStatus uint32,
 // without recording an attempt. Used on graceful shutdown so peers can
 // reclaim un-fired wheel contents on the next tick instead of waiting
 // out the assigned_until_utc lease. Only relinquishes timers whose
-// claim is still held (assigned_until_utc in the future) to avoid
-// stomping a peer that already reclaimed them after our lease expired.
+// claim is still held (assigned_until_utc in the future, same lease
+// token) to avoid stomping a peer that already reclaimed them after our
+// lease expired.
 var execBulkRelinquish = fmt.Sprintf(`UPDATE %s
 SET
 	assigned_worker = NULL
 	, assigned_until_utc = NULL
 	, attempt = GREATEST(attempt - 1, 0)
 WHERE
-	id = ANY($1)
+	(id, lease_token) IN (SELECT * FROM unnest($1::UUID[], $4::INT8[]))
 	AND assigned_worker = $2
 	AND assigned_until_utc IS NOT NULL
 	AND assigned_until_utc > $3
 	AND delivered_utc IS NULL
 `, timerTableName)
 
-func (m Manager) BulkRelinquish(ctx context.Context, workerIdentity string, asOf time.Time, ids []uuid.UUID) (err error) {
-	if len(ids) == 0 {
+func (m Manager) BulkRelinquish(ctx context.Context, workerIdentity string, asOf time.Time, leases []TimerLease) (err error) {
+	if len(leases) == 0 {
 		return
 	}
-	_, err = m.bulkRelinquish.ExecContext(ctx, ids, workerIdentity, asOf)
+	ids, tokens := leaseBounds(leases)
+	_, err = m.bulkRelinquish.ExecContext(ctx, ids, workerIdentity, asOf, tokens)
+	return
+}
+
+// queryRenewLeases pushes assigned_until_utc out for timers a worker is
+// still holding. A lease is renewed only while the worker still owns it
+// by token, so a timer a peer has already re-claimed is never stolen
+// back; a lapsed lease nobody has re-claimed yet is safe to renew since
+// the token hasn't moved. Returns the ids that were not renewed.
+var queryRenewLeases = fmt.Sprintf(`WITH leases AS (
+	SELECT id, token FROM unnest($3::UUID[], $4::INT8[]) AS l(id, token)
+), renewed AS (
+	UPDATE %[1]s
+	SET
+		assigned_until_utc = $2
+	WHERE
+		(id, lease_token) IN (SELECT id, token FROM leases)
+		AND assigned_worker = $1
+		AND delivered_utc IS NULL
+		AND expired_utc IS NULL
+	RETURNING id
+)
+SELECT l.id FROM leases AS l WHERE l.id NOT IN (SELECT id FROM renewed)
+`, timerTableName)
+
+// RenewLeases extends the lease on timers the worker is still holding
+// to leaseUntil, returning the ids whose leases were lost (re-claimed by
+// a peer, completed, or deleted) and so should be dropped by the worker.
+func (m Manager) RenewLeases(ctx context.Context, workerIdentity string, leaseUntil time.Time, leases []TimerLease) (lost []uuid.UUID, err error) {
+	if len(leases) == 0 {
+		return
+	}
+	ids, tokens := leaseBounds(leases)
+	var rows *sql.Rows
+	rows, err = m.renewLeases.QueryContext(ctx, workerIdentity, leaseUntil, ids, tokens)
+	if err != nil {
+		return
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var id uuid.UUID
+		if err = rows.Scan(&id); err != nil {
+			return
+		}
+		lost = append(lost, id)
+	}
+	err = rows.Err()
 	return
 }
 
@@ -473,11 +1151,17 @@ func (m Manager) DeleteTimers(ctx context.Context, after, before time.Time, matc
 	return err
 }
 
-const execWorkerSeen = `INSERT INTO workers (hostname, created_utc, last_seen_utc)
-VALUES ($1, $2, $2) ON CONFLICT (hostname) DO UPDATE SET last_seen_utc = $2`
+const execWorkerSeen = `INSERT INTO workers (hostname, created_utc, last_seen_utc, capacity_weight, wheel_occupancy)
+VALUES ($1, $2, $2, $3, $4) ON CONFLICT (hostname) DO UPDATE SET last_seen_utc = $2, capacity_weight = $3, wheel_occupancy = $4`
 
-func (m Manager) WorkerSeen(ctx context.Context, workerHostname string, ts time.Time) (err error) {
-	_, err = m.workerSeen.ExecContext(ctx, workerHostname, ts)
+// WorkerSeen upserts the worker's heartbeat row, advertising its capacity
+// weight to peers for shard assignment and its wheel occupancy to the
+// controller.
+func (m Manager) WorkerSeen(ctx context.Context, workerHostname string, ts time.Time, capacityWeight int, wheelOccupancy float64) (err error) {
+	if capacityWeight < 1 {
+		capacityWeight = 1
+	}
+	_, err = m.workerSeen.ExecContext(ctx, workerHostname, ts, capacityWeight, wheelOccupancy)
 	return
 }
 
@@ -515,10 +1199,77 @@ func (m Manager) GetWorkers(ctx context.Context, asOf time.Time) (output []Worke
 // batch ops
 //
 
-const execBulkMarkDelivered = `UPDATE timers SET delivered_utc = $1 WHERE id = ANY($2)`
+// queryCountStaleLeases is the tail of each fenced completion write: a
+// lease is stale if the timer's token has moved on (a peer re-claimed
+// it after our lease expired) or the timer is gone. The SELECT reads the
+// statement's snapshot, which doesn't include the mutation CTE's writes,
+// and the writes never change lease_token anyway.
+const queryCountStaleLeases = `SELECT count(*) FROM leases AS l
+WHERE NOT EXISTS (SELECT 1 FROM timers AS t WHERE t.id = l.id AND t.lease_token = l.token)`
+
+// execBulkMarkDelivered marks a batch of claimed timers delivered,
+// recording when each was fired. Each write is conditional on
+// (id, lease_token) so a worker that paused past its lease can't mark a
+// row delivered after a peer re-claimed it.
+var execBulkMarkDelivered = fmt.Sprintf(`WITH leases AS (
+	SELECT id, token, fired FROM unnest($2::UUID[], $3::INT8[], $4::TIMESTAMP[]) AS l(id, token, fired)
+), marked AS (
+	UPDATE %[1]s AS t
+	SET delivered_utc = $1, fired_utc = l.fired
+	FROM leases AS l
+	WHERE t.id = l.id AND t.lease_token = l.token
+	RETURNING t.id
+)
+%[2]s`, timerTableName, queryCountStaleLeases)
+
+// BulkMarkDelivered marks a batch of claimed timers delivered, returning
+// how many of the writes were stale (the lease was lost).
+func (m Manager) BulkMarkDelivered(ctx context.Context, deliveredUTC time.Time, deliveries []TimerDelivery) (stale int64, err error) {
+	if len(deliveries) == 0 {
+		return
+	}
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	tokens := make([]int64, 0, len(deliveries))
+	fired := make([]time.Time, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+		tokens = append(tokens, d.Token)
+		if d.FiredUTC.IsZero() {
+			fired = append(fired, deliveredUTC)
+		} else {
+			fired = append(fired, d.FiredUTC.UTC())
+		}
+	}
+	err = m.bulkMarkDelivered.QueryRowContext(ctx, deliveredUTC, ids, tokens, fired).Scan(&stale)
+	return
+}
+
+// execBulkMarkExpired records that a batch of claimed timers were past
+// their expires_utc when the worker got to them. The hook is never sent;
+// clearing the lease drops the row out of the claim index (its predicate
+// excludes expired rows) and leaves it for the cull sweep.
+var execBulkMarkExpired = fmt.Sprintf(`WITH leases AS (
+	SELECT id, token FROM unnest($2::UUID[], $3::INT8[]) AS l(id, token)
+), marked AS (
+	UPDATE %[1]s
+	SET
+		expired_utc = $1
+		, assigned_until_utc = NULL
+	WHERE
+		(id, lease_token) IN (SELECT id, token FROM leases)
+		AND delivered_utc IS NULL
+	RETURNING id
+)
+%[2]s`, timerTableName, queryCountStaleLeases)
 
-func (m Manager) BulkMarkDelivered(ctx context.Context, deliveredUTC time.Time, ids []uuid.UUID) (err error) {
-	_, err = m.bulkMarkDelivered.ExecContext(ctx, deliveredUTC, ids)
+// BulkMarkExpired marks a batch of claimed timers expired, returning how
+// many of the writes were stale.
+func (m Manager) BulkMarkExpired(ctx context.Context, expiredUTC time.Time, leases []TimerLease) (stale int64, err error) {
+	if len(leases) == 0 {
+		return
+	}
+	ids, tokens := leaseBounds(leases)
+	err = m.bulkMarkExpired.QueryRowContext(ctx, expiredUTC, ids, tokens).Scan(&stale)
 	return
 }
 
@@ -527,6 +1278,7 @@ var queryGetPeakTimersDueCount = fmt.Sprintf(`SELECT COALESCE(MAX(cnt), 0) FROM
 	FROM %s
 	WHERE due_utc >= $1 AND due_utc < $2
 		AND delivered_utc IS NULL
+		AND expired_utc IS NULL
 		AND attempt < 5
 	GROUP BY floor(extract(epoch from due_utc) / $3)
 ) sub`, timerTableName)
@@ -536,10 +1288,16 @@ func (m Manager) GetPeakTimersDueCount(ctx context.Context, after, before time.T
 	return
 }
 
+// queryGetOverdueTimerCount excludes timers that are already expired or
+// will be by the time a worker claims them (expires_utc <= asOf); those
+// cost a single UPDATE to retire rather than a hook delivery, so they
+// shouldn't drive scale-up.
 var queryGetOverdueTimerCount = fmt.Sprintf(`SELECT count(*)
 FROM %s
 WHERE due_utc < $1
 	AND delivered_utc IS NULL
+	AND expired_utc IS NULL
+	AND (expires_utc IS NULL OR expires_utc > $1)
 	AND attempt < 5
 `, timerTableName)
 
@@ -547,3 +1305,125 @@ func (m Manager) GetOverdueTimerCount(ctx context.Context, asOf time.Time) (coun
 	err = m.getOverdueTimerCount.QueryRowContext(ctx, asOf).Scan(&count)
 	return
 }
+
+//
+// delivery stats
+//
+
+var queryGetTimerFiringsBetween = fmt.Sprintf(`SELECT shard_key, due_utc, fired_utc
+FROM %s
+WHERE fired_utc >= $1 AND fired_utc < $2
+`, timerTableName)
+
+var execUpsertDeliveryStats = fmt.Sprintf(`INSERT INTO %[1]s (%[2]s)
+SELECT * FROM unnest($1::TIMESTAMP[], $2::TEXT[], $3::INT8[], $4::INT8[], $5::INT8[], $6::INT8[])
+ON CONFLICT (bucket_utc, shard_key) DO UPDATE SET
+	delivery_count = excluded.delivery_count
+	, lateness_p50 = excluded.lateness_p50
+	, lateness_p99 = excluded.lateness_p99
+	, lateness_max = excluded.lateness_max
+`, deliveryStatsTableName, db.ColumnNamesCSV(deliveryStatsColumns))
+
+// RollupDeliveryStats summarizes the timers fired between after
+// (inclusive) and before (exclusive) into delivery_stats, replacing any
+// existing rows for the same buckets. after and before should be
+// aligned to DeliveryStatsBucket or the edge buckets are partial.
+func (m Manager) RollupDeliveryStats(ctx context.Context, after, before time.Time) (buckets int, err error) {
+	var rows *sql.Rows
+	rows, err = m.getTimerFiringsBetween.QueryContext(ctx, after, before)
+	if err != nil {
+		return
+	}
+	defer func() { _ = rows.Close() }()
+	var firings []timerFiring
+	for rows.Next() {
+		var f timerFiring
+		if err = rows.Scan(&f.ShardKey, &f.DueUTC, &f.FiredUTC); err != nil {
+			return
+		}
+		firings = append(firings, f)
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+	stats := summarizeLateness(firings)
+	if len(stats) == 0 {
+		return
+	}
+	bucketUTCs := make([]time.Time, 0, len(stats))
+	shardKeys := make([]string, 0, len(stats))
+	counts := make([]int64, 0, len(stats))
+	p50s := make([]int64, 0, len(stats))
+	p99s := make([]int64, 0, len(stats))
+	maxes := make([]int64, 0, len(stats))
+	for _, ds := range stats {
+		bucketUTCs = append(bucketUTCs, ds.BucketUTC)
+		shardKeys = append(shardKeys, ds.ShardKey)
+		counts = append(counts, ds.Count)
+		p50s = append(p50s, int64(ds.LatenessP50))
+		p99s = append(p99s, int64(ds.LatenessP99))
+		maxes = append(maxes, int64(ds.LatenessMax))
+	}
+	if _, err = m.upsertDeliveryStats.ExecContext(ctx, bucketUTCs, shardKeys, counts, p50s, p99s, maxes); err != nil {
+		return
+	}
+	buckets = len(stats)
+	return
+}
+
+var queryGetDeliveryStatsWatermark = fmt.Sprintf(`SELECT max(bucket_utc) FROM %s`, deliveryStatsTableName)
+
+// GetDeliveryStatsWatermark returns the latest bucket rolled up, if any.
+func (m Manager) GetDeliveryStatsWatermark(ctx context.Context) (latest time.Time, found bool, err error) {
+	var value sql.NullTime
+	if err = m.getDeliveryStatsWatermark.QueryRowContext(ctx).Scan(&value); err != nil {
+		return
+	}
+	latest, found = value.Time, value.Valid
+	return
+}
+
+var queryGetDeliveryStats = fmt.Sprintf(`SELECT
+	%s
+FROM
+	%s
+WHERE
+	bucket_utc >= $1 AND bucket_utc < $2
+ORDER BY bucket_utc, shard_key
+`, db.ColumnNamesCSV(deliveryStatsColumns), deliveryStatsTableName)
+
+// GetDeliveryStats returns the delivery stats buckets starting between
+// after (inclusive) and before (exclusive) whose shard key matches the
+// selector, in (bucket, shard key) order.
+func (m Manager) GetDeliveryStats(ctx context.Context, after, before time.Time, s selector.Selector) (output []DeliveryStats, err error) {
+	var rows *sql.Rows
+	rows, err = m.getDeliveryStats.QueryContext(ctx, after, before)
+	if err != nil {
+		return
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var ds DeliveryStats
+		if err = db.PopulateInOrder(&ds, rows, deliveryStatsColumns); err != nil {
+			return
+		}
+		if s == nil || s.Matches(ds.MatchLabels()) {
+			output = append(output, ds)
+		}
+	}
+	err = rows.Err()
+	return
+}
+
+var execCullDeliveryStats = fmt.Sprintf(`DELETE FROM %s WHERE bucket_utc < $1`, deliveryStatsTableName)
+
+// CullDeliveryStats deletes the delivery stats buckets older than the
+// cutoff.
+func (m Manager) CullDeliveryStats(ctx context.Context, cutoff time.Time) (rowsAffected int64, err error) {
+	res, err := m.cullDeliveryStats.ExecContext(ctx, cutoff)
+	if err != nil {
+		return
+	}
+	rowsAffected, _ = res.RowsAffected()
+	return
+}
@@ -2,15 +2,16 @@ package model
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sandman/pkg/utils"
-	"slices"
 	"testing"
 	"time"
 
 	"sandman/pkg/assert"
 	"sandman/pkg/db"
 	"sandman/pkg/db/dbutil"
+	"sandman/pkg/selector"
 	"sandman/pkg/testutil"
 	"sandman/pkg/uuid"
 )
@@ -86,7 +87,7 @@ func Test_Manager_GetDueTimers_byDueUTC(t *testing.T) {
 	})
 	assert.Nil(t, err)
 
-	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 10, 0, 1<<32)
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 10, AllShards())
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(timers))
 }
@@ -166,7 +167,7 @@ func Test_Manager_GetDueTimers_byAssignedUntilUTC(t *testing.T) {
 	})
 	assert.Nil(t, err)
 
-	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 10, 0, 1<<32)
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 10, AllShards())
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(timers))
 }
@@ -230,12 +231,12 @@ func Test_Manager_GetDueTimers_byRetryUTC(t *testing.T) {
 	})
 	assert.Nil(t, err)
 
-	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 10, 0, 1<<32)
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 10, AllShards())
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(timers))
 }
 
-func Test_Manager_GetDueTimers_ordersByShard(t *testing.T) {
+func Test_Manager_GetDueTimers_priorityWithinRound(t *testing.T) {
 	ctx := context.Background()
 	tx, err := testutil.DefaultDB().BeginTx(ctx)
 	assert.Nil(t, err)
@@ -253,140 +254,96 @@ func Test_Manager_GetDueTimers_ordersByShard(t *testing.T) {
 
 	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
 
-	t00 := Timer{
-		Name:   "test-timer-00",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_bufoco")),
-		Priority:   10,
-	}
-	err = modelMgr.Invoke(ctx).Create(&t00)
-	assert.Nil(t, err)
+	// one timer per shard key, so every timer is in the first round and
+	// priority alone decides who makes the cut.
+	t00 := createDueTimers(t, modelMgr, "uk_bufoco", 1, now, 10)[0]
+	t01 := createDueTimers(t, modelMgr, "uk_not_bufoco", 1, now, 400000)[0]
+	t02 := createDueTimers(t, modelMgr, "uk_also_not_bufoco", 1, now, 20)[0]
+	t03 := createDueTimers(t, modelMgr, "uk_not_aswell_bufoco", 1, now.Add(-time.Minute), 10)[0]
+	_ = createDueTimers(t, modelMgr, "uk_not_sortof_aswell_bufoco", 1, now, 5)[0]
 
-	t01 := Timer{
-		Name:   "test-timer-01",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_not_bufoco")),
-		Priority:   10,
-	}
-	err = modelMgr.Invoke(ctx).Create(&t01)
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 3, AllShards())
 	assert.Nil(t, err)
+	assert.Equal(t, 3, len(timers))
 
-	t02 := Timer{
-		Name:   "test-timer-02",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_also_not_bufoco")),
-		Priority:   10,
-	}
-	err = modelMgr.Invoke(ctx).Create(&t02)
-	assert.Nil(t, err)
+	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t01.ID) })
+	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t02.ID) })
+	// t00 and t03 tie on priority; the more overdue one wins.
+	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t03.ID) })
+	assert.All(t, timers, func(t Timer) bool { return !t.ID.Equal(t00.ID) })
+}
 
-	t03 := Timer{
-		Name:   "test-timer-03",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_not_aswell_bufoco")),
-		Priority:   10,
-	}
-	err = modelMgr.Invoke(ctx).Create(&t03)
+func Test_Manager_GetDueTimers_fairAcrossShardKeys(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
 	assert.Nil(t, err)
+	defer tx.Rollback()
 
-	t04 := Timer{
-		Name:   "test-timer-04",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_not_sortof_aswell_bufoco")),
-		Priority:   10,
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
 	}
-	err = modelMgr.Invoke(ctx).Create(&t04)
+	err = modelMgr.Initialize(ctx)
 	assert.Nil(t, err)
+	defer modelMgr.Close()
 
-	asOf := now.Add(3 * time.Hour)
-	pseudoPriorities := []pseudoPriority{
-		computePseudoPriority(asOf, &t00),
-		computePseudoPriority(asOf, &t01),
-		computePseudoPriority(asOf, &t02),
-		computePseudoPriority(asOf, &t03),
-		computePseudoPriority(asOf, &t04),
-	}
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
 
-	slices.SortFunc(pseudoPriorities, func(i, j pseudoPriority) int {
-		if i.Priority < j.Priority {
-			return 1
-		} else if i.Priority == j.Priority {
-			return 0
-		}
-		return -1
-	})
+	// the noisy tenant is older, higher priority and far larger; under
+	// a plain priority / due ordering it would take every slot.
+	noisy := createDueTimers(t, modelMgr, "uk_noisy", 100, now.Add(-time.Hour), 1000)
+	quietA := createDueTimers(t, modelMgr, "uk_quiet_a", 1, now, 0)
+	quietB := createDueTimers(t, modelMgr, "uk_quiet_b", 2, now, 0)
 
-	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", asOf, 3, 0, 1<<32)
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(time.Minute), 3, AllShards())
 	assert.Nil(t, err)
 	assert.Equal(t, 3, len(timers))
+	assert.Equal(t, 1, countByShardKey(timers, noisy[0].ShardKey))
+	assert.Equal(t, 1, countByShardKey(timers, quietA[0].ShardKey))
+	assert.Equal(t, 1, countByShardKey(timers, quietB[0].ShardKey))
 
-	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t01.ID) })
-	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t02.ID) })
-	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t03.ID) })
-
-	assert.Equal(t, t01.ID, pseudoPriorities[0].ID)
-	assert.Equal(t, t02.ID, pseudoPriorities[2].ID)
-	assert.Equal(t, t03.ID, pseudoPriorities[1].ID)
+	// bounded starvation: the quiet keys drain within as many claims as
+	// they have timers, no matter how deep the noisy backlog is.
+	timers, err = modelMgr.GetDueTimers(ctx, "test-worker", now.Add(time.Minute), 3, AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(timers))
+	assert.Equal(t, 2, countByShardKey(timers, noisy[0].ShardKey))
+	assert.Equal(t, 1, countByShardKey(timers, quietB[0].ShardKey))
 }
 
-type pseudoPriority struct {
-	ID       uuid.UUID
-	Priority uint32
-}
+func Test_Manager_GetDueTimers_keyWeights(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
 
-func computePseudoPriority(asOf time.Time, t *Timer) (output pseudoPriority) {
-	output.ID = t.ID
-	output.Priority += t.Priority
-
-	// the idea here is we split each hour into 60 minutes and 60 seconds
-	// for 3600 total shards.
-	// we then assign the shard to a "bucket" based on the current minute and second, and offset
-	// the bucket by the shard assignment onto 3600.
-	// we then multiply by 100 giving us 0 -> 36,0000 possible "boost" to
-	// the priority based on the shuffle shard
-	bucket := uint32(asOf.Minute()) * uint32(asOf.Second())
-	shardWeight := ((t.Shard % 3600) + bucket) % 3600
-	output.Priority += shardWeight * 100
-
-	// boost by how many seconds the timer is past its due time so older
-	// (more overdue) timers drain before newer ones.
-	if overdue := asOf.Sub(t.DueUTC); overdue > 0 {
-		output.Priority += uint32(overdue/time.Second) * 100
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
 	}
-	return
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+
+	heavy := createDueTimers(t, modelMgr, "uk_heavy", 20, now, 0)
+	light := createDueTimers(t, modelMgr, "uk_light", 20, now, 0)
+
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(time.Minute), 8, AllShards(),
+		OptClaimKeyWeights(map[string]uint32{"uk_heavy": 3}),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 8, len(timers))
+	assert.Equal(t, 6, countByShardKey(timers, heavy[0].ShardKey))
+	assert.Equal(t, 2, countByShardKey(timers, light[0].ShardKey))
 }
 
-func Test_Manager_GetDueTimers_ordersByShard_boosted(t *testing.T) {
+func Test_Manager_GetDueTimers_maxPerKey(t *testing.T) {
 	ctx := context.Background()
 	tx, err := testutil.DefaultDB().BeginTx(ctx)
 	assert.Nil(t, err)
@@ -404,88 +361,126 @@ func Test_Manager_GetDueTimers_ordersByShard_boosted(t *testing.T) {
 
 	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
 
-	t00 := Timer{
-		Name:   "test-timer-00",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_bufoco")),
-		Priority:   10,
-	}
-	err = modelMgr.Invoke(ctx).Create(&t00)
+	noisy := createDueTimers(t, modelMgr, "uk_noisy", 50, now, 0)
+	quiet := createDueTimers(t, modelMgr, "uk_quiet", 1, now, 0)
+
+	// even with spare capacity in the batch the noisy key is held to
+	// its cap, leaving the remainder of the backlog for later ticks (or
+	// for the worker that owns the rest of the batch budget).
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(time.Minute), 20, AllShards(),
+		OptClaimMaxPerKey(4),
+	)
 	assert.Nil(t, err)
+	assert.Equal(t, 5, len(timers))
+	assert.Equal(t, 4, countByShardKey(timers, noisy[0].ShardKey))
+	assert.Equal(t, 1, countByShardKey(timers, quiet[0].ShardKey))
+}
 
-	t01 := Timer{
-		Name:   "test-timer-01",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_not_bufoco")),
-		Priority:   10,
-	}
-	err = modelMgr.Invoke(ctx).Create(&t01)
+func Test_Manager_GetDueTimers_orderingKey(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
 	assert.Nil(t, err)
+	defer tx.Rollback()
 
-	t02 := Timer{
-		Name:   "test-timer-02",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_also_not_bufoco")),
-		Priority:   400000, // this means it's beyond the 360,000 maximum boost of shuffling
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
 	}
-	err = modelMgr.Invoke(ctx).Create(&t02)
+	err = modelMgr.Initialize(ctx)
 	assert.Nil(t, err)
+	defer modelMgr.Close()
 
-	t03 := Timer{
-		Name:   "test-timer-03",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_not_aswell_bufoco")),
-		Priority:   10,
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+
+	ordered := make([]Timer, 3)
+	for x := range ordered {
+		ordered[x] = Timer{
+			Name:        fmt.Sprintf("test-timer-order-1234-%d", x),
+			DueUTC:      now.Add(time.Duration(x-3) * time.Minute),
+			CreatedUTC:  now,
+			OrderingKey: "order-1234",
+			Shard:       StableHash([]byte("order-1234")),
+			// later timers have higher priority; ordering must still win.
+			Priority: uint32(x * 1000),
+		}
+		err = modelMgr.Invoke(ctx).Create(&ordered[x])
+		assert.Nil(t, err)
 	}
-	err = modelMgr.Invoke(ctx).Create(&t03)
-	assert.Nil(t, err)
+	unordered := createDueTimers(t, modelMgr, "uk_unordered", 2, now.Add(-time.Minute), 0)
 
-	t04 := Timer{
-		Name:   "test-timer-04",
-		DueUTC: now,
-		Labels: map[string]string{
-			"service": "test-service",
-			"env":     "prod",
-			"region":  "us-east-1",
-		},
-		CreatedUTC: now,
-		Shard:      StableHash([]byte("uk_not_sortof_aswell_bufoco")),
-		Priority:   10,
+	asOf := now.Add(time.Minute)
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", asOf, 10, AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(timers))
+	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(ordered[0].ID) })
+	assert.Equal(t, 2, countByShardKey(timers, unordered[0].ShardKey))
+	var head TimerLease
+	for _, claimed := range timers {
+		if claimed.ID.Equal(ordered[0].ID) {
+			head = claimed.Lease()
+		}
 	}
-	err = modelMgr.Invoke(ctx).Create(&t04)
+
+	// the head is leased but not delivered; nothing behind it may go.
+	timers, err = modelMgr.GetDueTimers(ctx, "test-worker", asOf, 10, AllShards())
 	assert.Nil(t, err)
+	assert.Equal(t, 0, len(timers))
 
-	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", now.Add(3*time.Hour), 3, 0, 1<<32)
+	stale, err := modelMgr.BulkMarkDelivered(ctx, asOf, []TimerDelivery{{TimerLease: head}})
 	assert.Nil(t, err)
-	assert.Equal(t, 3, len(timers))
+	assert.Zero(t, stale)
 
-	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t01.ID) })
-	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t02.ID) })
-	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(t03.ID) })
+	timers, err = modelMgr.GetDueTimers(ctx, "test-worker", asOf, 10, AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(timers))
+	assert.Equal(t, ordered[1].ID, timers[0].ID)
+
+	// a failed attempt keeps the key blocked until the retry succeeds or
+	// the timer is exhausted.
+	attemptStale, err := modelMgr.MarkAttempted(ctx, timers[0].Lease(), 500, nil, asOf)
+	assert.Nil(t, err)
+	assert.False(t, attemptStale)
+	timers, err = modelMgr.GetDueTimers(ctx, "test-worker", asOf, 10, AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(timers))
+
+	_, err = modelMgr.Invoke(ctx).Exec(fmt.Sprintf("UPDATE %s SET attempt = 5 WHERE id = $1", timerTableName), ordered[1].ID)
+	assert.Nil(t, err)
+	timers, err = modelMgr.GetDueTimers(ctx, "test-worker", asOf, 10, AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(timers))
+	assert.Equal(t, ordered[2].ID, timers[0].ID)
+}
+
+// createDueTimers inserts count timers for shardKey, all due at due with
+// the given priority, and returns them in insertion order.
+func createDueTimers(t *testing.T, modelMgr *Manager, shardKey string, count int, due time.Time, priority uint32) []Timer {
+	t.Helper()
+	output := make([]Timer, count)
+	for x := 0; x < count; x++ {
+		output[x] = Timer{
+			Name:       fmt.Sprintf("test-timer-%s-%d", shardKey, x),
+			DueUTC:     due,
+			CreatedUTC: due,
+			Priority:   priority,
+			ShardKey:   shardKey,
+			Shard:      StableHash([]byte(shardKey)),
+		}
+		err := modelMgr.Invoke(context.Background()).Create(&output[x])
+		assert.Nil(t, err)
+	}
+	return output
+}
+
+func countByShardKey(timers []Timer, shardKey string) (count int) {
+	for _, t := range timers {
+		if t.ShardKey == shardKey {
+			count++
+		}
+	}
+	return
 }
 
 func Test_Manager_BulkMarkDelivered(t *testing.T) {
@@ -522,21 +517,22 @@ func Test_Manager_BulkMarkDelivered(t *testing.T) {
 		assert.Nil(t, err)
 	}
 
-	var ids = []uuid.UUID{
-		timers[0].ID,
-		timers[5].ID,
-		timers[10].ID,
-		timers[15].ID,
-		timers[20].ID,
-		timers[25].ID,
-		timers[30].ID,
-		timers[35].ID,
-		timers[40].ID,
-		timers[45].ID,
+	var deliveries = []TimerDelivery{
+		timers[0].Delivery(now.Add(time.Hour + time.Second)),
+		timers[5].Delivery(now.Add(time.Hour + time.Second)),
+		timers[10].Delivery(now.Add(time.Hour + time.Second)),
+		timers[15].Delivery(now.Add(time.Hour + time.Second)),
+		timers[20].Delivery(now.Add(time.Hour + time.Second)),
+		timers[25].Delivery(now.Add(time.Hour + time.Second)),
+		timers[30].Delivery(now.Add(time.Hour + time.Second)),
+		timers[35].Delivery(now.Add(time.Hour + time.Second)),
+		timers[40].Delivery(now.Add(time.Hour + time.Second)),
+		timers[45].Delivery(now.Add(time.Hour + time.Second)),
 	}
 
-	err = modelMgr.BulkMarkDelivered(ctx, now.Add(time.Hour), ids)
+	stale, err := modelMgr.BulkMarkDelivered(ctx, now.Add(time.Hour+2*time.Second), deliveries)
 	assert.Nil(t, err)
+	assert.Zero(t, stale)
 
 	var verifyTimers []Timer
 	err = modelMgr.Invoke(ctx).All(&verifyTimers)
@@ -556,6 +552,119 @@ func Test_Manager_BulkMarkDelivered(t *testing.T) {
 	assert.Any(t, verifyTimers, func(t Timer) bool { return t.ID.Equal(timers[35].ID) && t.DeliveredUTC != nil })
 	assert.Any(t, verifyTimers, func(t Timer) bool { return t.ID.Equal(timers[40].ID) && t.DeliveredUTC != nil })
 	assert.Any(t, verifyTimers, func(t Timer) bool { return t.ID.Equal(timers[45].ID) && t.DeliveredUTC != nil })
+	assert.Any(t, verifyTimers, func(t Timer) bool {
+		return t.ID.Equal(timers[0].ID) && t.FiredUTC != nil && t.FiredUTC.Equal(now.Add(time.Hour+time.Second).Truncate(time.Microsecond))
+	})
+}
+
+func Test_Manager_LeaseFencing(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
+
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
+	}
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+	createDueTimers(t, modelMgr, "tenant-a", 2, now.Add(-time.Minute), 0)
+
+	zombie, err := modelMgr.GetDueTimers(ctx, "worker-zombie", now, 10, AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, zombie, 2)
+	assert.All(t, zombie, func(t Timer) bool { return t.LeaseToken == 1 })
+
+	// the zombie pauses past its lease (and the retry backoff) and a peer
+	// re-claims both timers.
+	reclaimAt := now.Add(10 * time.Minute)
+	peer, err := modelMgr.GetDueTimers(ctx, "worker-peer", reclaimAt, 10, AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, peer, 2)
+	assert.All(t, peer, func(t Timer) bool { return t.LeaseToken == 2 })
+
+	stale, err := modelMgr.BulkMarkDelivered(ctx, reclaimAt, []TimerDelivery{zombie[0].Delivery(reclaimAt)})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), stale)
+
+	stale, err = modelMgr.BulkMarkAttempted(ctx, 500, nil, reclaimAt, []TimerLease{zombie[1].Lease()})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), stale)
+
+	staleAttempt, err := modelMgr.MarkAttempted(ctx, zombie[1].Lease(), 500, nil, reclaimAt)
+	assert.Nil(t, err)
+	assert.True(t, staleAttempt)
+
+	stale, err = modelMgr.BulkMarkExpired(ctx, reclaimAt, []TimerLease{zombie[0].Lease(), zombie[1].Lease()})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), stale)
+
+	var verify []Timer
+	err = modelMgr.Invoke(ctx).All(&verify)
+	assert.Nil(t, err)
+	assert.All(t, verify, func(t Timer) bool {
+		return t.DeliveredUTC == nil && t.ExpiredUTC == nil && t.DeliveredStatusCode == 0
+	}, "stale writes must not land")
+
+	// the peer holds the current leases, so its writes go through.
+	stale, err = modelMgr.BulkMarkDelivered(ctx, reclaimAt, []TimerDelivery{peer[0].Delivery(reclaimAt), peer[1].Delivery(reclaimAt)})
+	assert.Nil(t, err)
+	assert.Zero(t, stale)
+
+	verify = nil
+	err = modelMgr.Invoke(ctx).All(&verify)
+	assert.Nil(t, err)
+	assert.All(t, verify, func(t Timer) bool { return t.DeliveredUTC != nil })
+}
+
+func Test_Manager_RenewLeases(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
+
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
+	}
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+	createDueTimers(t, modelMgr, "tenant-a", 3, now.Add(-time.Minute), 0)
+
+	held, err := modelMgr.GetDueTimers(ctx, "worker-a", now, 10, AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, held, 3)
+
+	// one timer is delivered, another is re-claimed by a peer after
+	// worker-a's lease lapsed; only the third can still be renewed.
+	_, err = modelMgr.BulkMarkDelivered(ctx, now, []TimerDelivery{held[0].Delivery(now)})
+	assert.Nil(t, err)
+	_, err = modelMgr.Invoke(ctx).Exec(fmt.Sprintf("UPDATE %s SET assigned_worker = 'worker-b', lease_token = lease_token + 1 WHERE id = $1", timerTableName), held[1].ID)
+	assert.Nil(t, err)
+
+	leaseUntil := now.Add(5 * time.Minute)
+	lost, err := modelMgr.RenewLeases(ctx, "worker-a", leaseUntil, []TimerLease{held[0].Lease(), held[1].Lease(), held[2].Lease()})
+	assert.Nil(t, err)
+	assert.ItsLen(t, lost, 2)
+	assert.Any(t, lost, func(id uuid.UUID) bool { return id.Equal(held[0].ID) })
+	assert.Any(t, lost, func(id uuid.UUID) bool { return id.Equal(held[1].ID) })
+
+	var verify Timer
+	_, err = modelMgr.Invoke(ctx).Get(&verify, held[2].ID)
+	assert.Nil(t, err)
+	assert.NotNil(t, verify.AssignedUntilUTC)
+	assert.True(t, verify.AssignedUntilUTC.Equal(leaseUntil))
 }
 
 func Test_Manager_WorkerSeen(t *testing.T) {
@@ -575,10 +684,10 @@ func Test_Manager_WorkerSeen(t *testing.T) {
 	defer modelMgr.Close()
 
 	ts := time.Now().UTC()
-	err = modelMgr.WorkerSeen(ctx, "worker-00", ts)
+	err = modelMgr.WorkerSeen(ctx, "worker-00", ts, 1, 0)
 	assert.Nil(t, err)
 
-	err = modelMgr.WorkerSeen(ctx, "worker-01", ts)
+	err = modelMgr.WorkerSeen(ctx, "worker-01", ts, 1, 0)
 	assert.Nil(t, err)
 
 	var workers []Worker
@@ -588,10 +697,10 @@ func Test_Manager_WorkerSeen(t *testing.T) {
 	assert.Nil(t, err)
 	assert.ItsLen(t, workers, 2)
 
-	err = modelMgr.WorkerSeen(ctx, "worker-00", ts)
+	err = modelMgr.WorkerSeen(ctx, "worker-00", ts, 1, 0)
 	assert.Nil(t, err)
 
-	err = modelMgr.WorkerSeen(ctx, "worker-01", ts)
+	err = modelMgr.WorkerSeen(ctx, "worker-01", ts, 1, 0)
 	assert.Nil(t, err)
 
 	var verify []Worker
@@ -617,16 +726,302 @@ func Test_Manager_GetWorkers(t *testing.T) {
 	defer modelMgr.Close()
 
 	ts := time.Now().UTC()
-	err = modelMgr.WorkerSeen(ctx, "worker-00", ts)
+	err = modelMgr.WorkerSeen(ctx, "worker-00", ts, 1, 0)
 	assert.Nil(t, err)
 
-	err = modelMgr.WorkerSeen(ctx, "worker-01", ts)
+	err = modelMgr.WorkerSeen(ctx, "worker-01", ts, 3, 0.5)
 	assert.Nil(t, err)
 
-	err = modelMgr.WorkerSeen(ctx, "worker-02", ts.Add(-time.Minute))
+	err = modelMgr.WorkerSeen(ctx, "worker-02", ts.Add(-time.Minute), 1, 0)
 	assert.Nil(t, err)
 
 	workers, err := modelMgr.GetWorkers(ctx, ts.Add(-30*time.Second))
 	assert.Nil(t, err)
 	assert.ItsLen(t, workers, 2)
+	for _, w := range workers {
+		if w.Hostname == "worker-01" {
+			assert.Equal(t, 3, w.CapacityWeight)
+			assert.Equal(t, 0.5, w.WheelOccupancy)
+		} else {
+			assert.Equal(t, 1, w.CapacityWeight)
+			assert.Zero(t, w.WheelOccupancy)
+		}
+	}
+}
+
+func Test_Manager_BulkMarkExpired(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
+
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
+	}
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+
+	fresh := Timer{
+		Name:       "test-timer-fresh",
+		DueUTC:     now,
+		ExpiresUTC: utils.Ref(now.Add(time.Hour)),
+		CreatedUTC: now,
+	}
+	err = modelMgr.Invoke(ctx).Create(&fresh)
+	assert.Nil(t, err)
+
+	stale := Timer{
+		Name:       "test-timer-stale",
+		DueUTC:     now,
+		ExpiresUTC: utils.Ref(now.Add(time.Minute)),
+		CreatedUTC: now,
+	}
+	err = modelMgr.Invoke(ctx).Create(&stale)
+	assert.Nil(t, err)
+
+	asOf := now.Add(5 * time.Minute)
+
+	// the stale timer is already past its expiry, so it should not count
+	// towards the overdue backlog even before a worker marks it.
+	overdue, err := modelMgr.GetOverdueTimerCount(ctx, asOf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), overdue)
+
+	timers, err := modelMgr.GetDueTimers(ctx, "test-worker", asOf, 10, AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(timers))
+	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(stale.ID) && t.IsExpired(asOf) })
+	assert.Any(t, timers, func(t Timer) bool { return t.ID.Equal(fresh.ID) && !t.IsExpired(asOf) })
+	var expired []TimerLease
+	for _, claimed := range timers {
+		if claimed.ID.Equal(stale.ID) {
+			expired = append(expired, claimed.Lease())
+		}
+	}
+
+	staleWrites, err := modelMgr.BulkMarkExpired(ctx, asOf, expired)
+	assert.Nil(t, err)
+	assert.Zero(t, staleWrites)
+
+	var verify Timer
+	_, err = modelMgr.Invoke(ctx).Get(&verify, stale.ID)
+	assert.Nil(t, err)
+	assert.NotNil(t, verify.ExpiredUTC)
+	assert.Nil(t, verify.DeliveredUTC)
+	assert.Nil(t, verify.AssignedUntilUTC)
+
+	// once expired the timer must never be reclaimed, even after its
+	// retry and lease windows pass.
+	timers, err = modelMgr.GetDueTimers(ctx, "test-worker", asOf.Add(time.Hour), 10, AllShards())
+	assert.Nil(t, err)
+	assert.All(t, timers, func(t Timer) bool { return !t.ID.Equal(stale.ID) })
+}
+
+func Test_Manager_ExportTimers(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
+
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
+	}
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+	pending := createDueTimers(t, modelMgr, "tenant-a", exportPageSize+5, now, 0)
+	delivered := pending[0]
+	_, err = modelMgr.BulkMarkDelivered(ctx, now, []TimerDelivery{delivered.Delivery(now)})
+	assert.Nil(t, err)
+	exhausted := Timer{
+		Name:       "test-timer-exhausted",
+		DueUTC:     now,
+		CreatedUTC: now,
+		Attempt:    5,
+		ShardKey:   "tenant-a",
+		Shard:      StableHash([]byte("tenant-a")),
+	}
+	assert.Nil(t, modelMgr.CreateTimer(ctx, &exhausted))
+
+	var exported []Timer
+	err = modelMgr.ExportTimers(ctx, now.Add(-time.Hour), now.Add(time.Hour), nil, func(t Timer) error {
+		exported = append(exported, t)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, len(pending)-1, len(exported), "export should page past the first page and skip delivered and exhausted timers")
+	assert.All(t, exported, func(t Timer) bool { return !t.ID.Equal(delivered.ID) && !t.ID.Equal(exhausted.ID) })
+}
+
+func Test_Manager_ImportTimer(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
+
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
+	}
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+	imported := Timer{
+		Name:       "test-timer-import",
+		Labels:     map[string]string{"env": "prod"},
+		Priority:   10,
+		ShardKey:   "tenant-a",
+		DueUTC:     now,
+		CreatedUTC: now,
+		HookURL:    "https://example.com/hook",
+	}
+	result, err := modelMgr.ImportTimer(ctx, &imported, ImportConflictFail)
+	assert.Nil(t, err)
+	assert.Equal(t, ImportCreated, result)
+	assert.False(t, imported.ID.IsZero())
+
+	again := imported
+	_, err = modelMgr.ImportTimer(ctx, &again, ImportConflictFail)
+	assert.True(t, errors.Is(err, ErrTimerNameExists))
+
+	result, err = modelMgr.ImportTimer(ctx, &again, ImportConflictSkip)
+	assert.Nil(t, err)
+	assert.Equal(t, ImportSkipped, result)
+
+	replacement := imported
+	replacement.Priority = 20
+	replacement.HookURL = "https://example.com/other"
+	result, err = modelMgr.ImportTimer(ctx, &replacement, ImportConflictReplace)
+	assert.Nil(t, err)
+	assert.Equal(t, ImportReplaced, result)
+	assert.True(t, replacement.ID.Equal(imported.ID))
+
+	verify, found, err := modelMgr.GetTimerByName(ctx, imported.Name)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint32(20), verify.Priority)
+	assert.Equal(t, "https://example.com/other", verify.HookURL)
+}
+
+func Test_Manager_ExportDeliveredTimers_replay(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
+
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
+	}
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 18, 17, time.UTC)
+	timers := createDueTimers(t, modelMgr, "tenant-a", exportPageSize+3, now, 0)
+	deliveries := make([]TimerDelivery, 0, len(timers)-1)
+	for _, timer := range timers[:len(timers)-1] {
+		deliveries = append(deliveries, timer.Delivery(now.Add(time.Minute)))
+	}
+	_, err = modelMgr.BulkMarkDelivered(ctx, now.Add(time.Minute), deliveries)
+	assert.Nil(t, err)
+
+	var delivered []Timer
+	err = modelMgr.ExportDeliveredTimers(ctx, now, now.Add(time.Hour), nil, func(t Timer) error {
+		delivered = append(delivered, t)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, len(deliveries), len(delivered), "export should page past the first page and skip undelivered timers")
+	assert.All(t, delivered, func(t Timer) bool { return !t.ID.Equal(timers[len(timers)-1].ID) })
+
+	replay := delivered[0].Replay("abc123", now.Add(time.Hour), now.Add(2*time.Hour))
+	err = modelMgr.Invoke(ctx).Create(&replay)
+	assert.Nil(t, err)
+	assert.Equal(t, delivered[0].Name+"-replay-abc123", replay.Name)
+	assert.Equal(t, delivered[0].ID.String(), replay.Labels[LabelReplayedFrom])
+	assert.Equal(t, delivered[0].Shard, replay.Shard)
+	assert.Nil(t, replay.DeliveredUTC)
+}
+
+func Test_Manager_RollupDeliveryStats(t *testing.T) {
+	ctx := context.Background()
+	tx, err := testutil.DefaultDB().BeginTx(ctx)
+	assert.Nil(t, err)
+	defer tx.Rollback()
+
+	modelMgr := &Manager{
+		BaseManager: dbutil.NewBaseManager(
+			testutil.DefaultDB(),
+			db.OptTx(tx),
+		),
+	}
+	err = modelMgr.Initialize(ctx)
+	assert.Nil(t, err)
+	defer modelMgr.Close()
+
+	now := time.Date(2024, 10, 19, 20, 19, 0, 0, time.UTC)
+	tenantA := createDueTimers(t, modelMgr, "tenant-a", 3, now, 0)
+	tenantB := createDueTimers(t, modelMgr, "tenant-b", 1, now.Add(time.Minute), 0)
+	_, err = modelMgr.BulkMarkDelivered(ctx, now.Add(2*time.Minute), []TimerDelivery{
+		tenantA[0].Delivery(now.Add(100 * time.Millisecond)),
+		tenantA[1].Delivery(now.Add(200 * time.Millisecond)),
+		tenantA[2].Delivery(now.Add(3 * time.Second)),
+		tenantB[0].Delivery(now.Add(time.Minute + 500*time.Millisecond)),
+	})
+	assert.Nil(t, err)
+
+	_, found, err := modelMgr.GetDeliveryStatsWatermark(ctx)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	// rolling up twice replaces rather than double counts.
+	for range 2 {
+		buckets, err := modelMgr.RollupDeliveryStats(ctx, now, now.Add(2*time.Minute))
+		assert.Nil(t, err)
+		assert.Equal(t, 2, buckets)
+	}
+
+	watermark, found, err := modelMgr.GetDeliveryStatsWatermark(ctx)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, now.Add(time.Minute), watermark)
+
+	sel, err := selector.Parse("shard_key=tenant-a")
+	assert.Nil(t, err)
+	stats, err := modelMgr.GetDeliveryStats(ctx, now, now.Add(time.Hour), sel)
+	assert.Nil(t, err)
+	assert.ItsLen(t, stats, 1)
+	assert.Equal(t, now, stats[0].BucketUTC)
+	assert.Equal(t, int64(3), stats[0].Count)
+	assert.Equal(t, 200*time.Millisecond, stats[0].LatenessP50)
+	assert.Equal(t, 3*time.Second, stats[0].LatenessP99)
+	assert.Equal(t, 3*time.Second, stats[0].LatenessMax)
+
+	stats, err = modelMgr.GetDeliveryStats(ctx, now, now.Add(time.Hour), nil)
+	assert.Nil(t, err)
+	assert.ItsLen(t, stats, 2)
+	assert.Equal(t, "tenant-b", stats[1].ShardKey)
+	assert.Equal(t, 500*time.Millisecond, stats[1].LatenessMax)
+
+	culled, err := modelMgr.CullDeliveryStats(ctx, now.Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), culled)
 }
@@ -0,0 +1,743 @@
+package model
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"sandman/pkg/selector"
+	"sandman/pkg/uuid"
+)
+
+// maxAttempts is the retry budget; a timer attempted this many times is
+// no longer claimed. It mirrors the `attempt < 5` in the claim index
+// predicate.
+const maxAttempts = 5
+
+// retryDelay is how long a claimed or failed timer waits before it can
+// be claimed again, the `interval '5 minutes'` in the claim and
+// attempt queries.
+const retryDelay = 5 * time.Minute
+
+// ErrTimerIDExists is returned by MemoryStore.CreateTimer when a timer
+// with the same id is already held.
+var ErrTimerIDExists = errors.New("timer id already exists")
+
+// NewMemoryStore returns an empty in-memory timer store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		timers:  make(map[uuid.UUID]*Timer),
+		names:   make(map[string]uuid.UUID),
+		workers: make(map[string]Worker),
+		stats:   make(map[deliveryStatsKey]DeliveryStats),
+	}
+}
+
+// MemoryStore is a TimerStore held in process memory.
+//
+// It implements the same claim, lease and retry semantics as Manager
+// under a single lock, so every call is atomic with respect to every
+// other; nothing is persisted. Timers are copied on the way in and out
+// so callers never share state with the store.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	timers  map[uuid.UUID]*Timer
+	names   map[string]uuid.UUID
+	workers map[string]Worker
+	stats   map[deliveryStatsKey]DeliveryStats
+}
+
+type deliveryStatsKey struct {
+	bucketUTC time.Time
+	shardKey  string
+}
+
+//
+// timers
+//
+
+// CreateTimer inserts a timer, setting its ID if it wasn't already set.
+func (ms *MemoryStore) CreateTimer(_ context.Context, t *Timer) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.names[t.Name]; ok {
+		return fmt.Errorf("%w; %q", ErrTimerNameExists, t.Name)
+	}
+	if t.ID.IsZero() {
+		t.ID = uuid.V4()
+	} else if _, ok := ms.timers[t.ID]; ok {
+		return fmt.Errorf("%w; %v", ErrTimerIDExists, t.ID)
+	}
+	ms.putLocked(cloneTimer(*t))
+	return nil
+}
+
+// GetTimerByID returns the timer with the given id, if any.
+func (ms *MemoryStore) GetTimerByID(_ context.Context, id uuid.UUID) (out Timer, found bool, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	if t, ok := ms.timers[id]; ok {
+		out, found = cloneTimer(*t), true
+	}
+	return
+}
+
+// GetTimerByName returns the timer with the given name, if any.
+func (ms *MemoryStore) GetTimerByName(_ context.Context, name string) (out Timer, found bool, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	if id, ok := ms.names[name]; ok {
+		out, found = cloneTimer(*ms.timers[id]), true
+	}
+	return
+}
+
+// GetTimersDueBetween returns the timers due between after and before
+// that match the selector, in due order.
+func (ms *MemoryStore) GetTimersDueBetween(_ context.Context, after, before time.Time, s selector.Selector) ([]Timer, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	output := ms.filterLocked(func(t *Timer) bool {
+		return t.DueUTC.After(after) && t.DueUTC.Before(before) && matchesSelector(s, t.MatchLabels())
+	})
+	slices.SortFunc(output, compareDue)
+	return output, nil
+}
+
+// ExportTimers calls fn for every pending timer due between after and
+// before that matches the selector, in due order.
+//
+// The timers are copied out before fn is called, so fn may call back
+// into the store.
+func (ms *MemoryStore) ExportTimers(_ context.Context, after, before time.Time, s selector.Selector, fn func(Timer) error) error {
+	ms.mu.RLock()
+	pending := ms.filterLocked(func(t *Timer) bool {
+		return t.DeliveredUTC == nil && t.ExpiredUTC == nil && t.Attempt < maxAttempts && t.DueUTC.After(after) && t.DueUTC.Before(before) && matchesSelector(s, t.MatchLabels())
+	})
+	ms.mu.RUnlock()
+	slices.SortFunc(pending, compareDue)
+	for _, t := range pending {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportDeliveredTimers calls fn for every timer delivered between
+// after and before that matches the selector, in delivery order.
+//
+// The timers are copied out before fn is called, so fn may call back
+// into the store.
+func (ms *MemoryStore) ExportDeliveredTimers(_ context.Context, after, before time.Time, s selector.Selector, fn func(Timer) error) error {
+	ms.mu.RLock()
+	delivered := ms.filterLocked(func(t *Timer) bool {
+		return t.DeliveredUTC != nil && t.DeliveredUTC.After(after) && t.DeliveredUTC.Before(before) && matchesSelector(s, t.MatchLabels())
+	})
+	ms.mu.RUnlock()
+	slices.SortFunc(delivered, func(a, b Timer) int {
+		if c := a.DeliveredUTC.Compare(*b.DeliveredUTC); c != 0 {
+			return c
+		}
+		return a.ID.Compare(b.ID)
+	})
+	for _, t := range delivered {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportTimer creates a timer, resolving a name conflict with the given
+// policy. On success the timer's ID is set to the created (or replaced)
+// timer's id.
+//
+// Like Manager, an imported timer is always given a fresh id, and a
+// replace keeps the id of the timer it replaces and bumps its lease
+// token, fencing off any worker holding a lease on it.
+func (ms *MemoryStore) ImportTimer(_ context.Context, t *Timer, onConflict ImportConflict) (ImportResult, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if existingID, ok := ms.names[t.Name]; ok {
+		switch onConflict {
+		case ImportConflictSkip:
+			return ImportSkipped, nil
+		case ImportConflictReplace:
+			t.ID = existingID
+			t.LeaseToken = ms.timers[existingID].LeaseToken + 1
+			ms.putLocked(cloneTimer(*t))
+			return ImportReplaced, nil
+		default:
+			return ImportCreated, fmt.Errorf("%w; %q", ErrTimerNameExists, t.Name)
+		}
+	}
+	t.ID = uuid.V4()
+	ms.putLocked(cloneTimer(*t))
+	return ImportCreated, nil
+}
+
+// DeleteTimerByID deletes the timer with the given id.
+func (ms *MemoryStore) DeleteTimerByID(_ context.Context, id uuid.UUID) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.deleteLocked(id), nil
+}
+
+// DeleteTimerByName deletes the timer with the given name.
+func (ms *MemoryStore) DeleteTimerByName(_ context.Context, name string) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	id, ok := ms.names[name]
+	if !ok {
+		return false, nil
+	}
+	return ms.deleteLocked(id), nil
+}
+
+//
+// claims and leases
+//
+
+// GetDueTimers claims up to batchSize timers due as of asOf whose shard
+// falls in one of the given ranges; see Manager.GetDueTimers.
+func (ms *MemoryStore) GetDueTimers(ctx context.Context, workerIdentity string, asOf time.Time, batchSize int, shards []ShardRange, opts ...ClaimOption) ([]Timer, error) {
+	return ms.GetDueTimersWindowed(ctx, workerIdentity, asOf, batchSize, shards, 0, defaultLeaseSeconds, opts...)
+}
+
+// GetDueTimersWindowed claims up to batchSize timers due before
+// asOf+windowSeconds, leasing them until asOf+leaseSeconds; see
+// Manager.GetDueTimersWindowed for the selection rules, which this
+// follows step for step.
+func (ms *MemoryStore) GetDueTimersWindowed(_ context.Context, workerIdentity string, asOf time.Time, batchSize int, shards []ShardRange, windowSeconds, leaseSeconds int, opts ...ClaimOption) ([]Timer, error) {
+	if leaseSeconds <= 0 {
+		leaseSeconds = defaultLeaseSeconds
+	}
+	var claimOptions ClaimOptions
+	for _, opt := range opts {
+		opt(&claimOptions)
+	}
+	weights := make(map[uint32]int)
+	weightedShards, shardWeights := claimOptions.shardWeights()
+	for index, shard := range weightedShards {
+		weights[uint32(shard)] = int(shardWeights[index])
+	}
+	maxPerKey := claimOptions.maxPerKeyOrDefault(batchSize)
+	candidatesPerKey := claimOptions.candidatesPerKey(batchSize)
+	dueCutoff := asOf.Add(time.Duration(windowSeconds) * time.Second)
+	leaseUntil := asOf.Add(time.Duration(leaseSeconds) * time.Second)
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	// candidates, partitioned by shard
+	heads := ms.orderingHeadsLocked()
+	byShard := make(map[uint32][]*Timer)
+	for _, t := range ms.timers {
+		if !isReady(t) || !InShardRanges(t.Shard, shards) || !t.DueUTC.Before(dueCutoff) {
+			continue
+		}
+		if t.AssignedUntilUTC != nil && !t.AssignedUntilUTC.Before(asOf) {
+			continue
+		}
+		if t.RetryUTC != nil && !t.RetryUTC.Before(asOf) {
+			continue
+		}
+		if t.OrderingKey != "" && heads[orderingStream{shard: t.Shard, orderingKey: t.OrderingKey}] != t {
+			continue
+		}
+		byShard[t.Shard] = append(byShard[t.Shard], t)
+	}
+
+	// weighted round-robin across shard keys
+	type ranked struct {
+		*Timer
+		round int
+	}
+	var selected []ranked
+	for shard, candidates := range byShard {
+		// each key's earliest due timers, then ranked by priority.
+		slices.SortFunc(candidates, func(a, b *Timer) int { return compareDue(*a, *b) })
+		candidates = candidates[:min(len(candidates), candidatesPerKey)]
+		slices.SortFunc(candidates, compareClaimOrder)
+		weight := cmp.Or(weights[shard], DefaultClaimKeyWeight)
+		for index, t := range candidates {
+			keyRank := index + 1
+			if keyRank > maxPerKey*weight {
+				break
+			}
+			selected = append(selected, ranked{Timer: t, round: index / weight})
+		}
+	}
+	slices.SortFunc(selected, func(a, b ranked) int {
+		if c := cmp.Compare(a.round, b.round); c != 0 {
+			return c
+		}
+		return compareClaimOrder(a.Timer, b.Timer)
+	})
+	if len(selected) > batchSize {
+		selected = selected[:max(batchSize, 0)]
+	}
+
+	output := make([]Timer, 0, len(selected))
+	for _, t := range selected {
+		assignedWorker := workerIdentity
+		retryUTC := asOf.Add(retryDelay)
+		t.AssignedWorker = &assignedWorker
+		t.Attempt++
+		t.LeaseToken++
+		t.AssignedUntilUTC = &leaseUntil
+		t.RetryUTC = &retryUTC
+		output = append(output, cloneTimer(*t.Timer))
+	}
+	return output, nil
+}
+
+// RenewLeases extends the leases the worker still holds to leaseUntil,
+// returning the ids whose leases were lost.
+func (ms *MemoryStore) RenewLeases(_ context.Context, workerIdentity string, leaseUntil time.Time, leases []TimerLease) (lost []uuid.UUID, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, l := range leases {
+		t, ok := ms.leasedLocked(l)
+		if !ok || !isAssignedTo(t, workerIdentity) || t.DeliveredUTC != nil || t.ExpiredUTC != nil {
+			lost = append(lost, l.ID)
+			continue
+		}
+		t.AssignedUntilUTC = &leaseUntil
+	}
+	return
+}
+
+// BulkRelinquish drops the worker's still-held claims on a batch of
+// timers without recording an attempt.
+func (ms *MemoryStore) BulkRelinquish(_ context.Context, workerIdentity string, asOf time.Time, leases []TimerLease) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, l := range leases {
+		t, ok := ms.leasedLocked(l)
+		if !ok || !isAssignedTo(t, workerIdentity) || t.AssignedUntilUTC == nil || !t.AssignedUntilUTC.After(asOf) || t.DeliveredUTC != nil {
+			continue
+		}
+		t.AssignedWorker = nil
+		t.AssignedUntilUTC = nil
+		if t.Attempt > 0 {
+			t.Attempt--
+		}
+	}
+	return nil
+}
+
+//
+// outcomes
+//
+
+// MarkAttempted records a failed delivery for a claimed timer, returning
+// true if the lease was lost and nothing was written.
+func (ms *MemoryStore) MarkAttempted(_ context.Context, lease TimerLease, deliveredStatus uint32, deliveredErr error, asOf time.Time) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	t, ok := ms.leasedLocked(lease)
+	if !ok {
+		return true, nil
+	}
+	markAttempted(t, deliveredStatus, deliveredErr, asOf)
+	return false, nil
+}
+
+// BulkMarkAttempted records a failed delivery for a batch of claimed
+// timers, returning how many of the writes were stale.
+func (ms *MemoryStore) BulkMarkAttempted(_ context.Context, deliveredStatus uint32, deliveredErr error, asOf time.Time, leases []TimerLease) (stale int64, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, l := range leases {
+		t, ok := ms.leasedLocked(l)
+		if !ok {
+			stale++
+			continue
+		}
+		markAttempted(t, deliveredStatus, deliveredErr, asOf)
+	}
+	return
+}
+
+// BulkMarkDelivered marks a batch of claimed timers delivered, returning
+// how many of the writes were stale.
+func (ms *MemoryStore) BulkMarkDelivered(_ context.Context, deliveredUTC time.Time, deliveries []TimerDelivery) (stale int64, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, d := range deliveries {
+		t, ok := ms.leasedLocked(d.TimerLease)
+		if !ok {
+			stale++
+			continue
+		}
+		firedUTC := deliveredUTC
+		if !d.FiredUTC.IsZero() {
+			firedUTC = d.FiredUTC.UTC()
+		}
+		t.DeliveredUTC = &deliveredUTC
+		t.FiredUTC = &firedUTC
+	}
+	return
+}
+
+// BulkMarkExpired marks a batch of claimed timers expired, returning how
+// many of the writes were stale.
+func (ms *MemoryStore) BulkMarkExpired(_ context.Context, expiredUTC time.Time, leases []TimerLease) (stale int64, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, l := range leases {
+		t, ok := ms.leasedLocked(l)
+		if !ok {
+			stale++
+			continue
+		}
+		if t.DeliveredUTC != nil {
+			continue
+		}
+		t.ExpiredUTC = &expiredUTC
+		t.AssignedUntilUTC = nil
+	}
+	return
+}
+
+//
+// culling
+//
+
+// CullTimers deletes the finished timers due before the cutoff.
+func (ms *MemoryStore) CullTimers(_ context.Context, cutoff time.Time) (rowsAffected int64, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for id, t := range ms.timers {
+		if isCullable(t, cutoff) {
+			ms.deleteLocked(id)
+			rowsAffected++
+		}
+	}
+	return
+}
+
+// GetCullableTimers returns up to limit of the timers CullTimers would
+// delete for the cutoff, oldest first.
+func (ms *MemoryStore) GetCullableTimers(_ context.Context, cutoff time.Time, limit int) ([]Timer, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	output := ms.filterLocked(func(t *Timer) bool {
+		return isCullable(t, cutoff)
+	})
+	slices.SortFunc(output, compareDue)
+	if len(output) > limit {
+		output = output[:max(limit, 0)]
+	}
+	return output, nil
+}
+
+// BulkDeleteTimers deletes the timers with the given ids.
+func (ms *MemoryStore) BulkDeleteTimers(_ context.Context, ids []uuid.UUID) (rowsAffected int64, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, id := range ids {
+		if ms.deleteLocked(id) {
+			rowsAffected++
+		}
+	}
+	return
+}
+
+//
+// worker membership
+//
+
+// WorkerSeen upserts the worker's heartbeat.
+func (ms *MemoryStore) WorkerSeen(_ context.Context, workerHostname string, ts time.Time, capacityWeight int, wheelOccupancy float64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	w, ok := ms.workers[workerHostname]
+	if !ok {
+		w = Worker{Hostname: workerHostname, CreatedUTC: ts}
+	}
+	w.LastSeenUTC = ts
+	w.CapacityWeight = max(capacityWeight, 1)
+	w.WheelOccupancy = wheelOccupancy
+	ms.workers[workerHostname] = w
+	return nil
+}
+
+// DeleteWorker removes a worker's heartbeat.
+func (ms *MemoryStore) DeleteWorker(_ context.Context, hostname string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	delete(ms.workers, hostname)
+	return nil
+}
+
+// GetWorkers returns the workers seen after asOf, by hostname.
+func (ms *MemoryStore) GetWorkers(_ context.Context, asOf time.Time) (output []Worker, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	for _, w := range ms.workers {
+		if w.LastSeenUTC.After(asOf) {
+			output = append(output, w)
+		}
+	}
+	slices.SortFunc(output, func(a, b Worker) int {
+		return cmp.Compare(a.Hostname, b.Hostname)
+	})
+	return
+}
+
+//
+// counts
+//
+
+// GetPeakTimersDueCount returns the most pending timers due within any
+// one bucketSeconds wide bucket between after and before.
+func (ms *MemoryStore) GetPeakTimersDueCount(_ context.Context, after, before time.Time, bucketSeconds float64) (count int64, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	buckets := make(map[float64]int64)
+	for _, t := range ms.timers {
+		if !isReady(t) || t.DueUTC.Before(after) || !t.DueUTC.Before(before) {
+			continue
+		}
+		bucket := math.Floor(float64(t.DueUTC.UnixNano()) / float64(time.Second) / bucketSeconds)
+		buckets[bucket]++
+		count = max(count, buckets[bucket])
+	}
+	return
+}
+
+// GetOverdueTimerCount returns how many pending timers were due before
+// asOf and won't have expired by then.
+func (ms *MemoryStore) GetOverdueTimerCount(_ context.Context, asOf time.Time) (count int64, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	for _, t := range ms.timers {
+		if isReady(t) && t.DueUTC.Before(asOf) && !t.IsExpired(asOf) {
+			count++
+		}
+	}
+	return
+}
+
+//
+// delivery stats
+//
+
+// RollupDeliveryStats summarizes the timers fired between after
+// (inclusive) and before (exclusive), replacing any existing stats for
+// the same buckets.
+func (ms *MemoryStore) RollupDeliveryStats(_ context.Context, after, before time.Time) (int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var firings []timerFiring
+	for _, t := range ms.timers {
+		if t.FiredUTC == nil || t.FiredUTC.Before(after) || !t.FiredUTC.Before(before) {
+			continue
+		}
+		firings = append(firings, timerFiring{ShardKey: t.ShardKey, DueUTC: t.DueUTC, FiredUTC: *t.FiredUTC})
+	}
+	stats := summarizeLateness(firings)
+	for _, ds := range stats {
+		ms.stats[deliveryStatsKey{bucketUTC: ds.BucketUTC, shardKey: ds.ShardKey}] = ds
+	}
+	return len(stats), nil
+}
+
+// GetDeliveryStatsWatermark returns the latest bucket rolled up, if any.
+func (ms *MemoryStore) GetDeliveryStatsWatermark(_ context.Context) (latest time.Time, found bool, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	for k := range ms.stats {
+		if !found || k.bucketUTC.After(latest) {
+			latest, found = k.bucketUTC, true
+		}
+	}
+	return
+}
+
+// GetDeliveryStats returns the buckets starting between after
+// (inclusive) and before (exclusive) whose shard key matches the
+// selector, in (bucket, shard key) order.
+func (ms *MemoryStore) GetDeliveryStats(_ context.Context, after, before time.Time, s selector.Selector) (output []DeliveryStats, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	for _, ds := range ms.stats {
+		if ds.BucketUTC.Before(after) || !ds.BucketUTC.Before(before) || !matchesSelector(s, ds.MatchLabels()) {
+			continue
+		}
+		output = append(output, ds)
+	}
+	slices.SortFunc(output, func(a, b DeliveryStats) int {
+		if c := a.BucketUTC.Compare(b.BucketUTC); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ShardKey, b.ShardKey)
+	})
+	return
+}
+
+// CullDeliveryStats deletes the buckets older than the cutoff.
+func (ms *MemoryStore) CullDeliveryStats(_ context.Context, cutoff time.Time) (rowsAffected int64, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for k := range ms.stats {
+		if k.bucketUTC.Before(cutoff) {
+			delete(ms.stats, k)
+			rowsAffected++
+		}
+	}
+	return
+}
+
+//
+// helpers
+//
+
+// putLocked stores t, replacing any timer with the same id.
+func (ms *MemoryStore) putLocked(t Timer) {
+	if prev, ok := ms.timers[t.ID]; ok && prev.Name != t.Name {
+		delete(ms.names, prev.Name)
+	}
+	ms.timers[t.ID] = &t
+	ms.names[t.Name] = t.ID
+}
+
+func (ms *MemoryStore) deleteLocked(id uuid.UUID) bool {
+	t, ok := ms.timers[id]
+	if !ok {
+		return false
+	}
+	delete(ms.names, t.Name)
+	delete(ms.timers, id)
+	return true
+}
+
+// leasedLocked returns the timer a lease refers to if the lease is still
+// current, i.e. the timer exists and hasn't been re-claimed since.
+func (ms *MemoryStore) leasedLocked(l TimerLease) (*Timer, bool) {
+	t, ok := ms.timers[l.ID]
+	if !ok || t.LeaseToken != l.Token {
+		return nil, false
+	}
+	return t, true
+}
+
+func (ms *MemoryStore) filterLocked(fn func(*Timer) bool) (output []Timer) {
+	for _, t := range ms.timers {
+		if fn(t) {
+			output = append(output, cloneTimer(*t))
+		}
+	}
+	return
+}
+
+type orderingStream struct {
+	shard       uint32
+	orderingKey string
+}
+
+// orderingHeadsLocked returns the earliest ready timer of each (shard,
+// ordering key) stream. Only the head of a stream is claimable; every
+// timer behind it waits until it is delivered, expired or out of
+// attempts.
+func (ms *MemoryStore) orderingHeadsLocked() map[orderingStream]*Timer {
+	heads := make(map[orderingStream]*Timer)
+	for _, t := range ms.timers {
+		if t.OrderingKey == "" || !isReady(t) {
+			continue
+		}
+		k := orderingStream{shard: t.Shard, orderingKey: t.OrderingKey}
+		if head, ok := heads[k]; !ok || compareDue(*t, *head) < 0 {
+			heads[k] = t
+		}
+	}
+	return heads
+}
+
+// isReady mirrors the claim index predicate: the timer is neither
+// delivered nor expired and has attempts left.
+func isReady(t *Timer) bool {
+	return t.DeliveredUTC == nil && t.ExpiredUTC == nil && t.Attempt < maxAttempts
+}
+
+func isCullable(t *Timer, cutoff time.Time) bool {
+	return (t.DeliveredUTC != nil || t.ExpiredUTC != nil || t.Attempt >= maxAttempts) &&
+		t.DueUTC.Before(cutoff) &&
+		(t.FiredUTC == nil || t.FiredUTC.Before(cutoff))
+}
+
+func isAssignedTo(t *Timer, workerIdentity string) bool {
+	return t.AssignedWorker != nil && *t.AssignedWorker == workerIdentity
+}
+
+func markAttempted(t *Timer, deliveredStatus uint32, deliveredErr error, asOf time.Time) {
+	if t.Attempt >= maxAttempts {
+		return
+	}
+	t.DeliveredStatusCode = deliveredStatus
+	t.DeliveredErr = ""
+	if deliveredErr != nil {
+		t.DeliveredErr = deliveredErr.Error()
+	}
+	retryUTC := asOf.Add(retryDelay)
+	t.RetryUTC = &retryUTC
+	t.AssignedUntilUTC = nil
+}
+
+func matchesSelector(s selector.Selector, labels map[string]string) bool {
+	return s == nil || s.Matches(labels)
+}
+
+// compareDue orders timers by (due_utc, id).
+func compareDue(a, b Timer) int {
+	if c := a.DueUTC.Compare(b.DueUTC); c != 0 {
+		return c
+	}
+	return a.ID.Compare(b.ID)
+}
+
+// compareClaimOrder orders timers by (priority DESC, due_utc, id), the
+// order a claim ranks timers within a shard key and breaks ties within
+// a round.
+func compareClaimOrder(a, b *Timer) int {
+	if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+		return c
+	}
+	return compareDue(*a, *b)
+}
+
+// cloneTimer copies a timer so it shares no maps or pointers with the
+// original.
+func cloneTimer(t Timer) Timer {
+	t.Labels = maps.Clone(t.Labels)
+	t.HookHeaders = maps.Clone(t.HookHeaders)
+	t.HookBody = slices.Clone(t.HookBody)
+	t.AssignedUntilUTC = cloneTime(t.AssignedUntilUTC)
+	t.RetryUTC = cloneTime(t.RetryUTC)
+	t.ExpiresUTC = cloneTime(t.ExpiresUTC)
+	t.DeliveredUTC = cloneTime(t.DeliveredUTC)
+	t.FiredUTC = cloneTime(t.FiredUTC)
+	t.ExpiredUTC = cloneTime(t.ExpiredUTC)
+	if t.AssignedWorker != nil {
+		assignedWorker := *t.AssignedWorker
+		t.AssignedWorker = &assignedWorker
+	}
+	return t
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := *t
+	return &value
+}
@@ -1,6 +1,11 @@
 package model
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"sandman/pkg/db"
 	"sandman/pkg/db/dbgen"
 	"sandman/pkg/db/migration"
 )
@@ -21,6 +26,53 @@ func Migrations(opts ...migration.SuiteOption) *migration.Suite {
 						dbgen.Index(Worker{}, "last_seen_utc"),
 					),
 				),
+				migration.NewGroupWithAction(
+					dbgen.TableFrom(
+						DeliveryStats{},
+					),
+				),
+				migration.NewGroupWithStep(
+					migration.ColumnNotExists("timers", "expires_utc"),
+					migration.Statements(
+						`ALTER TABLE timers ADD COLUMN expires_utc TIMESTAMP`,
+					),
+				),
+				migration.NewGroupWithStep(
+					migration.ColumnNotExists("timers", "expired_utc"),
+					migration.Statements(
+						`ALTER TABLE timers ADD COLUMN expired_utc TIMESTAMP`,
+					),
+				),
+				migration.NewGroupWithStep(
+					migration.ColumnNotExists("timers", "lease_token"),
+					migration.Statements(
+						`ALTER TABLE timers ADD COLUMN lease_token INT8 NOT NULL DEFAULT 0`,
+					),
+				),
+				migration.NewGroupWithStep(
+					migration.ColumnNotExists("timers", "fired_utc"),
+					migration.Statements(
+						`ALTER TABLE timers ADD COLUMN fired_utc TIMESTAMP`,
+					),
+				),
+				migration.NewGroupWithStep(
+					migration.ColumnNotExists("workers", "capacity_weight"),
+					migration.Statements(
+						`ALTER TABLE workers ADD COLUMN capacity_weight INT8 NOT NULL DEFAULT 1`,
+					),
+				),
+				migration.NewGroupWithStep(
+					migration.ColumnNotExists("workers", "wheel_occupancy"),
+					migration.Statements(
+						`ALTER TABLE workers ADD COLUMN wheel_occupancy FLOAT8 NOT NULL DEFAULT 0`,
+					),
+				),
+				migration.NewGroupWithStep(
+					migration.ColumnNotExists("timers", "ordering_key"),
+					migration.Statements(
+						`ALTER TABLE timers ADD COLUMN ordering_key TEXT NOT NULL DEFAULT ''`,
+					),
+				),
 				// The partial index is keyed (shard, due_utc) rather than
 				// (due_utc) so writes spread across shard-prefix ranges
 				// from the first insert instead of piling onto the tail
@@ -29,41 +81,116 @@ func Migrations(opts ...migration.SuiteOption) *migration.Suite {
 				// the allocator has to wait for load-based splits, during
 				// which every insert hammers one leaseholder.
 				//
+				// The predicate also excludes expired timers so rows a
+				// worker marked expired drop out of the claim scan the
+				// same way delivered and exhausted rows do, and the index
+				// stores ordering_key so the ordered-delivery check can
+				// tell unordered rows apart without a primary lookup.
+				//
 				// SkipTransaction because mikoshi DDL is async: CREATE
 				// INDEX returns after the descriptor is written but the
 				// index isn't PUBLIC until the backfill job finishes.
 				// A follow-up SPLIT in the same transaction hangs
 				// waiting on a view of the index the txn will never see.
+				//
+				// Postgres has no ranges to split; the same covering
+				// columns go in an INCLUDE.
 				migration.NewGroupWithStep(
-					migration.IndexNotExists("timers", "ix_timers_shard_due_utc_pending"),
-					migration.Statements(
-						// STORING the lease/retry/priority columns lets the
-						// claim CTE evaluate its assigned_until/retry filter
-						// directly off the index. Without this the planner
-						// inserts a lookup-join to the primary index, which
-						// under FOR UPDATE means we lock every candidate
-						// row just to discover it's already leased — and
-						// that lock blocks the concurrent BulkMarkDelivered
-						// UPDATE on the same primary range.
-						`CREATE INDEX ix_timers_shard_due_utc_pending ON timers (shard, due_utc) STORING (assigned_until_utc, retry_utc, priority) WHERE delivered_utc IS NULL AND attempt < 5`,
-						`ALTER INDEX timers@ix_timers_shard_due_utc_pending SPLIT EVENLY FROM (0) TO (4294967296) INTO 16`,
-						`ALTER INDEX timers@ix_timers_shard_due_utc_pending SCATTER`,
+					migration.IndexNotExists("timers", "ix_timers_shard_due_utc_ready"),
+					dialectStatements(
+						[]string{
+							// STORING the lease/retry/priority columns lets the
+							// claim CTE evaluate its assigned_until/retry filter
+							// directly off the index. Without this the planner
+							// inserts a lookup-join to the primary index, which
+							// under FOR UPDATE means we lock every candidate
+							// row just to discover it's already leased — and
+							// that lock blocks the concurrent BulkMarkDelivered
+							// UPDATE on the same primary range.
+							`CREATE INDEX ix_timers_shard_due_utc_ready ON timers (shard, due_utc) STORING (assigned_until_utc, retry_utc, priority, ordering_key) WHERE delivered_utc IS NULL AND expired_utc IS NULL AND attempt < 5`,
+							`ALTER INDEX timers@ix_timers_shard_due_utc_ready SPLIT EVENLY FROM (0) TO (4294967296) INTO 16`,
+							`ALTER INDEX timers@ix_timers_shard_due_utc_ready SCATTER`,
+						},
+						[]string{
+							`CREATE INDEX ix_timers_shard_due_utc_ready ON timers (shard, due_utc) INCLUDE (assigned_until_utc, retry_utc, priority, ordering_key) WHERE delivered_utc IS NULL AND expired_utc IS NULL AND attempt < 5`,
+						},
 					),
 					migration.OptGroupSkipTransaction(),
 				),
+				// ix_timers_ordering_pending answers "is there an earlier
+				// undelivered timer for this ordering key?" for the claim
+				// query. It only covers ordered rows, so unordered
+				// workloads pay nothing for it.
 				migration.NewGroupWithStep(
-					migration.IndexExists("timers", "ix_timers_due_utc_pending"),
+					migration.IndexNotExists("timers", "ix_timers_ordering_pending"),
 					migration.Statements(
-						`DROP INDEX timers@ix_timers_due_utc_pending`,
+						`CREATE INDEX ix_timers_ordering_pending ON timers (shard, ordering_key, due_utc, id) WHERE ordering_key != '' AND delivered_utc IS NULL AND expired_utc IS NULL AND attempt < 5`,
 					),
+					migration.OptGroupSkipTransaction(),
 				),
+				// ix_timers_fired_utc feeds the delivery stats rollup,
+				// which reads the last few minutes of firings every
+				// cull pass.
 				migration.NewGroupWithStep(
-					migration.IndexExists("timers", "ix_timers_due_utc_attempt_delivered_utc_assigned_until_utc_retry_utc"),
+					migration.IndexNotExists("timers", "ix_timers_fired_utc"),
+					dialectStatements(
+						[]string{
+							`CREATE INDEX ix_timers_fired_utc ON timers (fired_utc) STORING (shard_key, due_utc) WHERE fired_utc IS NOT NULL`,
+						},
+						[]string{
+							`CREATE INDEX ix_timers_fired_utc ON timers (fired_utc) INCLUDE (shard_key, due_utc) WHERE fired_utc IS NOT NULL`,
+						},
+					),
+					migration.OptGroupSkipTransaction(),
+				),
+				// ix_timers_delivered_utc lets a replay page through a
+				// delivery range without scanning the table.
+				migration.NewGroupWithStep(
+					migration.IndexNotExists("timers", "ix_timers_delivered_utc"),
 					migration.Statements(
-						`DROP INDEX timers@ix_timers_due_utc_attempt_delivered_utc_assigned_until_utc_retry_utc`,
+						`CREATE INDEX ix_timers_delivered_utc ON timers (delivered_utc, id) WHERE delivered_utc IS NOT NULL`,
 					),
+					migration.OptGroupSkipTransaction(),
+				),
+				migration.NewGroupWithStep(
+					migration.IndexExists("timers", "ix_timers_shard_due_utc_pending"),
+					dropIndex("timers", "ix_timers_shard_due_utc_pending"),
+				),
+				migration.NewGroupWithStep(
+					migration.IndexExists("timers", "ix_timers_due_utc_pending"),
+					dropIndex("timers", "ix_timers_due_utc_pending"),
+				),
+				migration.NewGroupWithStep(
+					migration.IndexExists("timers", "ix_timers_due_utc_attempt_delivered_utc_assigned_until_utc_retry_utc"),
+					dropIndex("timers", "ix_timers_due_utc_attempt_delivered_utc_assigned_until_utc_retry_utc"),
 				),
 			),
 		)...,
 	)
 }
+
+// isPostgres returns if the connection is to stock Postgres rather than
+// CockroachDB, which is what an unknown dialect is assumed to be.
+func isPostgres(conn *db.Connection) bool {
+	return conn != nil && db.Dialect(conn.Dialect).Is(db.DialectPostgres)
+}
+
+// dialectStatements runs the crdb statements against CockroachDB and the
+// postgres statements against Postgres.
+func dialectStatements(crdb, postgres []string) migration.Action {
+	return migration.ActionFunc(func(ctx context.Context, c *db.Connection, tx *sql.Tx) error {
+		if isPostgres(c) {
+			return migration.Statements(postgres...).Action(ctx, c, tx)
+		}
+		return migration.Statements(crdb...).Action(ctx, c, tx)
+	})
+}
+
+// dropIndex drops an index; cockroachdb names it by table, Postgres by
+// schema.
+func dropIndex(tableName, indexName string) migration.Action {
+	return dialectStatements(
+		[]string{fmt.Sprintf("DROP INDEX %s@%s", tableName, indexName)},
+		[]string{fmt.Sprintf("DROP INDEX %s", indexName)},
+	)
+}
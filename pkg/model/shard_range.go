@@ -0,0 +1,37 @@
+package model
+
+// ShardSpace is one past the max uint32 shard value; uint64 so the upper
+// bound is expressible without overflow.
+const ShardSpace uint64 = 1 << 32
+
+// ShardRange is a half-open [Lo, Hi) slice of the shard space.
+type ShardRange struct {
+	Lo, Hi uint64
+}
+
+// AllShards returns the whole shard space as a single range.
+func AllShards() []ShardRange {
+	return []ShardRange{{Lo: 0, Hi: ShardSpace}}
+}
+
+// InShardRanges reports whether shard falls in any of the ranges.
+func InShardRanges(shard uint32, ranges []ShardRange) bool {
+	for _, r := range ranges {
+		if uint64(shard) >= r.Lo && uint64(shard) < r.Hi {
+			return true
+		}
+	}
+	return false
+}
+
+// shardRangeBounds splits ranges into the parallel low / high arrays the
+// claim query unnests.
+func shardRangeBounds(ranges []ShardRange) (lows, highs []int64) {
+	lows = make([]int64, 0, len(ranges))
+	highs = make([]int64, 0, len(ranges))
+	for _, r := range ranges {
+		lows = append(lows, int64(r.Lo))
+		highs = append(highs, int64(r.Hi))
+	}
+	return
+}
@@ -11,3 +11,17 @@ func StableHash(data []byte) uint32 {
 	hash := md5.Sum(data)
 	return binary.BigEndian.Uint32(hash[4:])
 }
+
+// TimerShard returns the shard a timer is stored under. A timer with an
+// ordering key is sharded by it, whatever its shard key, so a whole
+// stream lands on one shard and one worker; otherwise the shard key is
+// hashed, and the empty key is shard 0.
+func TimerShard(shardKey, orderingKey string) uint32 {
+	if orderingKey != "" {
+		return StableHash([]byte(orderingKey))
+	}
+	if shardKey != "" {
+		return StableHash([]byte(shardKey))
+	}
+	return 0
+}
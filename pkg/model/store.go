@@ -0,0 +1,75 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"sandman/pkg/selector"
+	"sandman/pkg/uuid"
+)
+
+var (
+	_ TimerStore = (*Manager)(nil)
+	_ TimerStore = (*MemoryStore)(nil)
+)
+
+// TimerStore is the storage the worker, controller and servers run
+// against. Manager implements it over CockroachDB; MemoryStore holds
+// everything in process for tests and single-node deployments.
+//
+// Implementations must be safe for concurrent use and must agree on the
+// claim semantics documented on Manager: shard bands, weighted
+// round-robin across shard keys, ordering keys, the five attempt retry
+// budget and lease token fencing. The storetest package is the shared
+// conformance suite every implementation is expected to pass.
+type TimerStore interface {
+	// timers
+
+	CreateTimer(ctx context.Context, t *Timer) error
+	GetTimerByID(ctx context.Context, id uuid.UUID) (Timer, bool, error)
+	GetTimerByName(ctx context.Context, name string) (Timer, bool, error)
+	GetTimersDueBetween(ctx context.Context, after, before time.Time, s selector.Selector) ([]Timer, error)
+	ExportTimers(ctx context.Context, after, before time.Time, s selector.Selector, fn func(Timer) error) error
+	ExportDeliveredTimers(ctx context.Context, after, before time.Time, s selector.Selector, fn func(Timer) error) error
+	ImportTimer(ctx context.Context, t *Timer, onConflict ImportConflict) (ImportResult, error)
+	DeleteTimerByID(ctx context.Context, id uuid.UUID) (bool, error)
+	DeleteTimerByName(ctx context.Context, name string) (bool, error)
+
+	// claims and leases
+
+	GetDueTimers(ctx context.Context, workerIdentity string, asOf time.Time, batchSize int, shards []ShardRange, opts ...ClaimOption) ([]Timer, error)
+	GetDueTimersWindowed(ctx context.Context, workerIdentity string, asOf time.Time, batchSize int, shards []ShardRange, windowSeconds, leaseSeconds int, opts ...ClaimOption) ([]Timer, error)
+	RenewLeases(ctx context.Context, workerIdentity string, leaseUntil time.Time, leases []TimerLease) ([]uuid.UUID, error)
+	BulkRelinquish(ctx context.Context, workerIdentity string, asOf time.Time, leases []TimerLease) error
+
+	// outcomes
+
+	MarkAttempted(ctx context.Context, lease TimerLease, deliveredStatus uint32, deliveredErr error, asOf time.Time) (bool, error)
+	BulkMarkAttempted(ctx context.Context, deliveredStatus uint32, deliveredErr error, asOf time.Time, leases []TimerLease) (int64, error)
+	BulkMarkDelivered(ctx context.Context, deliveredUTC time.Time, deliveries []TimerDelivery) (int64, error)
+	BulkMarkExpired(ctx context.Context, expiredUTC time.Time, leases []TimerLease) (int64, error)
+
+	// culling
+
+	CullTimers(ctx context.Context, cutoff time.Time) (int64, error)
+	GetCullableTimers(ctx context.Context, cutoff time.Time, limit int) ([]Timer, error)
+	BulkDeleteTimers(ctx context.Context, ids []uuid.UUID) (int64, error)
+
+	// worker membership
+
+	WorkerSeen(ctx context.Context, workerHostname string, ts time.Time, capacityWeight int, wheelOccupancy float64) error
+	DeleteWorker(ctx context.Context, hostname string) error
+	GetWorkers(ctx context.Context, asOf time.Time) ([]Worker, error)
+
+	// counts
+
+	GetPeakTimersDueCount(ctx context.Context, after, before time.Time, bucketSeconds float64) (int64, error)
+	GetOverdueTimerCount(ctx context.Context, asOf time.Time) (int64, error)
+
+	// delivery stats
+
+	RollupDeliveryStats(ctx context.Context, after, before time.Time) (int, error)
+	GetDeliveryStatsWatermark(ctx context.Context) (time.Time, bool, error)
+	GetDeliveryStats(ctx context.Context, after, before time.Time, s selector.Selector) ([]DeliveryStats, error)
+	CullDeliveryStats(ctx context.Context, cutoff time.Time) (int64, error)
+}
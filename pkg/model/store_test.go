@@ -0,0 +1,40 @@
+package model_test
+
+import (
+	"context"
+	"testing"
+
+	"sandman/pkg/assert"
+	"sandman/pkg/db/dbutil"
+	"sandman/pkg/model"
+	"sandman/pkg/model/storetest"
+	"sandman/pkg/testutil"
+)
+
+// Test_Manager_Conformance runs the store conformance suite against the
+// database. Cases claim from several goroutines at once, which a single
+// transaction can't serve, so each case runs against emptied tables
+// rather than rolling back.
+func Test_Manager_Conformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) model.TimerStore {
+		ctx := context.Background()
+		emptyTables(t)
+		modelMgr := &model.Manager{
+			BaseManager: dbutil.NewBaseManager(testutil.DefaultDB()),
+		}
+		assert.Nil(t, modelMgr.Initialize(ctx))
+		t.Cleanup(func() {
+			_ = modelMgr.Close()
+			emptyTables(t)
+		})
+		return modelMgr
+	})
+}
+
+func emptyTables(t *testing.T) {
+	t.Helper()
+	for _, table := range []string{"timers", "workers", "delivery_stats"} {
+		_, err := testutil.DefaultDB().ExecContext(context.Background(), "DELETE FROM "+table)
+		assert.Nil(t, err)
+	}
+}
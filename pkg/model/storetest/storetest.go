@@ -0,0 +1,793 @@
+// Package storetest is the conformance suite for model.TimerStore
+// implementations.
+//
+// Every backend runs the same cases through Run, so a semantic the
+// worker relies on (a claim's shard band, the retry budget, lease
+// fencing) can't quietly drift between them:
+//
+//	func TestMemoryStore(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T) model.TimerStore {
+//			return model.NewMemoryStore()
+//		})
+//	}
+package storetest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"sandman/pkg/assert"
+	"sandman/pkg/model"
+	"sandman/pkg/selector"
+	"sandman/pkg/uuid"
+)
+
+// NewStore returns an empty store for a single test case.
+type NewStore func(*testing.T) model.TimerStore
+
+// Run runs every conformance case against stores returned by newStore.
+func Run(t *testing.T, newStore NewStore) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newStore(t))
+		})
+	}
+}
+
+var cases = []struct {
+	name string
+	fn   func(*testing.T, model.TimerStore)
+}{
+	{"CreateAndGet", testCreateAndGet},
+	{"GetTimersDueBetween", testGetTimersDueBetween},
+	{"ImportTimer", testImportTimer},
+	{"ExportTimers", testExportTimers},
+	{"ExportDeliveredTimers", testExportDeliveredTimers},
+	{"Claim_byDueUTC", testClaimByDueUTC},
+	{"Claim_window", testClaimWindow},
+	{"Claim_shardBands", testClaimShardBands},
+	{"Claim_retry", testClaimRetry},
+	{"Claim_retryBudget", testClaimRetryBudget},
+	{"Claim_fairAcrossShardKeys", testClaimFairAcrossShardKeys},
+	{"Claim_keyWeights", testClaimKeyWeights},
+	{"Claim_maxPerKey", testClaimMaxPerKey},
+	{"Claim_boundedCandidates", testClaimBoundedCandidates},
+	{"Claim_orderingKey", testClaimOrderingKey},
+	{"Claim_orderingKeyAcrossShardKeys", testClaimOrderingKeyAcrossShardKeys},
+	{"Claim_concurrent", testClaimConcurrent},
+	{"LeaseFencing", testLeaseFencing},
+	{"LeaseFencing_importReplace", testLeaseFencingImportReplace},
+	{"RenewLeases", testRenewLeases},
+	{"BulkRelinquish", testBulkRelinquish},
+	{"BulkMarkExpired", testBulkMarkExpired},
+	{"Cull", testCull},
+	{"Workers", testWorkers},
+	{"Counts", testCounts},
+	{"DeliveryStats", testDeliveryStats},
+}
+
+// anchor is microsecond aligned, the resolution of a TIMESTAMP column.
+var anchor = time.Date(2024, 10, 19, 20, 19, 18, 0, time.UTC)
+
+func newTimer(name, shardKey string, dueUTC time.Time) model.Timer {
+	var shard uint32
+	if shardKey != "" {
+		shard = model.StableHash([]byte(shardKey))
+	}
+	return model.Timer{
+		Name:       name,
+		ShardKey:   shardKey,
+		Shard:      shard,
+		Labels:     map[string]string{"shard_key": shardKey},
+		CreatedUTC: anchor,
+		DueUTC:     dueUTC,
+		HookURL:    "http://localhost/hook",
+		HookMethod: "POST",
+	}
+}
+
+func createTimer(t *testing.T, store model.TimerStore, timer model.Timer) model.Timer {
+	t.Helper()
+	assert.Nil(t, store.CreateTimer(context.Background(), &timer))
+	assert.False(t, timer.ID.IsZero())
+	return timer
+}
+
+func createDueTimers(t *testing.T, store model.TimerStore, shardKey string, count int, dueUTC time.Time) (output []model.Timer) {
+	t.Helper()
+	for index := range count {
+		output = append(output, createTimer(t, store, newTimer(fmt.Sprintf("%s-%02d", shardKey, index), shardKey, dueUTC.Add(time.Duration(index)*time.Second))))
+	}
+	return
+}
+
+func claimedNames(timers []model.Timer) (names []string) {
+	for _, timer := range timers {
+		names = append(names, timer.Name)
+	}
+	slices.Sort(names)
+	return
+}
+
+func countByShardKey(timers []model.Timer) map[string]int {
+	output := make(map[string]int)
+	for _, timer := range timers {
+		output[timer.ShardKey]++
+	}
+	return output
+}
+
+func leases(timers []model.Timer) (output []model.TimerLease) {
+	for _, timer := range timers {
+		output = append(output, timer.Lease())
+	}
+	return
+}
+
+func deliveries(timers []model.Timer, firedUTC time.Time) (output []model.TimerDelivery) {
+	for _, timer := range timers {
+		output = append(output, timer.Delivery(firedUTC))
+	}
+	return
+}
+
+func getTimer(t *testing.T, store model.TimerStore, id uuid.UUID) model.Timer {
+	t.Helper()
+	timer, found, err := store.GetTimerByID(context.Background(), id)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	return timer
+}
+
+func testCreateAndGet(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	created := newTimer("timer-00", "tenant-a", anchor.Add(time.Hour))
+	created.Labels["env"] = "prod"
+	created.HookHeaders = map[string]string{"X-Test": "yes"}
+	created.HookBody = []byte(`{"ok":true}`)
+	created = createTimer(t, store, created)
+
+	byID := getTimer(t, store, created.ID)
+	assert.Equal(t, "timer-00", byID.Name)
+	assert.Equal(t, "prod", byID.Labels["env"])
+	assert.Equal(t, "yes", byID.HookHeaders["X-Test"])
+	assert.Equal(t, `{"ok":true}`, string(byID.HookBody))
+	assert.True(t, byID.DueUTC.Equal(created.DueUTC))
+
+	byName, found, err := store.GetTimerByName(ctx, "timer-00")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.True(t, byName.ID.Equal(created.ID))
+
+	duplicate := newTimer("timer-00", "tenant-a", anchor.Add(time.Hour))
+	assert.NotNil(t, store.CreateTimer(ctx, &duplicate), "names are unique")
+
+	found, err = store.DeleteTimerByName(ctx, "timer-00")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	found, err = store.DeleteTimerByID(ctx, created.ID)
+	assert.Nil(t, err)
+	assert.False(t, found)
+	_, found, err = store.GetTimerByID(ctx, created.ID)
+	assert.Nil(t, err)
+	assert.False(t, found)
+}
+
+func testGetTimersDueBetween(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 3, anchor.Add(time.Minute))
+	createDueTimers(t, store, "tenant-b", 2, anchor.Add(time.Minute))
+	createTimer(t, store, newTimer("later", "tenant-a", anchor.Add(2*time.Hour)))
+
+	timers, err := store.GetTimersDueBetween(ctx, anchor, anchor.Add(time.Hour), nil)
+	assert.Nil(t, err)
+	assert.ItsLen(t, timers, 5)
+
+	sel, err := selector.Parse("shard_key=tenant-b")
+	assert.Nil(t, err)
+	timers, err = store.GetTimersDueBetween(ctx, anchor, anchor.Add(time.Hour), sel)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"tenant-b-00", "tenant-b-01"}, claimedNames(timers))
+}
+
+func testImportTimer(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	original := createTimer(t, store, newTimer("imported", "tenant-a", anchor.Add(time.Hour)))
+
+	incoming := newTimer("imported", "tenant-a", anchor.Add(2*time.Hour))
+	_, err := store.ImportTimer(ctx, &incoming, model.ImportConflictFail)
+	assert.True(t, errors.Is(err, model.ErrTimerNameExists))
+
+	incoming = newTimer("imported", "tenant-a", anchor.Add(2*time.Hour))
+	result, err := store.ImportTimer(ctx, &incoming, model.ImportConflictSkip)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ImportSkipped, result)
+	assert.True(t, getTimer(t, store, original.ID).DueUTC.Equal(original.DueUTC))
+
+	incoming = newTimer("imported", "tenant-a", anchor.Add(2*time.Hour))
+	result, err = store.ImportTimer(ctx, &incoming, model.ImportConflictReplace)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ImportReplaced, result)
+	assert.True(t, incoming.ID.Equal(original.ID), "a replace keeps the original id")
+	assert.True(t, getTimer(t, store, original.ID).DueUTC.Equal(anchor.Add(2*time.Hour)))
+
+	fresh := newTimer("imported-fresh", "tenant-a", anchor.Add(time.Hour))
+	result, err = store.ImportTimer(ctx, &fresh, model.ImportConflictFail)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ImportCreated, result)
+	assert.False(t, fresh.ID.IsZero())
+}
+
+func testExportTimers(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	pending := createDueTimers(t, store, "tenant-a", 3, anchor.Add(-time.Minute))
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 1, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, claimed, 1)
+	_, err = store.BulkMarkDelivered(ctx, anchor, deliveries(claimed, anchor))
+	assert.Nil(t, err)
+	exhausted := newTimer("exhausted", "tenant-a", anchor.Add(-time.Minute))
+	exhausted.Attempt = 5
+	createTimer(t, store, exhausted)
+
+	var exported []string
+	err = store.ExportTimers(ctx, anchor.Add(-time.Hour), anchor.Add(time.Hour), nil, func(timer model.Timer) error {
+		exported = append(exported, timer.Name)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{pending[1].Name, pending[2].Name}, exported, "pending timers in due order")
+}
+
+func testExportDeliveredTimers(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 3, anchor.Add(-time.Minute))
+	createDueTimers(t, store, "tenant-b", 1, anchor.Add(-time.Minute))
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	byName := make(map[string]model.Timer)
+	for _, timer := range claimed {
+		byName[timer.Name] = timer
+	}
+	deliver := func(deliveredUTC time.Time, names ...string) {
+		t.Helper()
+		var timers []model.Timer
+		for _, name := range names {
+			timers = append(timers, byName[name])
+		}
+		_, err := store.BulkMarkDelivered(ctx, deliveredUTC, deliveries(timers, deliveredUTC))
+		assert.Nil(t, err)
+	}
+	deliver(anchor.Add(time.Second), "tenant-a-00")
+	deliver(anchor, "tenant-a-01", "tenant-b-00")
+
+	sel, err := selector.Parse("shard_key=tenant-a")
+	assert.Nil(t, err)
+	var exported []string
+	err = store.ExportDeliveredTimers(ctx, anchor.Add(-time.Hour), anchor.Add(time.Hour), sel, func(timer model.Timer) error {
+		exported = append(exported, timer.Name)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"tenant-a-01", "tenant-a-00"}, exported, "delivered timers in delivery order")
+
+	stop := errors.New("stop")
+	var calls int
+	err = store.ExportDeliveredTimers(ctx, anchor.Add(-time.Hour), anchor.Add(time.Hour), nil, func(model.Timer) error {
+		calls++
+		return stop
+	})
+	assert.True(t, errors.Is(err, stop))
+	assert.Equal(t, 1, calls, "an error from fn stops the export")
+}
+
+func testClaimByDueUTC(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createTimer(t, store, newTimer("due-00", "tenant-a", anchor.Add(-time.Minute)))
+	createTimer(t, store, newTimer("due-01", "tenant-a", anchor.Add(-time.Second)))
+	createTimer(t, store, newTimer("future", "tenant-a", anchor.Add(time.Minute)))
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"due-00", "due-01"}, claimedNames(claimed))
+	for _, timer := range claimed {
+		assert.Equal(t, uint32(1), timer.Attempt)
+		assert.NotNil(t, timer.AssignedWorker)
+		assert.Equal(t, "worker-a", *timer.AssignedWorker)
+		assert.NotNil(t, timer.AssignedUntilUTC)
+		assert.True(t, timer.AssignedUntilUTC.Equal(anchor.Add(time.Minute)))
+	}
+
+	claimed, err = store.GetDueTimers(ctx, "worker-b", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, claimed, "leased timers are not claimed twice")
+}
+
+func testClaimWindow(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createTimer(t, store, newTimer("soon", "tenant-a", anchor.Add(5*time.Second)))
+	createTimer(t, store, newTimer("later", "tenant-a", anchor.Add(time.Minute)))
+
+	claimed, err := store.GetDueTimersWindowed(ctx, "worker-a", anchor, 10, model.AllShards(), 10, 40)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"soon"}, claimedNames(claimed))
+	assert.True(t, claimed[0].AssignedUntilUTC.Equal(anchor.Add(40*time.Second)))
+}
+
+func testClaimShardBands(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	a := createTimer(t, store, newTimer("a", "tenant-a", anchor.Add(-time.Minute)))
+	b := createTimer(t, store, newTimer("b", "tenant-b", anchor.Add(-time.Minute)))
+
+	band := []model.ShardRange{{Lo: uint64(a.Shard), Hi: uint64(a.Shard) + 1}}
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, band)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"a"}, claimedNames(claimed))
+
+	band = []model.ShardRange{{Lo: 0, Hi: uint64(b.Shard)}, {Lo: uint64(b.Shard) + 1, Hi: model.ShardSpace}}
+	claimed, err = store.GetDueTimers(ctx, "worker-b", anchor, 10, band)
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, claimed, "a band excluding the only remaining shard claims nothing")
+
+	claimed, err = store.GetDueTimers(ctx, "worker-b", anchor, 10, nil)
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, claimed, "no bands claims nothing")
+}
+
+func testClaimRetry(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	timer := createTimer(t, store, newTimer("flaky", "tenant-a", anchor.Add(-time.Minute)))
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, claimed, 1)
+	stale, err := store.MarkAttempted(ctx, claimed[0].Lease(), 503, errors.New("unavailable"), anchor)
+	assert.Nil(t, err)
+	assert.False(t, stale)
+
+	attempted := getTimer(t, store, timer.ID)
+	assert.Equal(t, uint32(503), attempted.DeliveredStatusCode)
+	assert.Equal(t, "unavailable", attempted.DeliveredErr)
+	assert.Nil(t, attempted.AssignedUntilUTC)
+
+	claimed, err = store.GetDueTimers(ctx, "worker-a", anchor.Add(time.Minute), 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, claimed, "a failed timer waits out its retry delay")
+
+	claimed, err = store.GetDueTimers(ctx, "worker-a", anchor.Add(6*time.Minute), 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, claimed, 1)
+	assert.Equal(t, uint32(2), claimed[0].Attempt)
+}
+
+func testClaimRetryBudget(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	timer := createTimer(t, store, newTimer("doomed", "tenant-a", anchor.Add(-time.Minute)))
+
+	asOf := anchor
+	for attempt := 1; attempt <= 5; attempt++ {
+		claimed, err := store.GetDueTimers(ctx, "worker-a", asOf, 10, model.AllShards())
+		assert.Nil(t, err)
+		assert.ItsLen(t, claimed, 1, fmt.Sprintf("attempt %d", attempt))
+		stale, err := store.BulkMarkAttempted(ctx, 500, errors.New("boom"), asOf, leases(claimed))
+		assert.Nil(t, err)
+		assert.Zero(t, stale)
+		asOf = asOf.Add(6 * time.Minute)
+	}
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", asOf, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, claimed, "a timer is attempted at most five times")
+	assert.Equal(t, uint32(5), getTimer(t, store, timer.ID).Attempt)
+}
+
+func testClaimFairAcrossShardKeys(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-big", 20, anchor.Add(-time.Hour))
+	createDueTimers(t, store, "tenant-small", 2, anchor.Add(-time.Minute))
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 4, model.AllShards())
+	assert.Nil(t, err)
+	counts := countByShardKey(claimed)
+	assert.Equal(t, 2, counts["tenant-big"])
+	assert.Equal(t, 2, counts["tenant-small"], "a small key isn't starved by an older backlog")
+}
+
+func testClaimKeyWeights(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 10, anchor.Add(-time.Minute))
+	createDueTimers(t, store, "tenant-b", 10, anchor.Add(-time.Minute))
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 8, model.AllShards(),
+		model.OptClaimKeyWeights(map[string]uint32{"tenant-a": 3}),
+	)
+	assert.Nil(t, err)
+	counts := countByShardKey(claimed)
+	assert.Equal(t, 6, counts["tenant-a"])
+	assert.Equal(t, 2, counts["tenant-b"])
+}
+
+func testClaimMaxPerKey(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 10, anchor.Add(-time.Minute))
+	createDueTimers(t, store, "tenant-b", 1, anchor.Add(-time.Minute))
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards(),
+		model.OptClaimMaxPerKey(3),
+	)
+	assert.Nil(t, err)
+	counts := countByShardKey(claimed)
+	assert.Equal(t, 3, counts["tenant-a"])
+	assert.Equal(t, 1, counts["tenant-b"])
+}
+
+func testClaimBoundedCandidates(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 5, anchor.Add(-time.Minute))
+	urgent := newTimer("tenant-a-urgent", "tenant-a", anchor.Add(-time.Second))
+	urgent.Priority = 100
+	createTimer(t, store, urgent)
+	createDueTimers(t, store, "tenant-b", 1, anchor.Add(-time.Minute))
+
+	// priority only ranks a key's earliest due timers, so the urgent
+	// timer behind them waits its turn.
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 2, model.AllShards(),
+		model.OptClaimMaxPerKey(1),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"tenant-a-00", "tenant-b-00"}, claimedNames(claimed))
+}
+
+func testClaimOrderingKey(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	var stream []model.Timer
+	for index := range 3 {
+		timer := newTimer(fmt.Sprintf("ordered-%02d", index), "tenant-a", anchor.Add(time.Duration(index-10)*time.Second))
+		timer.OrderingKey = "account-1"
+		stream = append(stream, createTimer(t, store, timer))
+	}
+	createTimer(t, store, newTimer("unordered", "tenant-a", anchor.Add(-time.Second)))
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ordered-00", "unordered"}, claimedNames(claimed), "only the head of a stream is claimable")
+
+	claimed, err = store.GetDueTimers(ctx, "worker-a", anchor.Add(2*time.Minute), 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, claimed, "a leased head blocks its stream")
+
+	_, err = store.BulkMarkDelivered(ctx, anchor, []model.TimerDelivery{getTimer(t, store, stream[0].ID).Delivery(anchor)})
+	assert.Nil(t, err)
+	claimed, err = store.GetDueTimers(ctx, "worker-a", anchor.Add(2*time.Minute), 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ordered-01"}, claimedNames(claimed))
+}
+
+func testClaimOrderingKeyAcrossShardKeys(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	var stream []model.Timer
+	for index, shardKey := range []string{"tenant-a", "tenant-b", ""} {
+		timer := newTimer(fmt.Sprintf("ordered-%02d", index), shardKey, anchor.Add(time.Duration(index-10)*time.Second))
+		timer.OrderingKey = "account-1"
+		timer.Shard = model.TimerShard(timer.ShardKey, timer.OrderingKey)
+		stream = append(stream, createTimer(t, store, timer))
+	}
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ordered-00"}, claimedNames(claimed), "shard keys don't split a stream")
+
+	_, err = store.BulkMarkDelivered(ctx, anchor, []model.TimerDelivery{getTimer(t, store, stream[0].ID).Delivery(anchor)})
+	assert.Nil(t, err)
+	claimed, err = store.GetDueTimers(ctx, "worker-a", anchor.Add(2*time.Minute), 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ordered-01"}, claimedNames(claimed))
+}
+
+func testClaimConcurrent(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	const timerCount, workerCount = 200, 8
+	for index := range timerCount {
+		createTimer(t, store, newTimer(fmt.Sprintf("timer-%03d", index), fmt.Sprintf("tenant-%d", index%7), anchor.Add(-time.Minute)))
+	}
+
+	var mu sync.Mutex
+	seen := make(map[uuid.UUID]string)
+	var duplicates int
+	var wg sync.WaitGroup
+	errs := make(chan error, workerCount)
+	for worker := range workerCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			identity := fmt.Sprintf("worker-%d", worker)
+			for {
+				claimed, err := store.GetDueTimers(ctx, identity, anchor, 7, model.AllShards())
+				if err != nil {
+					errs <- err
+					return
+				}
+				if len(claimed) == 0 {
+					return
+				}
+				mu.Lock()
+				for _, timer := range claimed {
+					if _, ok := seen[timer.ID]; ok {
+						duplicates++
+					}
+					seen[timer.ID] = identity
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.Nil(t, err)
+	}
+	assert.Zero(t, duplicates, "no timer is claimed by two workers")
+	assert.Equal(t, timerCount, len(seen))
+}
+
+func testLeaseFencing(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 2, anchor.Add(-time.Minute))
+
+	zombie, err := store.GetDueTimers(ctx, "worker-zombie", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, zombie, 2)
+
+	// the zombie pauses past its lease and retry delay; a peer re-claims.
+	reclaimAt := anchor.Add(10 * time.Minute)
+	peer, err := store.GetDueTimers(ctx, "worker-peer", reclaimAt, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, peer, 2)
+
+	stale, err := store.BulkMarkDelivered(ctx, reclaimAt, deliveries(zombie, reclaimAt))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), stale)
+	staleOne, err := store.MarkAttempted(ctx, zombie[0].Lease(), 500, errors.New("zombie"), reclaimAt)
+	assert.Nil(t, err)
+	assert.True(t, staleOne)
+	stale, err = store.BulkMarkExpired(ctx, reclaimAt, leases(zombie))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), stale)
+
+	for _, timer := range zombie {
+		current := getTimer(t, store, timer.ID)
+		assert.Nil(t, current.DeliveredUTC, "the zombie's writes are fenced off")
+		assert.Equal(t, "", current.DeliveredErr)
+	}
+
+	stale, err = store.BulkMarkDelivered(ctx, reclaimAt, deliveries(peer, reclaimAt))
+	assert.Nil(t, err)
+	assert.Zero(t, stale)
+	for _, timer := range peer {
+		delivered := getTimer(t, store, timer.ID)
+		assert.NotNil(t, delivered.DeliveredUTC)
+		assert.NotNil(t, delivered.FiredUTC)
+	}
+
+	stale, err = store.BulkMarkDelivered(ctx, reclaimAt, []model.TimerDelivery{{TimerLease: model.TimerLease{ID: uuid.V4(), Token: 1}}})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), stale, "a deleted timer's lease is stale")
+}
+
+func testLeaseFencingImportReplace(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 1, anchor.Add(-time.Minute))
+	leased, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, leased, 1)
+
+	incoming := newTimer(leased[0].Name, "tenant-a", anchor.Add(time.Hour))
+	result, err := store.ImportTimer(ctx, &incoming, model.ImportConflictReplace)
+	assert.Nil(t, err)
+	assert.Equal(t, model.ImportReplaced, result)
+
+	replaced := getTimer(t, store, leased[0].ID)
+	assert.True(t, replaced.LeaseToken > leased[0].LeaseToken, "a replace bumps the lease token")
+
+	stale, err := store.BulkMarkDelivered(ctx, anchor, deliveries(leased, anchor))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), stale)
+	assert.Nil(t, getTimer(t, store, leased[0].ID).DeliveredUTC, "the old lease holder can't deliver the replacement")
+}
+
+func testRenewLeases(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 3, anchor.Add(-time.Minute))
+
+	held, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, held, 3)
+	slices.SortFunc(held, func(a, b model.Timer) int { return a.DueUTC.Compare(b.DueUTC) })
+
+	stale, err := store.BulkMarkDelivered(ctx, anchor, deliveries(held[:1], anchor))
+	assert.Nil(t, err)
+	assert.Zero(t, stale)
+
+	leaseUntil := anchor.Add(5 * time.Minute)
+	lost, err := store.RenewLeases(ctx, "worker-a", leaseUntil, leases(held))
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{held[0].ID}, lost, "a completed timer's lease is lost")
+	assert.True(t, getTimer(t, store, held[1].ID).AssignedUntilUTC.Equal(leaseUntil))
+
+	lost, err = store.RenewLeases(ctx, "worker-b", leaseUntil, leases(held[1:]))
+	assert.Nil(t, err)
+	assert.ItsLen(t, lost, 2, "another worker can't renew a lease it doesn't hold")
+}
+
+func testBulkRelinquish(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 2, anchor.Add(-time.Minute))
+
+	held, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, held, 2)
+
+	assert.Nil(t, store.BulkRelinquish(ctx, "worker-b", anchor, leases(held)))
+	assert.NotNil(t, getTimer(t, store, held[0].ID).AssignedWorker, "only the holder can relinquish")
+
+	assert.Nil(t, store.BulkRelinquish(ctx, "worker-a", anchor, leases(held)))
+	for _, timer := range held {
+		relinquished := getTimer(t, store, timer.ID)
+		assert.Nil(t, relinquished.AssignedWorker)
+		assert.Nil(t, relinquished.AssignedUntilUTC)
+		assert.Zero(t, relinquished.Attempt, "relinquishing doesn't spend an attempt")
+	}
+}
+
+func testBulkMarkExpired(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 2, anchor.Add(-time.Minute))
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, claimed, 2)
+
+	stale, err := store.BulkMarkExpired(ctx, anchor, leases(claimed))
+	assert.Nil(t, err)
+	assert.Zero(t, stale)
+	for _, timer := range claimed {
+		expired := getTimer(t, store, timer.ID)
+		assert.NotNil(t, expired.ExpiredUTC)
+		assert.Nil(t, expired.DeliveredUTC)
+		assert.Nil(t, expired.AssignedUntilUTC)
+	}
+
+	claimed, err = store.GetDueTimers(ctx, "worker-a", anchor.Add(time.Hour), 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, claimed, "expired timers are never claimed again")
+}
+
+func testCull(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 3, anchor.Add(-2*time.Hour))
+	createTimer(t, store, newTimer("pending", "tenant-a", anchor.Add(-2*time.Hour+30*time.Second)))
+
+	done, err := store.GetDueTimers(ctx, "worker-a", anchor.Add(-2*time.Hour+10*time.Second), 2, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, done, 2)
+	slices.SortFunc(done, func(a, b model.Timer) int { return a.DueUTC.Compare(b.DueUTC) })
+	_, err = store.BulkMarkDelivered(ctx, anchor.Add(-time.Hour), deliveries(done[:1], anchor.Add(-time.Hour)))
+	assert.Nil(t, err)
+	_, err = store.BulkMarkExpired(ctx, anchor.Add(-time.Hour), leases(done[1:]))
+	assert.Nil(t, err)
+
+	cullable, err := store.GetCullableTimers(ctx, anchor, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, claimedNames(done), claimedNames(cullable))
+
+	cullable, err = store.GetCullableTimers(ctx, anchor, 1)
+	assert.Nil(t, err)
+	assert.ItsLen(t, cullable, 1)
+	assert.True(t, cullable[0].ID.Equal(done[0].ID), "oldest first")
+
+	deleted, err := store.BulkDeleteTimers(ctx, []uuid.UUID{done[0].ID, uuid.V4()})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	culled, err := store.CullTimers(ctx, anchor)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), culled)
+
+	remaining, err := store.GetTimersDueBetween(ctx, anchor.Add(-3*time.Hour), anchor, nil)
+	assert.Nil(t, err)
+	assert.ItsLen(t, remaining, 2, "unfinished timers are never culled")
+}
+
+func testWorkers(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	assert.Nil(t, store.WorkerSeen(ctx, "worker-stale", anchor.Add(-time.Minute), 1, 0))
+	assert.Nil(t, store.WorkerSeen(ctx, "worker-00", anchor.Add(-time.Minute), 1, 0))
+	assert.Nil(t, store.WorkerSeen(ctx, "worker-00", anchor, 0, 0.25))
+	assert.Nil(t, store.WorkerSeen(ctx, "worker-01", anchor, 3, 0.5))
+
+	workers, err := store.GetWorkers(ctx, anchor.Add(-30*time.Second))
+	assert.Nil(t, err)
+	slices.SortFunc(workers, func(a, b model.Worker) int { return cmp.Compare(a.Hostname, b.Hostname) })
+	assert.ItsLen(t, workers, 2)
+	assert.Equal(t, "worker-00", workers[0].Hostname)
+	assert.True(t, workers[0].CreatedUTC.Equal(anchor.Add(-time.Minute)), "a heartbeat keeps the created time")
+	assert.True(t, workers[0].LastSeenUTC.Equal(anchor))
+	assert.Equal(t, 1, workers[0].CapacityWeight, "weights are at least one")
+	assert.Equal(t, 0.25, workers[0].WheelOccupancy)
+	assert.Equal(t, 3, workers[1].CapacityWeight)
+
+	assert.Nil(t, store.DeleteWorker(ctx, "worker-00"))
+	workers, err = store.GetWorkers(ctx, anchor.Add(-30*time.Second))
+	assert.Nil(t, err)
+	assert.ItsLen(t, workers, 1)
+	assert.Equal(t, "worker-01", workers[0].Hostname)
+}
+
+func testCounts(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 3, anchor.Add(-time.Minute))
+	expiring := newTimer("expiring", "tenant-a", anchor.Add(-time.Minute))
+	expiresUTC := anchor.Add(-time.Second)
+	expiring.ExpiresUTC = &expiresUTC
+	createTimer(t, store, expiring)
+	createDueTimers(t, store, "tenant-b", 4, anchor.Add(time.Hour))
+	createDueTimers(t, store, "tenant-c", 2, anchor.Add(time.Hour+time.Minute))
+
+	overdue, err := store.GetOverdueTimerCount(ctx, anchor)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), overdue, "timers expired by now don't count as overdue")
+
+	peak, err := store.GetPeakTimersDueCount(ctx, anchor, anchor.Add(2*time.Hour), 30)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), peak)
+
+	peak, err = store.GetPeakTimersDueCount(ctx, anchor.Add(2*time.Hour), anchor.Add(3*time.Hour), 30)
+	assert.Nil(t, err)
+	assert.Zero(t, peak)
+}
+
+func testDeliveryStats(t *testing.T, store model.TimerStore) {
+	ctx := context.Background()
+	createDueTimers(t, store, "tenant-a", 2, anchor.Add(-time.Minute))
+	createDueTimers(t, store, "tenant-b", 1, anchor.Add(-time.Minute))
+
+	_, found, err := store.GetDeliveryStatsWatermark(ctx)
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	claimed, err := store.GetDueTimers(ctx, "worker-a", anchor, 10, model.AllShards())
+	assert.Nil(t, err)
+	assert.ItsLen(t, claimed, 3)
+	_, err = store.BulkMarkDelivered(ctx, anchor, deliveries(claimed, anchor))
+	assert.Nil(t, err)
+
+	bucket := anchor.Truncate(model.DeliveryStatsBucket)
+	buckets, err := store.RollupDeliveryStats(ctx, bucket, bucket.Add(model.DeliveryStatsBucket))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, buckets)
+
+	watermark, found, err := store.GetDeliveryStatsWatermark(ctx)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.True(t, watermark.Equal(bucket))
+
+	sel, err := selector.Parse("shard_key=tenant-a")
+	assert.Nil(t, err)
+	stats, err := store.GetDeliveryStats(ctx, bucket, bucket.Add(model.DeliveryStatsBucket), sel)
+	assert.Nil(t, err)
+	assert.ItsLen(t, stats, 1)
+	assert.Equal(t, int64(2), stats[0].Count)
+
+	culled, err := store.CullDeliveryStats(ctx, bucket.Add(model.DeliveryStatsBucket))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), culled)
+	stats, err = store.GetDeliveryStats(ctx, bucket, bucket.Add(model.DeliveryStatsBucket), nil)
+	assert.Nil(t, err)
+	assert.ItsEmpty(t, stats)
+}
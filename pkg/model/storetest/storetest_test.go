@@ -0,0 +1,13 @@
+package storetest
+
+import (
+	"testing"
+
+	"sandman/pkg/model"
+)
+
+func TestMemoryStore(t *testing.T) {
+	Run(t, func(*testing.T) model.TimerStore {
+		return model.NewMemoryStore()
+	})
+}
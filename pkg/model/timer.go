@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"maps"
 	"time"
 
@@ -16,30 +17,95 @@ var (
 )
 
 // Timer is a promise in the future to deliver an RPC
+//
+// The json tags (matching the column names) are the archive format.
 type Timer struct {
-	ID       uuid.UUID         `db:"id,pk,auto"`
-	Name     string            `db:"name"`
-	Labels   map[string]string `db:"labels,json"`
-	Priority uint32            `db:"priority"`
-	ShardKey string            `db:"shard_key"`
-	Shard    uint32            `db:"shard"`
+	ID       uuid.UUID         `db:"id,pk,auto" json:"id"`
+	Name     string            `db:"name" json:"name"`
+	Labels   map[string]string `db:"labels,json" json:"labels"`
+	Priority uint32            `db:"priority" json:"priority"`
+	ShardKey string            `db:"shard_key" json:"shard_key"`
+	Shard    uint32            `db:"shard" json:"shard"`
+	// OrderingKey groups timers that must be delivered strictly one at a
+	// time in (due_utc, id) order. Ordered timers are sharded by their
+	// ordering key rather than their shard key (see TimerShard), so a
+	// stream stays on one shard whatever shard keys its timers carry.
+	// Empty means unordered.
+	OrderingKey string `db:"ordering_key" json:"ordering_key"`
 
-	CreatedUTC       time.Time  `db:"created_utc"`
-	DueUTC           time.Time  `db:"due_utc"`
-	AssignedUntilUTC *time.Time `db:"assigned_until_utc"`
-	RetryUTC         *time.Time `db:"retry_utc"`
+	CreatedUTC       time.Time  `db:"created_utc" json:"created_utc"`
+	DueUTC           time.Time  `db:"due_utc" json:"due_utc"`
+	AssignedUntilUTC *time.Time `db:"assigned_until_utc" json:"assigned_until_utc"`
+	RetryUTC         *time.Time `db:"retry_utc" json:"retry_utc"`
+	// ExpiresUTC is the point past which delivering the timer would do
+	// more harm than good (e.g. "your session expires in 5 minutes"
+	// fired hours late after an outage). Nil means the timer never goes
+	// stale.
+	ExpiresUTC *time.Time `db:"expires_utc" json:"expires_utc"`
 
-	Attempt        uint32  `db:"attempt"`
-	AssignedWorker *string `db:"assigned_worker"`
+	Attempt        uint32  `db:"attempt" json:"attempt"`
+	AssignedWorker *string `db:"assigned_worker" json:"assigned_worker"`
+	// LeaseToken is bumped by every claim; completion writes are
+	// conditional on it so a worker that lost its lease can't overwrite
+	// the outcome recorded by the worker that re-claimed the timer.
+	LeaseToken int64 `db:"lease_token" json:"lease_token"`
 
-	HookURL     string            `db:"hook_url"`
-	HookMethod  string            `db:"hook_method"`
-	HookHeaders map[string]string `db:"hook_headers,json"`
-	HookBody    []byte            `db:"hook_body"`
+	HookURL     string            `db:"hook_url" json:"hook_url"`
+	HookMethod  string            `db:"hook_method" json:"hook_method"`
+	HookHeaders map[string]string `db:"hook_headers,json" json:"hook_headers"`
+	HookBody    []byte            `db:"hook_body" json:"hook_body"`
 
-	DeliveredUTC        *time.Time `db:"delivered_utc"`
-	DeliveredStatusCode uint32     `db:"delivered_status_code"`
-	DeliveredErr        string     `db:"delivered_err"`
+	DeliveredUTC        *time.Time `db:"delivered_utc" json:"delivered_utc"`
+	DeliveredStatusCode uint32     `db:"delivered_status_code" json:"delivered_status_code"`
+	DeliveredErr        string     `db:"delivered_err" json:"delivered_err"`
+
+	// FiredUTC is when the worker sent the successful hook request, as
+	// opposed to DeliveredUTC which is when the outcome was written.
+	// FiredUTC - DueUTC is the delivery lateness rolled up into
+	// DeliveryStats.
+	FiredUTC *time.Time `db:"fired_utc" json:"fired_utc"`
+
+	// ExpiredUTC is set instead of DeliveredUTC when a worker claims the
+	// timer after ExpiresUTC has passed; the hook is never sent.
+	ExpiredUTC *time.Time `db:"expired_utc" json:"expired_utc"`
+}
+
+// Lease returns the timer's id and the lease token of the claim that
+// returned it.
+func (t Timer) Lease() TimerLease {
+	return TimerLease{ID: t.ID, Token: t.LeaseToken}
+}
+
+// TimerLease identifies one claim of a timer, fencing completion writes
+// to the worker holding the current lease.
+type TimerLease struct {
+	ID    uuid.UUID
+	Token int64
+}
+
+// Delivery returns the timer's lease paired with when its hook was
+// fired, for BulkMarkDelivered.
+func (t Timer) Delivery(firedUTC time.Time) TimerDelivery {
+	return TimerDelivery{TimerLease: t.Lease(), FiredUTC: firedUTC}
+}
+
+// TimerDelivery is a successful firing under a lease. A zero FiredUTC
+// records the timer as fired when it was marked delivered.
+type TimerDelivery struct {
+	TimerLease
+	FiredUTC time.Time
+}
+
+// leaseBounds splits leases into the parallel id / token arrays the
+// completion queries unnest.
+func leaseBounds(leases []TimerLease) (ids []uuid.UUID, tokens []int64) {
+	ids = make([]uuid.UUID, 0, len(leases))
+	tokens = make([]int64, 0, len(leases))
+	for _, l := range leases {
+		ids = append(ids, l.ID)
+		tokens = append(tokens, l.Token)
+	}
+	return
 }
 
 func (t Timer) MatchLabels() map[string]string {
@@ -52,6 +118,49 @@ func (t Timer) MatchLabels() map[string]string {
 	if t.DeliveredUTC != nil && !t.DeliveredUTC.IsZero() {
 		output["delivered"] = "true"
 	}
+	if t.ExpiredUTC != nil && !t.ExpiredUTC.IsZero() {
+		output["expired"] = "true"
+	}
+	return output
+}
+
+// IsExpired returns if the timer has an expiry and it is at or before
+// the given timestamp.
+func (t Timer) IsExpired(asOf time.Time) bool {
+	return t.ExpiresUTC != nil && !t.ExpiresUTC.After(asOf)
+}
+
+// LabelReplayedFrom is set on replayed timers to the id of the timer
+// they were cloned from.
+const LabelReplayedFrom = "replayed_from"
+
+// Replay returns a fresh pending copy of the timer due at dueUTC, named
+// `<name>-replay-<replayID>` and labeled with the original timer id.
+//
+// An expiry keeps its offset from the due time so a replay isn't
+// expired on arrival.
+func (t Timer) Replay(replayID string, createdUTC, dueUTC time.Time) Timer {
+	labels := make(map[string]string, len(t.Labels)+1)
+	maps.Copy(labels, t.Labels)
+	labels[LabelReplayedFrom] = t.ID.String()
+	output := Timer{
+		Name:        fmt.Sprintf("%s-replay-%s", t.Name, replayID),
+		Labels:      labels,
+		Priority:    t.Priority,
+		ShardKey:    t.ShardKey,
+		Shard:       t.Shard,
+		OrderingKey: t.OrderingKey,
+		CreatedUTC:  createdUTC,
+		DueUTC:      dueUTC,
+		HookURL:     t.HookURL,
+		HookMethod:  t.HookMethod,
+		HookHeaders: maps.Clone(t.HookHeaders),
+		HookBody:    t.HookBody,
+	}
+	if t.ExpiresUTC != nil {
+		expiresUTC := dueUTC.Add(t.ExpiresUTC.Sub(t.DueUTC))
+		output.ExpiresUTC = &expiresUTC
+	}
 	return output
 }
 
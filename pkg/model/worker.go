@@ -17,6 +17,22 @@ type Worker struct {
 	Hostname    string    `db:"hostname,pk"`
 	CreatedUTC  time.Time `db:"created_utc"`
 	LastSeenUTC time.Time `db:"last_seen_utc"`
+	// CapacityWeight is the worker's advertised share of the shard space
+	// relative to its peers; a weight-2 worker owns roughly twice the
+	// shards of a weight-1 worker.
+	CapacityWeight int `db:"capacity_weight"`
+	// WheelOccupancy is how full the worker's wheel was against its
+	// budgets as of its last heartbeat, from 0 to 1. Workers without
+	// wheel budgets report zero.
+	WheelOccupancy float64 `db:"wheel_occupancy"`
+}
+
+// CapacityWeightOrDefault returns the capacity weight or a default of 1.
+func (w Worker) CapacityWeightOrDefault() int {
+	if w.CapacityWeight > 0 {
+		return w.CapacityWeight
+	}
+	return 1
 }
 
 // TableName returns the table name.
@@ -2,17 +2,27 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
+	"sandman/pkg/archive"
+	"sandman/pkg/clock"
+	"sandman/pkg/egress"
+	"sandman/pkg/log"
 	"sandman/pkg/selector"
+	"sandman/pkg/utils"
 	"sandman/pkg/uuid"
+	"sandman/pkg/wakeup"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -22,55 +32,310 @@ import (
 
 type TimerServer struct {
 	sandmanv1.TimersServer
-	Model *model.Manager
+	Model model.TimerStore
+	// Egress, if set, restricts which hook urls timers may be created
+	// with. Nil allows any url.
+	Egress *egress.Policy
+	// ArchiveDir, if set, is searched along with the timers table when
+	// replaying, so culled timers can be replayed too.
+	ArchiveDir string
+	// Clock, if set, is what the server reads the time from, e.g. to
+	// reject timers due in the past. Nil uses the wall clock.
+	Clock clock.Clock
+	// Wakeups, if set, is told about each created timer due within
+	// WakeupHorizon so the worker owning it can claim it without waiting
+	// for its next poll.
+	Wakeups wakeup.Publisher
+	// WakeupHorizon should be the workers' polling interval; timers due
+	// further out than that are found by a poll in time anyway. Zero
+	// means 5s, the default polling interval.
+	WakeupHorizon time.Duration
+	// ReplayLimit caps how many timers one replay may clone; a replay
+	// matching more is rejected rather than read into memory. Zero means
+	// 10000.
+	ReplayLimit int
 }
 
-func (s TimerServer) CreateTimer(ctx context.Context, t *sandmanv1.Timer) (*sandmanv1.IdentifierResponse, error) {
-	if t.GetDueUtc() == nil || t.GetDueUtc().AsTime().Before(time.Now().UTC()) {
-		return nil, status.Error(codes.InvalidArgument, "invalid `due_utc`; must be set and in the future")
-	}
-	if t.GetName() == "" {
-		return nil, status.Error(codes.InvalidArgument, "invalid `name`; must be set")
+const (
+	defaultWakeupHorizon = 5 * time.Second
+	defaultReplayLimit   = 10000
+)
+
+func (s TimerServer) replayLimitOrDefault() int {
+	if s.ReplayLimit > 0 {
+		return s.ReplayLimit
 	}
-	if t.GetHookUrl() == "" {
-		return nil, status.Error(codes.InvalidArgument, "invalid `hook_url`; must be set")
+	return defaultReplayLimit
+}
+
+// errReplayLimit stops a replay's reads once it has matched more timers
+// than the replay limit.
+var errReplayLimit = errors.New("replay limit exceeded")
+
+func (s TimerServer) wakeupHorizonOrDefault() time.Duration {
+	if s.WakeupHorizon > 0 {
+		return s.WakeupHorizon
 	}
-	if _, err := url.Parse(t.GetHookUrl()); err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid `hook_url`; could not parse url")
+	return defaultWakeupHorizon
+}
+
+// publishWakeup tells workers about t if it's due before they'd next
+// poll for it. It's best effort; a timer whose wakeup is lost is still
+// claimed by the next poll.
+func (s TimerServer) publishWakeup(ctx context.Context, t model.Timer, nowUTC time.Time) {
+	if s.Wakeups == nil || !t.DueUTC.Before(nowUTC.Add(s.wakeupHorizonOrDefault())) {
+		return
 	}
-	if strings.EqualFold(t.GetHookMethod(), http.MethodGet) && len(t.GetHookBody()) > 0 {
-		return nil, status.Error(codes.InvalidArgument, "invalid hook; `hook_method` cannot be GET with a body specified")
+	if err := s.Wakeups.Publish(ctx, wakeup.Wakeup{Shard: t.Shard, DueUTC: t.DueUTC}); err != nil {
+		log.GetLogger(ctx).Error("timers; failed to publish wakeup", log.Any("err", err))
 	}
-	nowUTC := time.Now().UTC()
-	dueUTC := t.GetDueUtc().AsTime()
+}
 
-	var shard uint32
-	if shardKey := t.GetShardKey(); shardKey != "" {
-		shard = model.StableHash([]byte(shardKey))
-	}
+func (s TimerServer) now() time.Time {
+	return clock.OrReal(s.Clock).Now().UTC()
+}
 
-	newTimer := model.Timer{
-		Name:        t.GetName(),
-		Labels:      t.GetLabels(),
-		Priority:    t.GetPriority(),
-		ShardKey:    t.GetShardKey(),
-		Shard:       shard,
-		CreatedUTC:  nowUTC,
-		DueUTC:      dueUTC,
-		HookURL:     t.GetHookUrl(),
-		HookMethod:  t.GetHookMethod(),
-		HookHeaders: t.GetHookHeaders(),
-		HookBody:    t.GetHookBody(),
+func (s TimerServer) CreateTimer(ctx context.Context, t *sandmanv1.Timer) (*sandmanv1.IdentifierResponse, error) {
+	nowUTC := s.now()
+	if t.GetDueUtc() == nil || t.GetDueUtc().AsTime().Before(nowUTC) {
+		return nil, status.Error(codes.InvalidArgument, "invalid `due_utc`; must be set and in the future")
 	}
-	if err := s.Model.Invoke(ctx).Create(&newTimer); err != nil {
+	newTimer, err := s.modelTimerFromProto(t, nowUTC)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Model.CreateTimer(ctx, &newTimer); err != nil {
 		err = status.Error(codes.Internal, err.Error())
 		return nil, err
 	}
+	s.publishWakeup(ctx, newTimer, nowUTC)
 	return &sandmanv1.IdentifierResponse{
 		Id: newTimer.ID.String(),
 	}, nil
 }
 
+func (s TimerServer) ExportTimers(args *sandmanv1.ExportTimersArgs, stream sandmanv1.Timers_ExportTimersServer) error {
+	var compiledSelector selector.Selector
+	var err error
+	if rawSelector := args.GetSelector(); rawSelector != "" {
+		compiledSelector, err = selector.Parse(rawSelector)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid selector; %v", err))
+		}
+	}
+	// unlike list, export defaults to every pending timer rather than
+	// the next hour's worth.
+	after := time.Time{}
+	before := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	if args.GetBefore() != nil && !args.GetBefore().AsTime().IsZero() {
+		before = args.GetBefore().AsTime()
+	}
+	if args.GetAfter() != nil && !args.GetAfter().AsTime().IsZero() {
+		after = args.GetAfter().AsTime()
+	}
+	err = s.Model.ExportTimers(stream.Context(), after, before, compiledSelector, func(t model.Timer) error {
+		return stream.Send(s.protoTimerFromModel(t))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+func (s TimerServer) ImportTimers(stream sandmanv1.Timers_ImportTimersServer) error {
+	var output sandmanv1.ImportTimersResponse
+	nowUTC := s.now()
+	for {
+		args, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&output)
+		}
+		if err != nil {
+			return err
+		}
+		// Imported timers may already be past due (e.g. restored from a
+		// snapshot); they're delivered as soon as a worker claims them.
+		if args.GetTimer().GetDueUtc() == nil {
+			return status.Error(codes.InvalidArgument, "invalid `due_utc`; must be set")
+		}
+		newTimer, err := s.modelTimerFromProto(args.GetTimer(), nowUTC)
+		if err != nil {
+			return err
+		}
+		var onConflict model.ImportConflict
+		switch args.GetOnConflict() {
+		case sandmanv1.ImportConflictPolicy_IMPORT_CONFLICT_FAIL:
+			onConflict = model.ImportConflictFail
+		case sandmanv1.ImportConflictPolicy_IMPORT_CONFLICT_SKIP:
+			onConflict = model.ImportConflictSkip
+		case sandmanv1.ImportConflictPolicy_IMPORT_CONFLICT_REPLACE:
+			onConflict = model.ImportConflictReplace
+		default:
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("invalid `on_conflict`; %v", args.GetOnConflict()))
+		}
+		result, err := s.Model.ImportTimer(stream.Context(), &newTimer, onConflict)
+		if errors.Is(err, model.ErrTimerNameExists) {
+			return status.Error(codes.AlreadyExists, fmt.Sprintf("%v; imported %d timers before the conflict", err, output.Created+output.Replaced))
+		}
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		switch result {
+		case model.ImportCreated:
+			output.Created++
+		case model.ImportSkipped:
+			output.Skipped++
+		case model.ImportReplaced:
+			output.Replaced++
+		}
+	}
+}
+
+func (s TimerServer) ReplayTimers(ctx context.Context, args *sandmanv1.ReplayTimersArgs) (*sandmanv1.ReplayTimersResponse, error) {
+	var compiledSelector selector.Selector
+	var err error
+	if rawSelector := args.GetSelector(); rawSelector != "" {
+		compiledSelector, err = selector.Parse(rawSelector)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid selector; %v", err))
+		}
+	}
+	if args.GetDeliveredAfter() == nil || args.GetDeliveredBefore() == nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid range; `delivered_after` and `delivered_before` must be set")
+	}
+	deliveredAfter := args.GetDeliveredAfter().AsTime()
+	deliveredBefore := args.GetDeliveredBefore().AsTime()
+	if !deliveredBefore.After(deliveredAfter) {
+		return nil, status.Error(codes.InvalidArgument, "invalid range; `delivered_before` must be after `delivered_after`")
+	}
+	nowUTC := s.now()
+	dueUTC := nowUTC
+	var spread time.Duration
+	switch {
+	case args.GetDueUtc() != nil:
+		dueUTC = args.GetDueUtc().AsTime()
+	case args.GetSpread() != nil:
+		spread = args.GetSpread().AsDuration()
+		if spread < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid `spread`; must not be negative")
+		}
+	}
+
+	replayLimit := s.replayLimitOrDefault()
+	var originals []model.Timer
+	err = s.Model.ExportDeliveredTimers(ctx, deliveredAfter, deliveredBefore, compiledSelector, func(t model.Timer) error {
+		if len(originals) == replayLimit {
+			return errReplayLimit
+		}
+		originals = append(originals, t)
+		return nil
+	})
+	if err == nil && s.ArchiveDir != "" {
+		// a timer is archived before it's deleted, so it can briefly be
+		// in both places.
+		seen := make(map[uuid.UUID]struct{}, len(originals))
+		for _, t := range originals {
+			seen[t.ID] = struct{}{}
+		}
+		err = archive.Search(s.ArchiveDir, archive.Query{
+			Selector:        compiledSelector,
+			DeliveredAfter:  deliveredAfter,
+			DeliveredBefore: deliveredBefore,
+		}, func(t model.Timer) error {
+			if _, ok := seen[t.ID]; ok {
+				return nil
+			}
+			if len(originals) == replayLimit {
+				return errReplayLimit
+			}
+			seen[t.ID] = struct{}{}
+			originals = append(originals, t)
+			return nil
+		})
+	}
+	if errors.Is(err, errReplayLimit) {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("too many timers to replay; more than %d match, narrow the range or selector", replayLimit))
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if s.ArchiveDir != "" {
+		sort.Slice(originals, func(i, j int) bool {
+			return originals[i].DeliveredUTC.Before(*originals[j].DeliveredUTC)
+		})
+	}
+	// the egress policy may have tightened since the originals were
+	// created; check them all before creating any replays.
+	for _, t := range originals {
+		if err := s.Egress.CheckURL(t.HookURL); err != nil {
+			return nil, status.Error(codes.PermissionDenied, fmt.Sprintf("cannot replay %q; %v", t.Name, err))
+		}
+	}
+
+	replayID := uuid.V4().ShortString()
+	output := sandmanv1.ReplayTimersResponse{
+		ReplayId: replayID,
+	}
+	for index, t := range originals {
+		replayDueUTC := dueUTC
+		if spread > 0 {
+			replayDueUTC = dueUTC.Add(time.Duration(int64(spread) * int64(index) / int64(len(originals))))
+		}
+		replay := t.Replay(replayID, nowUTC, replayDueUTC)
+		if err := s.Model.CreateTimer(ctx, &replay); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("%v; replayed %d timers (replay id %s) before the failure", err, output.Replayed, replayID))
+		}
+		output.Replayed++
+	}
+	return &output, nil
+}
+
+// GetDeliveryStats returns the delivery lateness rollups within the
+// range, defaulting to the last hour.
+func (s TimerServer) GetDeliveryStats(ctx context.Context, args *sandmanv1.GetDeliveryStatsArgs) (*sandmanv1.GetDeliveryStatsResponse, error) {
+	var compiledSelector selector.Selector
+	var err error
+	if rawSelector := args.GetSelector(); rawSelector != "" {
+		compiledSelector, err = selector.Parse(rawSelector)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid selector; %v", err))
+		}
+	}
+	before := s.now()
+	if args.GetBefore() != nil && !args.GetBefore().AsTime().IsZero() {
+		before = args.GetBefore().AsTime()
+	}
+	after := before.Add(-time.Hour)
+	if args.GetAfter() != nil && !args.GetAfter().AsTime().IsZero() {
+		after = args.GetAfter().AsTime()
+	}
+	if !before.After(after) {
+		return nil, status.Error(codes.InvalidArgument, "invalid range; `before` must be after `after`")
+	}
+
+	stats, err := s.Model.GetDeliveryStats(ctx, after, before, compiledSelector)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	output := sandmanv1.GetDeliveryStatsResponse{
+		Stats: make([]*sandmanv1.DeliveryStats, 0, len(stats)),
+	}
+	for _, ds := range stats {
+		output.Stats = append(output.Stats, &sandmanv1.DeliveryStats{
+			BucketUtc:   timestamppb.New(ds.BucketUTC),
+			ShardKey:    ds.ShardKey,
+			Count:       uint64(ds.Count),
+			LatenessP50: durationpb.New(ds.LatenessP50),
+			LatenessP99: durationpb.New(ds.LatenessP99),
+			LatenessMax: durationpb.New(ds.LatenessMax),
+		})
+	}
+	return &output, nil
+}
+
 func minutesUntil(now, dueUTC time.Time) uint64 {
 	diff := dueUTC.Sub(now)
 	if diff <= 0 {
@@ -88,7 +353,7 @@ func (s TimerServer) ListTimers(ctx context.Context, args *sandmanv1.ListTimersA
 			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid selector; %v", err))
 		}
 	}
-	before := time.Now().UTC().Add(time.Hour)
+	before := s.now().Add(time.Hour)
 	after := time.Time{}
 
 	if args.GetBefore() != nil && !args.GetBefore().AsTime().IsZero() {
@@ -152,8 +417,7 @@ func (s TimerServer) getTimerByNameOrID(ctx context.Context, id, name string) (*
 		if err != nil {
 			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%q is not a valid uuid", id))
 		}
-		var t model.Timer
-		found, err := s.Model.Invoke(ctx).Get(&t, parsedID)
+		t, found, err := s.Model.GetTimerByID(ctx, parsedID)
 		if err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -175,11 +439,68 @@ func (s TimerServer) getTimerByNameOrID(ctx context.Context, id, name string) (*
 	return nil, status.Error(codes.InvalidArgument, "one of `id` or `name` is required")
 }
 
+// modelTimerFromProto validates a timer's fields (other than whether
+// it's due in the future, which only create requires) and returns the
+// model timer to insert.
+func (s TimerServer) modelTimerFromProto(t *sandmanv1.Timer, nowUTC time.Time) (model.Timer, error) {
+	if t.GetName() == "" {
+		return model.Timer{}, status.Error(codes.InvalidArgument, "invalid `name`; must be set")
+	}
+	if t.GetHookUrl() == "" {
+		return model.Timer{}, status.Error(codes.InvalidArgument, "invalid `hook_url`; must be set")
+	}
+	if _, err := url.Parse(t.GetHookUrl()); err != nil {
+		return model.Timer{}, status.Error(codes.InvalidArgument, "invalid `hook_url`; could not parse url")
+	}
+	if err := s.Egress.CheckURL(t.GetHookUrl()); err != nil {
+		return model.Timer{}, status.Error(codes.PermissionDenied, fmt.Sprintf("invalid `hook_url`; %v", err))
+	}
+	if strings.EqualFold(t.GetHookMethod(), http.MethodGet) && len(t.GetHookBody()) > 0 {
+		return model.Timer{}, status.Error(codes.InvalidArgument, "invalid hook; `hook_method` cannot be GET with a body specified")
+	}
+	dueUTC := t.GetDueUtc().AsTime()
+
+	var expiresUTC *time.Time
+	if t.GetExpiresUtc() != nil && t.GetStaleAfter() != nil {
+		return model.Timer{}, status.Error(codes.InvalidArgument, "invalid expiry; only one of `expires_utc` or `stale_after` may be set")
+	}
+	if t.GetExpiresUtc() != nil {
+		expiresUTC = utils.Ref(t.GetExpiresUtc().AsTime())
+	} else if t.GetStaleAfter() != nil {
+		if t.GetStaleAfter().AsDuration() <= 0 {
+			return model.Timer{}, status.Error(codes.InvalidArgument, "invalid `stale_after`; must be positive")
+		}
+		expiresUTC = utils.Ref(dueUTC.Add(t.GetStaleAfter().AsDuration()))
+	}
+	if expiresUTC != nil && !expiresUTC.After(dueUTC) {
+		return model.Timer{}, status.Error(codes.InvalidArgument, "invalid `expires_utc`; must be after `due_utc`")
+	}
+
+	return model.Timer{
+		Name:        t.GetName(),
+		Labels:      t.GetLabels(),
+		Priority:    t.GetPriority(),
+		ShardKey:    t.GetShardKey(),
+		Shard:       model.TimerShard(t.GetShardKey(), t.GetOrderingKey()),
+		OrderingKey: t.GetOrderingKey(),
+		CreatedUTC:  nowUTC,
+		DueUTC:      dueUTC,
+		ExpiresUTC:  expiresUTC,
+		HookURL:     t.GetHookUrl(),
+		HookMethod:  t.GetHookMethod(),
+		HookHeaders: t.GetHookHeaders(),
+		HookBody:    t.GetHookBody(),
+	}, nil
+}
+
 func (s TimerServer) protoTimerFromModel(t model.Timer) *sandmanv1.Timer {
 	output := &sandmanv1.Timer{
 		Id:                  t.ID.ShortString(),
 		Name:                t.Name,
 		Labels:              t.Labels,
+		Priority:            t.Priority,
+		ShardKey:            t.ShardKey,
+		OrderingKey:         t.OrderingKey,
 		CreatedUtc:          timestamppb.New(t.CreatedUTC),
 		DueUtc:              timestamppb.New(t.DueUTC),
 		Attempt:             uint32(t.Attempt),
@@ -196,5 +517,14 @@ func (s TimerServer) protoTimerFromModel(t model.Timer) *sandmanv1.Timer {
 	if t.DeliveredUTC != nil && !t.DeliveredUTC.IsZero() {
 		output.DeliveredUtc = timestamppb.New(*t.DeliveredUTC)
 	}
+	if t.FiredUTC != nil && !t.FiredUTC.IsZero() {
+		output.FiredUtc = timestamppb.New(*t.FiredUTC)
+	}
+	if t.ExpiresUTC != nil && !t.ExpiresUTC.IsZero() {
+		output.ExpiresUtc = timestamppb.New(*t.ExpiresUTC)
+	}
+	if t.ExpiredUTC != nil && !t.ExpiredUTC.IsZero() {
+		output.ExpiredUtc = timestamppb.New(*t.ExpiredUTC)
+	}
 	return output
 }
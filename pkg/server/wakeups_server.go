@@ -0,0 +1,35 @@
+package server
+
+import (
+	"sandman/pkg/wakeup"
+
+	sandmanv1 "sandman/proto/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WakeupServer streams the wakeups the TimerServer publishes to its Hub
+// out to the workers watching it.
+type WakeupServer struct {
+	sandmanv1.WakeupsServer
+	Hub *wakeup.Hub
+}
+
+func (s WakeupServer) Watch(_ *sandmanv1.WatchWakeupsArgs, stream sandmanv1.Wakeups_WatchServer) error {
+	ctx := stream.Context()
+	wakeups, unsubscribe := s.Hub.Subscribe()
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case w := <-wakeups:
+			if err := stream.Send(&sandmanv1.Wakeup{
+				Shard:  w.Shard,
+				DueUtc: timestamppb.New(w.DueUTC),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -13,7 +13,7 @@ import (
 
 type WorkerServer struct {
 	sandmanv1.WorkersServer
-	Model *model.Manager
+	Model model.TimerStore
 }
 
 func (s WorkerServer) ListWorkers(ctx context.Context, args *sandmanv1.ListWorkersArgs) (*sandmanv1.ListWorkersResponse, error) {
@@ -33,9 +33,11 @@ func (s WorkerServer) ListWorkers(ctx context.Context, args *sandmanv1.ListWorke
 
 func (s WorkerServer) protoWorkerFromModel(t model.Worker) *sandmanv1.Worker {
 	output := &sandmanv1.Worker{
-		Hostname:    t.Hostname,
-		CreatedUtc:  timestamppb.New(t.CreatedUTC),
-		LastSeenUtc: timestamppb.New(t.LastSeenUTC),
+		Hostname:       t.Hostname,
+		CreatedUtc:     timestamppb.New(t.CreatedUTC),
+		LastSeenUtc:    timestamppb.New(t.LastSeenUTC),
+		CapacityWeight: int64(t.CapacityWeightOrDefault()),
+		WheelOccupancy: t.WheelOccupancy,
 	}
 	return output
 }
@@ -0,0 +1,58 @@
+package wakeup
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"sandman/pkg/log"
+	sandmanv1 "sandman/proto/v1"
+)
+
+// Client delivers wakeups streamed from a sandman-srv's Wakeups service.
+// A server only streams wakeups for timers created through it, so with
+// several servers a worker watches each of them (see Sources).
+type Client struct {
+	// Addr is the server's gRPC address.
+	Addr string
+	// Hostname identifies the worker to the server.
+	Hostname string
+}
+
+// Watch implements Source.
+func (c Client) Watch(ctx context.Context, fn func(Wakeup)) error {
+	conn, err := grpc.NewClient(c.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	client := sandmanv1.NewWakeupsClient(conn)
+	for {
+		err := c.watch(ctx, client, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.GetLogger(ctx).Error("wakeup; watch disconnected", log.String("addr", c.Addr), log.Any("err", err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (c Client) watch(ctx context.Context, client sandmanv1.WakeupsClient, fn func(Wakeup)) error {
+	stream, err := client.Watch(ctx, &sandmanv1.WatchWakeupsArgs{Hostname: c.Hostname})
+	if err != nil {
+		return err
+	}
+	for {
+		w, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		fn(Wakeup{Shard: w.GetShard(), DueUTC: w.GetDueUtc().AsTime()})
+	}
+}
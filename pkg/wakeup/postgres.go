@@ -0,0 +1,98 @@
+package wakeup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"sandman/pkg/db"
+	"sandman/pkg/log"
+)
+
+// Channel is the Postgres LISTEN / NOTIFY channel wakeups are sent on.
+const Channel = "sandman_wakeups"
+
+// Notifier publishes wakeups with Postgres NOTIFY, reaching every worker
+// listening on the same database regardless of which server created the
+// timer. CockroachDB has no NOTIFY; use the gRPC service there instead.
+type Notifier struct {
+	Conn *db.Connection
+}
+
+// Publish implements Publisher.
+func (n Notifier) Publish(ctx context.Context, w Wakeup) error {
+	_, err := n.Conn.ExecContext(ctx, "SELECT pg_notify($1, $2)", Channel, FormatPayload(w))
+	return err
+}
+
+// Listener delivers wakeups sent by a Notifier. It holds a dedicated
+// connection outside the pool, since LISTEN is bound to the session.
+type Listener struct {
+	DSN string
+}
+
+// Watch implements Source.
+func (l Listener) Watch(ctx context.Context, fn func(Wakeup)) error {
+	for {
+		err := l.listen(ctx, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.GetLogger(ctx).Error("wakeup; postgres listener disconnected", log.Any("err", err))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (l Listener) listen(ctx context.Context, fn func(Wakeup)) error {
+	conn, err := pgx.Connect(ctx, l.DSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		w, err := ParsePayload(notification.Payload)
+		if err != nil {
+			log.GetLogger(ctx).Error("wakeup; invalid notification", log.Any("err", err))
+			continue
+		}
+		fn(w)
+	}
+}
+
+// FormatPayload encodes a wakeup as a NOTIFY payload, its shard and due
+// time in unix nanos separated by a space.
+func FormatPayload(w Wakeup) string {
+	return strconv.FormatUint(uint64(w.Shard), 10) + " " + strconv.FormatInt(w.DueUTC.UnixNano(), 10)
+}
+
+// ParsePayload decodes a NOTIFY payload written by FormatPayload.
+func ParsePayload(payload string) (Wakeup, error) {
+	rawShard, rawDue, ok := strings.Cut(payload, " ")
+	if !ok {
+		return Wakeup{}, fmt.Errorf("invalid wakeup payload; %q", payload)
+	}
+	shard, err := strconv.ParseUint(rawShard, 10, 32)
+	if err != nil {
+		return Wakeup{}, fmt.Errorf("invalid wakeup shard: %w; %q", err, payload)
+	}
+	due, err := strconv.ParseInt(rawDue, 10, 64)
+	if err != nil {
+		return Wakeup{}, fmt.Errorf("invalid wakeup due time: %w; %q", err, payload)
+	}
+	return Wakeup{Shard: uint32(shard), DueUTC: time.Unix(0, due).UTC()}, nil
+}
@@ -0,0 +1,136 @@
+// Package wakeup tells workers about timers due before their next poll.
+//
+// Workers find due timers by polling, so a timer created shortly before
+// it's due can otherwise sit for up to a polling interval. When a timer
+// is created due within that interval, the server publishes a Wakeup
+// naming its shard and due time; the worker owning the shard runs a
+// claim for just that shard instead of waiting for the next poll.
+//
+// Wakeups are best effort. A lost wakeup only means the timer is picked
+// up by the next poll, as it would be without them.
+package wakeup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Wakeup is a timer due before workers would next poll for it.
+type Wakeup struct {
+	Shard  uint32
+	DueUTC time.Time
+}
+
+// Publisher sends wakeups to workers.
+type Publisher interface {
+	Publish(ctx context.Context, w Wakeup) error
+}
+
+// Source delivers wakeups to a worker.
+//
+// Watch calls fn for each wakeup until ctx is done, reconnecting to its
+// upstream as needed. fn must not block.
+type Source interface {
+	Watch(ctx context.Context, fn func(Wakeup)) error
+}
+
+// Publishers publishes each wakeup to every publisher in turn.
+type Publishers []Publisher
+
+// Publish implements Publisher.
+func (p Publishers) Publish(ctx context.Context, w Wakeup) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, w); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sources watches every source at once.
+type Sources []Source
+
+// Watch implements Source.
+func (s Sources) Watch(ctx context.Context, fn func(Wakeup)) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(s))
+	for index, source := range s {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[index] = source.Watch(ctx, fn)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// hubBuffer is how many wakeups a slow subscriber may fall behind by
+// before the hub drops wakeups for it.
+const hubBuffer = 1024
+
+// NewHub returns an empty hub.
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[chan Wakeup]struct{})}
+}
+
+// Hub fans wakeups out to subscribers in process. Publish never blocks;
+// a subscriber that falls behind misses wakeups rather than holding up
+// timer creation.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan Wakeup]struct{}
+}
+
+// Publish implements Publisher.
+func (h *Hub) Publish(_ context.Context, w Wakeup) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- w:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel of every wakeup published from now on, and
+// a func that unsubscribes and closes it.
+func (h *Hub) Subscribe() (<-chan Wakeup, func()) {
+	subscriber := make(chan Wakeup, hubBuffer)
+	h.mu.Lock()
+	h.subscribers[subscriber] = struct{}{}
+	h.mu.Unlock()
+	var once sync.Once
+	return subscriber, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers, subscriber)
+			h.mu.Unlock()
+			close(subscriber)
+		})
+	}
+}
+
+// Watch implements Source, so a worker running in the same process as
+// the server can subscribe directly.
+func (h *Hub) Watch(ctx context.Context, fn func(Wakeup)) error {
+	wakeups, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case w := <-wakeups:
+			fn(w)
+		}
+	}
+}
+
+// reconnectDelay is how long the Postgres listener and the gRPC client
+// wait before reconnecting after losing their upstream.
+const reconnectDelay = time.Second
@@ -0,0 +1,48 @@
+package wakeup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sandman/pkg/assert"
+)
+
+func TestPayload(t *testing.T) {
+	w := Wakeup{Shard: 4294967295, DueUTC: time.Date(2026, 4, 25, 12, 0, 0, 123456789, time.UTC)}
+	payload := FormatPayload(w)
+	assert.Equal(t, "4294967295 1777118400123456789", payload)
+	parsed, err := ParsePayload(payload)
+	assert.Nil(t, err)
+	assert.Equal(t, w, parsed)
+
+	for _, invalid := range []string{"", "7", "x 1", "7 x", "4294967296 1"} {
+		_, err := ParsePayload(invalid)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestHub(t *testing.T) {
+	ctx := context.Background()
+	hub := NewHub()
+	first, unsubscribeFirst := hub.Subscribe()
+	second, unsubscribeSecond := hub.Subscribe()
+	defer unsubscribeSecond()
+
+	w := Wakeup{Shard: 7, DueUTC: time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)}
+	assert.Nil(t, hub.Publish(ctx, w))
+	assert.Equal(t, w, <-first)
+	assert.Equal(t, w, <-second)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+	_, open := <-first
+	assert.False(t, open)
+
+	// a subscriber that isn't keeping up misses wakeups rather than
+	// blocking the publisher.
+	for range hubBuffer + 1 {
+		assert.Nil(t, hub.Publish(ctx, w))
+	}
+	assert.Equal(t, hubBuffer, len(second))
+}
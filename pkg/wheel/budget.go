@@ -0,0 +1,96 @@
+package wheel
+
+import (
+	"math"
+
+	"sandman/pkg/model"
+)
+
+// OptMaxHeld caps how many timers the wheel holds, slots and overflow
+// heap together; Insert rejects the rest. Zero, the default, is
+// unbounded.
+func OptMaxHeld(maxHeld int) Option {
+	return func(w *Wheel) {
+		w.maxHeld = maxHeld
+	}
+}
+
+// OptMaxHeldBytes caps the approximate memory the held timers take up,
+// bodies and headers included; Insert rejects a timer that would take
+// the wheel past it. Zero, the default, is unbounded.
+func OptMaxHeldBytes(maxHeldBytes int64) Option {
+	return func(w *Wheel) {
+		w.maxHeldBytes = maxHeldBytes
+	}
+}
+
+// timerOverhead approximates the fixed cost of holding a timer: the
+// model.Timer itself, its entry and its ids map slot.
+const timerOverhead = 512
+
+// timerSize approximates how much memory holding t takes.
+func timerSize(t *model.Timer) int64 {
+	size := timerOverhead + len(t.Name) + len(t.ShardKey) + len(t.OrderingKey) +
+		len(t.HookURL) + len(t.HookMethod) + len(t.HookBody)
+	for key, value := range t.Labels {
+		size += len(key) + len(value)
+	}
+	for key, value := range t.HookHeaders {
+		size += len(key) + len(value)
+	}
+	return int64(size)
+}
+
+// fits returns if the budgets leave room for another timer of the given
+// size.
+func (w *Wheel) fits(size int64) bool {
+	if w.maxHeld > 0 && len(w.ids) >= w.maxHeld {
+		return false
+	}
+	if w.maxHeldBytes > 0 && w.heldBytes+size > w.maxHeldBytes {
+		return false
+	}
+	return true
+}
+
+// HeldBytes returns the approximate memory the held timers take up.
+func (w *Wheel) HeldBytes() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.heldBytes
+}
+
+// Occupancy returns how full the wheel is against the tighter of its
+// budgets, from 0 (empty) to 1 (full). A wheel without budgets reports
+// zero.
+func (w *Wheel) Occupancy() (occupancy float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.maxHeld > 0 {
+		occupancy = float64(len(w.ids)) / float64(w.maxHeld)
+	}
+	if w.maxHeldBytes > 0 {
+		occupancy = max(occupancy, float64(w.heldBytes)/float64(w.maxHeldBytes))
+	}
+	return min(occupancy, 1)
+}
+
+// Free estimates how many more timers the wheel can take before Insert
+// starts rejecting them, sizing the byte budget's slack by the average
+// timer held. A wheel without budgets returns math.MaxInt.
+func (w *Wheel) Free() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	free := math.MaxInt
+	if w.maxHeld > 0 {
+		free = max(w.maxHeld-len(w.ids), 0)
+	}
+	if w.maxHeldBytes > 0 {
+		average := int64(timerOverhead)
+		if len(w.ids) > 0 {
+			average = max(w.heldBytes/int64(len(w.ids)), 1)
+		}
+		free = min(free, int(max(w.maxHeldBytes-w.heldBytes, 0)/average))
+	}
+	return free
+}
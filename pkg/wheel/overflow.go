@@ -0,0 +1,39 @@
+package wheel
+
+import "container/heap"
+
+// overflowLevel is the level of an entry waiting in the overflow heap
+// rather than in a slot.
+const overflowLevel = -1
+
+// overflow is a min-heap on due_utc of the timers due beyond the wheel's
+// horizon. Each entry's index is its position in the heap so it can be
+// removed in place.
+type overflow []*entry
+
+var _ heap.Interface = (*overflow)(nil)
+
+func (o overflow) Len() int { return len(o) }
+
+func (o overflow) Less(i, j int) bool { return o[i].timer.DueUTC.Before(o[j].timer.DueUTC) }
+
+func (o overflow) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+	o[i].index = i
+	o[j].index = j
+}
+
+func (o *overflow) Push(x any) {
+	e := x.(*entry)
+	e.level = overflowLevel
+	e.index = len(*o)
+	*o = append(*o, e)
+}
+
+func (o *overflow) Pop() any {
+	old := *o
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*o = old[:len(old)-1]
+	return e
+}
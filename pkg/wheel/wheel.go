@@ -1,14 +1,21 @@
-// Package wheel implements an in-memory single-level timer wheel used as
+// Package wheel implements an in-memory hierarchical timer wheel used as
 // a worker-side dispatch buffer in front of the durable timers table.
 //
-// Each slot holds the timers whose due_utc falls in a one-second window;
-// Advance returns the timers whose slot the cursor has reached or
-// passed, in time order, so the caller can fire them. Storage is the
-// source of truth — the wheel is purely a cache of claimed timers and
-// nothing here is durable across restarts.
+// The finest level has one slot per resolution (one second by default)
+// and each coarser level (seconds, minutes, hours) has one slot per unit
+// of the level below; timers start in the finest level that can place
+// them and cascade down as the cursor reaches their coarse slot. Advance
+// returns the timers whose finest slot the cursor has reached or passed,
+// in time order, so the caller can fire them. Timers due beyond the
+// horizon wait in an overflow heap until the cursor brings them within
+// it. Storage is the source of
+// truth — the wheel is purely a cache of claimed timers and nothing here
+// is durable across restarts.
 package wheel
 
 import (
+	"container/heap"
+	"fmt"
 	"sort"
 	"sync"
 	"time"
@@ -17,41 +24,140 @@ import (
 	"sandman/pkg/uuid"
 )
 
-// New returns a wheel with slotCount one-second slots whose cursor is
-// aligned to anchor truncated to the second. slotCount must be > 0;
-// callers should size it strictly greater than the prefetch window so
-// inserts never collide with the cursor mid-lap.
-func New(slotCount int, anchor time.Time) *Wheel {
+// DefaultResolution is the width of the finest slots if OptResolution
+// isn't given.
+const DefaultResolution = time.Second
+
+// levelWidths are the slot widths of the levels above the finest one.
+var levelWidths = []time.Duration{time.Second, time.Minute, time.Hour, 24 * time.Hour}
+
+// New returns a wheel that holds timers due within slotCount seconds of
+// its cursor, which is aligned to anchor truncated to the resolution.
+// slotCount must be > 0; callers should size it strictly greater than
+// the prefetch window so inserts never collide with the cursor mid-lap.
+func New(slotCount int, anchor time.Time, opts ...Option) *Wheel {
 	if slotCount <= 0 {
 		panic("wheel: slotCount must be > 0")
 	}
-	return &Wheel{
-		slots:    make([]slot, slotCount),
-		cursor:   0,
-		cursorAt: anchor.UTC().Truncate(time.Second),
-		ids:      make(map[uuid.UUID]struct{}),
+	w := &Wheel{
+		resolution: DefaultResolution,
+		horizon:    time.Duration(slotCount) * time.Second,
+		ids:        make(map[uuid.UUID]*entry),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.resolution <= 0 || w.resolution > time.Second || time.Second%w.resolution != 0 {
+		panic(fmt.Sprintf("wheel: resolution %v must evenly divide one second", w.resolution))
+	}
+
+	// each level spans one slot of the level above it; the top level
+	// is sized to cover the horizon.
+	widths := []time.Duration{w.resolution}
+	for _, width := range levelWidths {
+		if width <= w.resolution {
+			continue
+		}
+		if w.horizon <= width {
+			break
+		}
+		widths = append(widths, width)
+	}
+	w.levels = make([]level, len(widths))
+	for index, width := range widths {
+		slotsPerLevel := int((w.horizon+width-1)/width) + 1
+		if index < len(widths)-1 {
+			slotsPerLevel = int(widths[index+1] / width)
+		}
+		w.levels[index] = level{
+			width: width,
+			slots: make([][]*entry, slotsPerLevel),
+		}
+	}
+	w.cursorAt = anchor.UTC().Truncate(w.resolution)
+	return w
+}
+
+// Option mutates a wheel before its levels are built.
+type Option func(*Wheel)
+
+// OptMaxOverflow caps how many timers due beyond the horizon the wheel
+// holds in its overflow heap; Insert rejects the rest. Zero, the
+// default, is unbounded.
+func OptMaxOverflow(maxOverflow int) Option {
+	return func(w *Wheel) {
+		w.maxOverflow = maxOverflow
+	}
+}
+
+// OptResolution sets the width of the finest slots, and so how far
+// apart two timers can be due and still fire in the same Advance. It
+// must evenly divide one second; it defaults to one second.
+func OptResolution(resolution time.Duration) Option {
+	return func(w *Wheel) {
+		w.resolution = resolution
 	}
 }
 
 type Wheel struct {
-	mu       sync.Mutex
-	slots    []slot
-	cursor   int
+	mu         sync.Mutex
+	resolution time.Duration
+	horizon    time.Duration
+	levels     []level
+	// overflow holds the timers due beyond the horizon, up to
+	// maxOverflow of them if it's set.
+	overflow    overflow
+	maxOverflow int
+	// maxHeld and maxHeldBytes budget everything held; heldBytes is
+	// the running total timerSize of it.
+	maxHeld      int
+	maxHeldBytes int64
+	heldBytes    int64
+	// cursorAt is the start of the next finest slot to fire.
 	cursorAt time.Time
-	// ids tracks every timer currently held in any slot so callers can
-	// dedupe across overlapping prefetches without a slot-by-slot scan.
-	ids map[uuid.UUID]struct{}
+	// ids tracks every timer currently held so callers can dedupe
+	// across overlapping prefetches (and remove timers) without a
+	// slot-by-slot scan.
+	ids map[uuid.UUID]*entry
 }
 
-type slot struct {
-	timers []*model.Timer
+type level struct {
+	width time.Duration
+	slots [][]*entry
+	// count is how many timers the level holds, so Advance can skip
+	// over stretches of empty slots.
+	count int
 }
 
-// Size returns the number of slots in the wheel.
-func (w *Wheel) Size() int { return len(w.slots) }
+// entry is a held timer and where it is; cascading updates it in place
+// rather than rewriting ids. An entry in the overflow heap has level
+// overflowLevel and index its position in the heap.
+type entry struct {
+	timer *model.Timer
+	size  int64
+	level int
+	slot  int
+	index int
+}
 
-// CursorAt returns the wall-clock second the wheel's cursor currently
-// points at. Useful in tests and for instrumentation.
+// bucket returns which width-wide bucket since the epoch ts falls in.
+func bucket(ts time.Time, width time.Duration) int64 {
+	return ts.UnixNano() / int64(width)
+}
+
+// Size returns the number of slots in the wheel across all levels.
+func (w *Wheel) Size() (size int) {
+	for _, l := range w.levels {
+		size += len(l.slots)
+	}
+	return
+}
+
+// Resolution returns the width of the finest slots.
+func (w *Wheel) Resolution() time.Duration { return w.resolution }
+
+// CursorAt returns the start of the finest slot the wheel's cursor
+// currently points at. Useful in tests and for instrumentation.
 func (w *Wheel) CursorAt() time.Time {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -59,69 +165,171 @@ func (w *Wheel) CursorAt() time.Time {
 }
 
 // Len returns how many timers the wheel is currently holding across all
-// slots. Used by the worker to bound prefetch fills and surface as a
-// metric.
+// slots and the overflow heap. Used by the worker to bound prefetch
+// fills and surface as a metric.
 func (w *Wheel) Len() int {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	return len(w.ids)
 }
 
+// InsertResult is what Insert did with a timer.
+type InsertResult int
+
+const (
+	// Inserted means the timer is in a slot and fires when the cursor
+	// reaches it.
+	Inserted InsertResult = iota
+	// Deferred means the timer is due beyond the horizon; it waits in
+	// the overflow heap and moves into a slot once the cursor brings it
+	// within the horizon.
+	Deferred
+	// Duplicate means the wheel already holds a timer with the same id
+	// (e.g. a re-claim during an overlapping prefetch); the caller can
+	// drop it without touching the DB.
+	Duplicate
+	// Rejected means the wheel can't hold the timer, because its
+	// budgets or the overflow heap are full; the caller should
+	// relinquish its lease so a peer can pick it up.
+	Rejected
+)
+
+func (r InsertResult) String() string {
+	switch r {
+	case Inserted:
+		return "inserted"
+	case Deferred:
+		return "deferred"
+	case Duplicate:
+		return "duplicate"
+	case Rejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("InsertResult(%d)", int(r))
+	}
+}
+
 // Insert places t in the slot for its DueUTC. Timers already past due
-// land in the cursor's slot so they fire on the next Advance. Returns
-// false if t is already held by the wheel (e.g. a re-claim during an
-// overlapping prefetch); the caller can drop the duplicate without
-// touching the DB.
-//
-// If t.DueUTC is further in the future than the wheel can represent
-// (>= slotCount seconds past the cursor) Insert returns false and the
-// caller should leave the timer for a later prefetch — the lease will
-// still expire safely if it is never picked back up.
-func (w *Wheel) Insert(t *model.Timer) bool {
+// land in the cursor's slot so they fire on the next Advance. Timers due
+// further in the future than the slots can represent (>= slotCount
+// seconds past the cursor) go to the overflow heap, or are rejected if
+// it's full. Any timer that would take the wheel past its budgets is
+// rejected.
+func (w *Wheel) Insert(t *model.Timer) InsertResult {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	if _, ok := w.ids[t.ID]; ok {
-		return false
+		return Duplicate
+	}
+	size := timerSize(t)
+	if !w.fits(size) {
+		return Rejected
+	}
+	due := w.slotAt(t.DueUTC)
+	if due.Sub(w.cursorAt) >= w.horizon {
+		if w.maxOverflow > 0 && len(w.overflow) >= w.maxOverflow {
+			return Rejected
+		}
+		e := &entry{timer: t, size: size}
+		w.ids[t.ID] = e
+		w.heldBytes += size
+		heap.Push(&w.overflow, e)
+		return Deferred
+	}
+	e := &entry{timer: t, size: size}
+	w.ids[t.ID] = e
+	w.heldBytes += size
+	w.place(e, due)
+	return Inserted
+}
+
+// OverflowLen returns how many of the timers the wheel holds are waiting
+// in the overflow heap.
+func (w *Wheel) OverflowLen() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.overflow)
+}
+
+// promote moves the overflow timers the cursor has brought within the
+// horizon into slots.
+func (w *Wheel) promote() {
+	for len(w.overflow) > 0 {
+		due := w.slotAt(w.overflow[0].timer.DueUTC)
+		if due.Sub(w.cursorAt) >= w.horizon {
+			return
+		}
+		w.place(heap.Pop(&w.overflow).(*entry), due)
+	}
+}
+
+// slotAt returns the start of the finest slot a timer due at dueUTC
+// fires in, never earlier than the cursor.
+func (w *Wheel) slotAt(dueUTC time.Time) time.Time {
+	due := dueUTC.UTC().Truncate(w.resolution)
+	if due.Before(w.cursorAt) {
+		return w.cursorAt
 	}
-	offset := max(int(t.DueUTC.UTC().Truncate(time.Second).Sub(w.cursorAt)/time.Second), 0)
-	if offset >= len(w.slots) {
-		return false
+	return due
+}
+
+// place puts e in the finest level whose parent slot is the cursor's,
+// i.e. the finest level that can tell t's slot apart from the cursor's
+// without wrapping.
+func (w *Wheel) place(e *entry, due time.Time) {
+	for index := range w.levels {
+		if index < len(w.levels)-1 {
+			parentWidth := w.levels[index+1].width
+			if bucket(due, parentWidth) != bucket(w.cursorAt, parentWidth) {
+				continue
+			}
+		}
+		l := &w.levels[index]
+		slot := int(bucket(due, l.width) % int64(len(l.slots)))
+		l.slots[slot] = append(l.slots[slot], e)
+		l.count++
+		e.level, e.slot, e.index = index, slot, len(l.slots[slot])-1
+		return
 	}
-	idx := (w.cursor + offset) % len(w.slots)
-	w.slots[idx].timers = append(w.slots[idx].timers, t)
-	w.ids[t.ID] = struct{}{}
-	return true
 }
 
 // Advance moves the cursor forward to now and returns every timer in
-// any slot whose wall-clock second has been reached (inclusive of the
-// slot at now). Timers are returned in due_utc order so the caller can
-// dispatch newest-overdue last; callers that care about priority should
-// re-sort the returned slice.
+// any slot whose start has been reached (inclusive of the slot at now).
+// Timers are returned in due_utc order so the caller can dispatch
+// newest-overdue last; callers that care about priority should re-sort
+// the returned slice.
 //
-// Advance is safe to call more frequently than once per second; sub-
-// second calls are idempotent because slots are emptied as they fire.
+// Advance is safe to call more frequently than the resolution; calls
+// within a slot are idempotent because slots are emptied as they fire.
 func (w *Wheel) Advance(now time.Time) []*model.Timer {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	now = now.UTC().Truncate(time.Second)
+	end := now.UTC().Truncate(w.resolution).Add(w.resolution)
 	var fired []*model.Timer
-	// Drain every slot whose wall-clock time is at or before now,
-	// catching up if the dispatcher fell behind. Bounded by slotCount
-	// so a long pause can't loop forever — anything older than that
-	// has already been overwritten by a later prefetch's wrap-around.
-	for steps := 0; steps < len(w.slots) && !w.cursorAt.After(now); steps++ {
-		s := &w.slots[w.cursor]
-		if len(s.timers) > 0 {
-			for _, t := range s.timers {
-				delete(w.ids, t.ID)
+	for w.cursorAt.Before(end) {
+		w.promote()
+		// coarse slots starting at the cursor cascade into the finer
+		// levels first, top down, so a minute slot can land timers in
+		// the second slot that cascades next.
+		for index := len(w.levels) - 1; index > 0; index-- {
+			if w.cursorAt.UnixNano()%int64(w.levels[index].width) == 0 {
+				w.cascade(index)
 			}
-			fired = append(fired, s.timers...)
-			s.timers = nil
 		}
-		w.cursor = (w.cursor + 1) % len(w.slots)
-		w.cursorAt = w.cursorAt.Add(time.Second)
+		finest := &w.levels[0]
+		slot := int(bucket(w.cursorAt, finest.width) % int64(len(finest.slots)))
+		if entries := finest.slots[slot]; len(entries) > 0 {
+			for _, e := range entries {
+				delete(w.ids, e.timer.ID)
+				w.heldBytes -= e.size
+				fired = append(fired, e.timer)
+			}
+			finest.count -= len(entries)
+			finest.slots[slot] = nil
+		}
+		w.cursorAt = w.nextCursor(end)
 	}
+	w.promote()
 	if len(fired) > 1 {
 		sort.SliceStable(fired, func(i, j int) bool {
 			return fired[i].DueUTC.Before(fired[j].DueUTC)
@@ -130,20 +338,141 @@ func (w *Wheel) Advance(now time.Time) []*model.Timer {
 	return fired
 }
 
+// nextCursor returns where the cursor goes after its current slot, skipping
+// ahead to the next coarse boundary while the finer levels are empty so
+// a long pause costs a step per occupied slot rather than per
+// resolution.
+func (w *Wheel) nextCursor(end time.Time) time.Time {
+	next := w.cursorAt.Add(w.resolution)
+	index := 1
+	for ; index < len(w.levels) && w.levels[index-1].count == 0; index++ {
+		width := w.levels[index].width
+		next = time.Unix(0, (bucket(w.cursorAt, width)+1)*int64(width)).UTC()
+	}
+	if index == len(w.levels) && w.levels[index-1].count == 0 {
+		next = end
+	}
+	// stop where the earliest overflow timer comes within the horizon so
+	// it's promoted before the cursor passes its slot.
+	if len(w.overflow) > 0 {
+		if promoteAt := w.slotAt(w.overflow[0].timer.DueUTC).Add(w.resolution - w.horizon); promoteAt.Before(next) {
+			next = promoteAt
+		}
+	}
+	if next.After(end) {
+		return end
+	}
+	return next
+}
+
+// cascade empties the cursor's slot of a coarse level back into the
+// wheel, which places each timer in a finer level.
+func (w *Wheel) cascade(index int) {
+	l := &w.levels[index]
+	slot := int(bucket(w.cursorAt, l.width) % int64(len(l.slots)))
+	entries := l.slots[slot]
+	if len(entries) == 0 {
+		return
+	}
+	l.slots[slot] = nil
+	l.count -= len(entries)
+	for _, e := range entries {
+		w.place(e, w.slotAt(e.timer.DueUTC))
+	}
+}
+
 // DrainAll empties the wheel and returns everything it was holding,
 // regardless of due_utc. Used during graceful shutdown so the worker
 // can relinquish its claim on un-fired timers in one DB call.
 func (w *Wheel) DrainAll() []*model.Timer {
 	w.mu.Lock()
 	defer w.mu.Unlock()
-	var out []*model.Timer
-	for i := range w.slots {
-		if len(w.slots[i].timers) == 0 {
-			continue
+	out := make([]*model.Timer, 0, len(w.ids))
+	for index := range w.levels {
+		l := &w.levels[index]
+		for i := range l.slots {
+			for _, e := range l.slots[i] {
+				out = append(out, e.timer)
+			}
+			l.slots[i] = nil
 		}
-		out = append(out, w.slots[i].timers...)
-		w.slots[i].timers = nil
+		l.count = 0
 	}
-	w.ids = make(map[uuid.UUID]struct{})
+	for _, e := range w.overflow {
+		out = append(out, e.timer)
+	}
+	w.overflow = nil
+	w.ids = make(map[uuid.UUID]*entry)
+	w.heldBytes = 0
 	return out
 }
+
+// Held returns a snapshot of every timer the wheel is currently holding,
+// in no particular order. The wheel keeps ownership of the timers; used
+// by the worker to renew their leases.
+func (w *Wheel) Held() []*model.Timer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make([]*model.Timer, 0, len(w.ids))
+	for _, e := range w.ids {
+		out = append(out, e.timer)
+	}
+	return out
+}
+
+// Upcoming returns the held timers that fire within the next `within`
+// of the cursor, in no particular order. It reads only the slots that
+// window overlaps, so it stays cheap however much the wheel holds; used
+// by the worker to pre-warm connections to their hosts.
+func (w *Wheel) Upcoming(within time.Duration) (out []*model.Timer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	end := w.cursorAt.Add(within)
+	for index := range w.levels {
+		l := &w.levels[index]
+		first := bucket(w.cursorAt, l.width)
+		last := min(bucket(end, l.width), first+int64(len(l.slots))-1)
+		for b := first; b <= last; b++ {
+			for _, e := range l.slots[int(b%int64(len(l.slots)))] {
+				if e.timer.DueUTC.Before(end) {
+					out = append(out, e.timer)
+				}
+			}
+		}
+	}
+	return
+}
+
+// Remove evicts the given timers from the wheel so they never fire,
+// returning how many were actually held. IDs the wheel isn't holding
+// (e.g. already fired) are ignored.
+func (w *Wheel) Remove(ids ...uuid.UUID) (removed int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, id := range ids {
+		e, ok := w.ids[id]
+		if !ok {
+			continue
+		}
+		delete(w.ids, id)
+		w.heldBytes -= e.size
+		removed++
+		if e.level == overflowLevel {
+			heap.Remove(&w.overflow, e.index)
+			continue
+		}
+		// order within a slot doesn't matter (Advance sorts what it
+		// fires), so swap the last timer into the hole.
+		l := &w.levels[e.level]
+		entries := l.slots[e.slot]
+		last := len(entries) - 1
+		if e.index != last {
+			entries[e.index] = entries[last]
+			entries[e.index].index = e.index
+		}
+		entries[last] = nil
+		l.slots[e.slot] = entries[:last]
+		l.count--
+	}
+	return
+}
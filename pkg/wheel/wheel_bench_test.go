@@ -0,0 +1,167 @@
+package wheel
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"sandman/pkg/model"
+	"sandman/pkg/uuid"
+)
+
+// The benchmarks hold 1M timers due evenly over a ten minute horizon and
+// compare the hierarchical wheel at a few resolutions against flatWheel,
+// the single-level one-second wheel it replaced.
+//
+//	go test ./pkg/wheel -run=^$ -bench=. -benchmem
+
+const (
+	benchHeldTimers = 1_000_000
+	benchHorizon    = 10 * time.Minute
+	benchTick       = 250 * time.Millisecond
+)
+
+var (
+	benchAnchor     = time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)
+	benchTimersOnce sync.Once
+	benchTimers     []*model.Timer
+)
+
+func heldTimers() []*model.Timer {
+	benchTimersOnce.Do(func() {
+		benchTimers = make([]*model.Timer, benchHeldTimers)
+		step := benchHorizon / benchHeldTimers
+		for index := range benchTimers {
+			benchTimers[index] = &model.Timer{ID: uuid.V4(), DueUTC: benchAnchor.Add(time.Duration(index) * step)}
+		}
+	})
+	return benchTimers
+}
+
+// benchWheel is the part of the wheel API the benchmarks exercise.
+type benchWheel interface {
+	Insert(*model.Timer) InsertResult
+	Advance(time.Time) []*model.Timer
+}
+
+func benchWheels() []struct {
+	name string
+	new  func() benchWheel
+} {
+	slotCount := int(benchHorizon/time.Second) + 1
+	output := []struct {
+		name string
+		new  func() benchWheel
+	}{
+		{"flat", func() benchWheel { return newFlatWheel(slotCount, benchAnchor) }},
+	}
+	for _, resolution := range []time.Duration{time.Second, 10 * time.Millisecond, time.Millisecond} {
+		output = append(output, struct {
+			name string
+			new  func() benchWheel
+		}{
+			fmt.Sprintf("hierarchical-%v", resolution),
+			func() benchWheel { return New(slotCount, benchAnchor, OptResolution(resolution)) },
+		})
+	}
+	return output
+}
+
+func BenchmarkInsert(b *testing.B) {
+	timers := heldTimers()
+	for _, bw := range benchWheels() {
+		b.Run(bw.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				w := bw.new()
+				for _, t := range timers {
+					w.Insert(t)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*len(timers)), "ns/timer")
+		})
+	}
+}
+
+// BenchmarkAdvance fires everything the wheel holds by advancing it over
+// the horizon a dispatch tick at a time.
+func BenchmarkAdvance(b *testing.B) {
+	timers := heldTimers()
+	for _, bw := range benchWheels() {
+		b.Run(bw.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				b.StopTimer()
+				w := bw.new()
+				for _, t := range timers {
+					w.Insert(t)
+				}
+				b.StartTimer()
+				var fired int
+				for now := benchAnchor; fired < len(timers); now = now.Add(benchTick) {
+					fired += len(w.Advance(now))
+				}
+			}
+		})
+	}
+}
+
+// flatWheel is the single-level one-second wheel, kept as the
+// benchmarks' baseline.
+type flatWheel struct {
+	mu       sync.Mutex
+	slots    [][]*model.Timer
+	cursor   int
+	cursorAt time.Time
+	ids      map[uuid.UUID]int
+}
+
+func newFlatWheel(slotCount int, anchor time.Time) *flatWheel {
+	return &flatWheel{
+		slots:    make([][]*model.Timer, slotCount),
+		cursorAt: anchor.UTC().Truncate(time.Second),
+		ids:      make(map[uuid.UUID]int),
+	}
+}
+
+func (w *flatWheel) Insert(t *model.Timer) InsertResult {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.ids[t.ID]; ok {
+		return Duplicate
+	}
+	offset := max(int(t.DueUTC.UTC().Truncate(time.Second).Sub(w.cursorAt)/time.Second), 0)
+	if offset >= len(w.slots) {
+		return Rejected
+	}
+	idx := (w.cursor + offset) % len(w.slots)
+	w.slots[idx] = append(w.slots[idx], t)
+	w.ids[t.ID] = idx
+	return Inserted
+}
+
+func (w *flatWheel) Advance(now time.Time) []*model.Timer {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now = now.UTC().Truncate(time.Second)
+	var fired []*model.Timer
+	for steps := 0; steps < len(w.slots) && !w.cursorAt.After(now); steps++ {
+		if timers := w.slots[w.cursor]; len(timers) > 0 {
+			for _, t := range timers {
+				delete(w.ids, t.ID)
+			}
+			fired = append(fired, timers...)
+			w.slots[w.cursor] = nil
+		}
+		w.cursor = (w.cursor + 1) % len(w.slots)
+		w.cursorAt = w.cursorAt.Add(time.Second)
+	}
+	if len(fired) > 1 {
+		sort.SliceStable(fired, func(i, j int) bool {
+			return fired[i].DueUTC.Before(fired[j].DueUTC)
+		})
+	}
+	return fired
+}
@@ -28,7 +28,7 @@ func TestInsertAndAdvance_FiresInSlot(t *testing.T) {
 
 	a := newTimer(now.Add(2 * time.Second))
 	b := newTimer(now.Add(5 * time.Second))
-	if !w.Insert(a) || !w.Insert(b) {
+	if w.Insert(a) != Inserted || w.Insert(b) != Inserted {
 		t.Fatal("expected both inserts to succeed")
 	}
 	if got := w.Len(); got != 2 {
@@ -62,7 +62,7 @@ func TestAdvance_DrainsCatchUpInOrder(t *testing.T) {
 		newTimer(now.Add(2 * time.Second)),
 	}
 	for _, tm := range timers {
-		if !w.Insert(tm) {
+		if w.Insert(tm) != Inserted {
 			t.Fatalf("insert failed for %v", tm.DueUTC)
 		}
 	}
@@ -83,7 +83,7 @@ func TestInsert_OverdueLandsInCursorSlot(t *testing.T) {
 	w := New(64, now)
 
 	overdue := newTimer(now.Add(-30 * time.Second))
-	if !w.Insert(overdue) {
+	if w.Insert(overdue) != Inserted {
 		t.Fatal("expected overdue insert to succeed")
 	}
 
@@ -93,16 +93,85 @@ func TestInsert_OverdueLandsInCursorSlot(t *testing.T) {
 	}
 }
 
-func TestInsert_BeyondHorizonRejected(t *testing.T) {
+func TestInsert_BeyondHorizonDeferred(t *testing.T) {
 	now := mustTime(t, "2026-04-25T12:00:00Z")
 	w := New(8, now)
 
-	tooFar := newTimer(now.Add(30 * time.Second))
-	if w.Insert(tooFar) {
-		t.Fatal("expected insert beyond horizon to be rejected")
+	near := newTimer(now.Add(3 * time.Second))
+	far := newTimer(now.Add(30 * time.Second))
+	farther := newTimer(now.Add(2 * time.Hour))
+	if got := w.Insert(near); got != Inserted {
+		t.Fatalf("insert near: got %v want %v", got, Inserted)
+	}
+	for _, tm := range []*model.Timer{farther, far} {
+		if got := w.Insert(tm); got != Deferred {
+			t.Fatalf("insert beyond horizon: got %v want %v", got, Deferred)
+		}
+	}
+	if got := w.Len(); got != 3 {
+		t.Fatalf("Len: got %d want 3", got)
+	}
+	if got := w.OverflowLen(); got != 2 {
+		t.Fatalf("OverflowLen: got %d want 2", got)
+	}
+
+	if fired := w.Advance(now.Add(29 * time.Second)); len(fired) != 1 || fired[0].ID != near.ID {
+		t.Fatalf("advance to t+29: want near, got %+v", fired)
+	}
+	if got := w.OverflowLen(); got != 1 {
+		t.Fatalf("OverflowLen after promoting far: got %d want 1", got)
+	}
+	if fired := w.Advance(now.Add(30 * time.Second)); len(fired) != 1 || fired[0].ID != far.ID {
+		t.Fatalf("advance to t+30: want far, got %+v", fired)
+	}
+	// a single long Advance still fires farther on time rather than
+	// leaving it in the overflow heap.
+	fired := w.Advance(now.Add(3 * time.Hour))
+	if len(fired) != 1 || fired[0].ID != farther.ID {
+		t.Fatalf("advance to t+3h: want farther, got %+v", fired)
 	}
 	if got := w.Len(); got != 0 {
-		t.Fatalf("Len: got %d want 0", got)
+		t.Fatalf("Len after drain: got %d want 0", got)
+	}
+}
+
+func TestInsert_OverflowFullRejected(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(8, now, OptMaxOverflow(1))
+
+	if got := w.Insert(newTimer(now.Add(30 * time.Second))); got != Deferred {
+		t.Fatalf("first insert beyond horizon: got %v want %v", got, Deferred)
+	}
+	if got := w.Insert(newTimer(now.Add(40 * time.Second))); got != Rejected {
+		t.Fatalf("insert with overflow full: got %v want %v", got, Rejected)
+	}
+	if got := w.Insert(newTimer(now.Add(2 * time.Second))); got != Inserted {
+		t.Fatalf("insert within horizon with overflow full: got %v want %v", got, Inserted)
+	}
+	if got := w.Len(); got != 2 {
+		t.Fatalf("Len: got %d want 2", got)
+	}
+}
+
+func TestRemove_FromOverflow(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(8, now)
+
+	a := newTimer(now.Add(time.Minute))
+	b := newTimer(now.Add(2 * time.Minute))
+	c := newTimer(now.Add(3 * time.Minute))
+	for _, tm := range []*model.Timer{c, a, b} {
+		w.Insert(tm)
+	}
+	if removed := w.Remove(a.ID); removed != 1 {
+		t.Fatalf("Remove: got %d want 1", removed)
+	}
+	if got := w.OverflowLen(); got != 2 {
+		t.Fatalf("OverflowLen: got %d want 2", got)
+	}
+	fired := w.Advance(now.Add(5 * time.Minute))
+	if len(fired) != 2 || fired[0].ID != b.ID || fired[1].ID != c.ID {
+		t.Fatalf("advance: want b then c, got %+v", fired)
 	}
 }
 
@@ -111,11 +180,11 @@ func TestInsert_DuplicateIDRejected(t *testing.T) {
 	w := New(64, now)
 
 	tm := newTimer(now.Add(2 * time.Second))
-	if !w.Insert(tm) {
+	if w.Insert(tm) != Inserted {
 		t.Fatal("first insert failed")
 	}
-	if w.Insert(tm) {
-		t.Fatal("duplicate insert should have returned false")
+	if got := w.Insert(tm); got != Duplicate {
+		t.Fatalf("duplicate insert: got %v want %v", got, Duplicate)
 	}
 	if got := w.Len(); got != 1 {
 		t.Fatalf("Len: got %d want 1", got)
@@ -158,7 +227,7 @@ func TestAdvance_LapWrapReusesSlots(t *testing.T) {
 
 	// Cursor is now at t+2, slotCount=4 → can insert up to t+5.
 	b := newTimer(now.Add(5 * time.Second))
-	if !w.Insert(b) {
+	if w.Insert(b) != Inserted {
 		t.Fatal("expected re-use insert to succeed")
 	}
 	fired := w.Advance(now.Add(5 * time.Second))
@@ -184,7 +253,241 @@ func TestDrainAll_ReturnsEverythingAndClears(t *testing.T) {
 		t.Fatalf("Len after drain: got %d, want 0", w.Len())
 	}
 	// After drain, the IDs should be insertable again.
-	if !w.Insert(a) {
+	if w.Insert(a) != Inserted {
 		t.Fatal("expected re-insert after drain to succeed")
 	}
 }
+
+func TestRemove_EvictsBeforeFiring(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(64, now)
+
+	a := newTimer(now.Add(2 * time.Second))
+	b := newTimer(now.Add(2 * time.Second))
+	c := newTimer(now.Add(3 * time.Second))
+	for _, tm := range []*model.Timer{a, b, c} {
+		w.Insert(tm)
+	}
+	if got := len(w.Held()); got != 3 {
+		t.Fatalf("Held: got %d want 3", got)
+	}
+	if removed := w.Remove(a.ID, c.ID, uuid.V4()); removed != 2 {
+		t.Fatalf("Remove: got %d want 2", removed)
+	}
+	if got := w.Len(); got != 1 {
+		t.Fatalf("Len after remove: got %d want 1", got)
+	}
+	fired := w.Advance(now.Add(5 * time.Second))
+	if len(fired) != 1 || fired[0].ID != b.ID {
+		t.Fatalf("advance: want only b, got %+v", fired)
+	}
+	// a removed timer can be inserted again, e.g. after a re-claim.
+	if w.Insert(newTimerWithID(a.ID, now.Add(10*time.Second))) != Inserted {
+		t.Fatal("expected re-insert of removed timer to succeed")
+	}
+}
+
+func newTimerWithID(id uuid.UUID, due time.Time) *model.Timer {
+	return &model.Timer{ID: id, DueUTC: due}
+}
+
+func TestAdvance_SubSecondResolution(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(64, now, OptResolution(100*time.Millisecond))
+
+	early := newTimer(now.Add(200 * time.Millisecond))
+	late := newTimer(now.Add(900 * time.Millisecond))
+	w.Insert(early)
+	w.Insert(late)
+
+	fired := w.Advance(now.Add(250 * time.Millisecond))
+	if len(fired) != 1 || fired[0].ID != early.ID {
+		t.Fatalf("advance to t+250ms: want only early, got %+v", fired)
+	}
+	if fired := w.Advance(now.Add(850 * time.Millisecond)); len(fired) != 0 {
+		t.Fatalf("advance to t+850ms fired the t+900ms timer: got %d, want 0", len(fired))
+	}
+	fired = w.Advance(now.Add(900 * time.Millisecond))
+	if len(fired) != 1 || fired[0].ID != late.ID {
+		t.Fatalf("advance to t+900ms: want late, got %+v", fired)
+	}
+	if got := w.CursorAt(); !got.Equal(now.Add(time.Second)) {
+		t.Fatalf("CursorAt: got %v want %v", got, now.Add(time.Second))
+	}
+}
+
+func TestAdvance_CascadesFromCoarseLevels(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00.5Z")
+	w := New(3*3600, now, OptResolution(10*time.Millisecond))
+
+	// one timer per level: the same second, a later second, a later
+	// minute and a later hour.
+	dues := []time.Duration{
+		300 * time.Millisecond,
+		4*time.Second + 20*time.Millisecond,
+		2*time.Minute + 3*time.Second + 40*time.Millisecond,
+		90*time.Minute + 50*time.Millisecond,
+	}
+	var timers []*model.Timer
+	for _, d := range dues {
+		tm := newTimer(now.Add(d))
+		if w.Insert(tm) != Inserted {
+			t.Fatalf("insert failed for +%v", d)
+		}
+		timers = append(timers, tm)
+	}
+
+	for index, d := range dues {
+		if fired := w.Advance(now.Add(d - 10*time.Millisecond)); len(fired) != 0 {
+			t.Fatalf("advance to just before +%v fired early: %+v", d, fired)
+		}
+		fired := w.Advance(now.Add(d))
+		if len(fired) != 1 || fired[0].ID != timers[index].ID {
+			t.Fatalf("advance to +%v: want timer %d, got %+v", d, index, fired)
+		}
+	}
+	if got := w.Len(); got != 0 {
+		t.Fatalf("Len after drain: got %d want 0", got)
+	}
+}
+
+func TestAdvance_LongPauseCatchesUp(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(600, now, OptResolution(time.Millisecond))
+
+	var timers []*model.Timer
+	for _, d := range []time.Duration{time.Second, 70 * time.Second, 9 * time.Minute} {
+		tm := newTimer(now.Add(d))
+		w.Insert(tm)
+		timers = append(timers, tm)
+	}
+	fired := w.Advance(now.Add(time.Hour))
+	if len(fired) != 3 {
+		t.Fatalf("got %d fired, want 3", len(fired))
+	}
+	for index := range timers {
+		if fired[index].ID != timers[index].ID {
+			t.Fatalf("fired out of order at %d", index)
+		}
+	}
+	if got := w.CursorAt(); !got.Equal(now.Add(time.Hour + time.Millisecond)) {
+		t.Fatalf("CursorAt: got %v want %v", got, now.Add(time.Hour+time.Millisecond))
+	}
+}
+
+func TestRemove_FromCoarseLevelKeepsOthers(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(600, now, OptResolution(10*time.Millisecond))
+
+	// all three share a minute slot, so removing the first moves the
+	// last into its place.
+	a := newTimer(now.Add(3 * time.Minute))
+	b := newTimer(now.Add(3*time.Minute + time.Second))
+	c := newTimer(now.Add(3*time.Minute + 2*time.Second))
+	for _, tm := range []*model.Timer{a, b, c} {
+		w.Insert(tm)
+	}
+	if removed := w.Remove(a.ID); removed != 1 {
+		t.Fatalf("Remove: got %d want 1", removed)
+	}
+	if removed := w.Remove(c.ID); removed != 1 {
+		t.Fatalf("Remove after swap: got %d want 1", removed)
+	}
+	fired := w.Advance(now.Add(5 * time.Minute))
+	if len(fired) != 1 || fired[0].ID != b.ID {
+		t.Fatalf("advance: want only b, got %+v", fired)
+	}
+}
+
+func TestNew_InvalidResolutionPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for a resolution that doesn't divide a second")
+		}
+	}()
+	New(8, time.Now(), OptResolution(300*time.Millisecond))
+}
+
+func TestInsert_OverBudgetRejected(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(64, now, OptMaxHeld(2))
+
+	a := newTimer(now.Add(time.Second))
+	if got := w.Insert(a); got != Inserted {
+		t.Fatalf("insert a: got %v want %v", got, Inserted)
+	}
+	if got := w.Occupancy(); got != 0.5 {
+		t.Fatalf("Occupancy: got %v want 0.5", got)
+	}
+	if got := w.Insert(newTimer(now.Add(2 * time.Minute))); got != Deferred {
+		t.Fatalf("insert beyond horizon: got %v want %v", got, Deferred)
+	}
+	if got := w.Free(); got != 0 {
+		t.Fatalf("Free: got %d want 0", got)
+	}
+	if got := w.Insert(newTimer(now.Add(2 * time.Second))); got != Rejected {
+		t.Fatalf("insert over budget: got %v want %v", got, Rejected)
+	}
+
+	w.Advance(now.Add(time.Second))
+	if got := w.Free(); got != 1 {
+		t.Fatalf("Free after firing a: got %d want 1", got)
+	}
+	if got := w.Insert(newTimer(now.Add(2 * time.Second))); got != Inserted {
+		t.Fatalf("insert after firing a: got %v want %v", got, Inserted)
+	}
+}
+
+func TestInsert_OverByteBudgetRejected(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:00Z")
+	w := New(64, now, OptMaxHeldBytes(4096))
+
+	small := newTimer(now.Add(time.Second))
+	if got := w.Insert(small); got != Inserted {
+		t.Fatalf("insert small: got %v want %v", got, Inserted)
+	}
+	large := newTimer(now.Add(2 * time.Second))
+	large.HookBody = make([]byte, 4096)
+	if got := w.Insert(large); got != Rejected {
+		t.Fatalf("insert large: got %v want %v", got, Rejected)
+	}
+	if got := w.HeldBytes(); got != timerSize(small) {
+		t.Fatalf("HeldBytes: got %d want %d", got, timerSize(small))
+	}
+	if got, want := w.Free(), int((4096-timerSize(small))/timerSize(small)); got != want {
+		t.Fatalf("Free: got %d want %d", got, want)
+	}
+
+	w.Remove(small.ID)
+	if got := w.HeldBytes(); got != 0 {
+		t.Fatalf("HeldBytes after remove: got %d want 0", got)
+	}
+	if got := w.Occupancy(); got != 0 {
+		t.Fatalf("Occupancy after remove: got %v want 0", got)
+	}
+}
+
+func TestUpcoming_AcrossLevels(t *testing.T) {
+	now := mustTime(t, "2026-04-25T12:00:50Z")
+	w := New(600, now, OptResolution(100*time.Millisecond))
+
+	soon := newTimer(now.Add(2 * time.Second))
+	// crosses the minute boundary, so it's still in the minute level.
+	nextMinute := newTimer(now.Add(12 * time.Second))
+	later := newTimer(now.Add(5 * time.Minute))
+	for _, tm := range []*model.Timer{soon, nextMinute, later} {
+		w.Insert(tm)
+	}
+	upcoming := w.Upcoming(15 * time.Second)
+	if len(upcoming) != 2 {
+		t.Fatalf("Upcoming: got %d want 2", len(upcoming))
+	}
+	for _, tm := range upcoming {
+		if tm.ID == later.ID {
+			t.Fatal("Upcoming returned a timer outside the window")
+		}
+	}
+	if got := w.Upcoming(time.Second); len(got) != 0 {
+		t.Fatalf("Upcoming within 1s: got %d want 0", len(got))
+	}
+}
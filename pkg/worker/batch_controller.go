@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// batchController tunes the claim batch size from what the worker
+// observes, additive-increase / multiplicative-decrease style.
+//
+// A tick "struggles" if any of these hold, and the batch is cut by
+// batchDecreaseFactor:
+//
+//   - draining the batch at the hook p95 with the configured parallelism
+//     would take longer than the tick budget,
+//   - every parallelism slot was busy and the tick ran past
+//     saturatedBudgetFraction of its budget,
+//   - the claim query alone took more than claimBudgetFraction of the
+//     budget (the database is the bottleneck, not the hooks),
+//   - the wheel holds more than it can drain over the prefetch window.
+//
+// Otherwise, if the last claim came back full (there's more work than
+// we're taking) the batch grows by a fixed step. A claim that comes back
+// short leaves it alone; there's nothing to gain from growing it.
+type batchController struct {
+	min, max    int
+	step        float64
+	parallelism int
+	budget      time.Duration
+
+	mu      sync.Mutex
+	current float64
+	hooks   latencyWindow
+}
+
+const (
+	batchDecreaseFactor     = 0.5
+	claimBudgetFraction     = 0.25
+	saturatedBudgetFraction = 0.8
+	hookLatencySamples      = 512
+)
+
+func newBatchController(initial, minSize, maxSize, parallelism int, budget time.Duration) *batchController {
+	minSize = max(minSize, 1)
+	maxSize = max(maxSize, minSize)
+	return &batchController{
+		min:         minSize,
+		max:         maxSize,
+		step:        math.Max(1, float64(maxSize-minSize)/32),
+		parallelism: max(parallelism, 1),
+		budget:      budget,
+		current:     float64(min(max(initial, minSize), maxSize)),
+		hooks:       latencyWindow{samples: make([]time.Duration, 0, hookLatencySamples)},
+	}
+}
+
+// tickObservation is what a claim tick reports back to the controller.
+type tickObservation struct {
+	// Requested is the batch size asked for; Claimed is how many rows
+	// came back.
+	Requested, Claimed int
+	ClaimLatency       time.Duration
+	// Elapsed is how long firing the claimed timers took, in legacy
+	// mode. Zero in wheel mode where firing is decoupled from claiming.
+	Elapsed time.Duration
+	// PeakInFlight is the most hooks in flight at once since the last
+	// observation.
+	PeakInFlight int
+	// WheelLen and Window describe the wheel after a prefetch, in wheel
+	// mode.
+	WheelLen int
+	Window   time.Duration
+}
+
+// Size returns the current batch size.
+func (bc *batchController) Size() int {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	return int(bc.current)
+}
+
+// ObserveHook records how long a hook request took.
+func (bc *batchController) ObserveHook(elapsed time.Duration) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.hooks.add(elapsed)
+}
+
+// Observe adjusts the batch size after a tick and returns the new size.
+func (bc *batchController) Observe(obs tickObservation) int {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	if bc.struggling(obs) {
+		bc.current = math.Max(float64(bc.min), math.Floor(bc.current*batchDecreaseFactor))
+	} else if obs.Requested > 0 && obs.Claimed >= obs.Requested {
+		bc.current = math.Min(float64(bc.max), bc.current+bc.step)
+	}
+	return int(bc.current)
+}
+
+func (bc *batchController) struggling(obs tickObservation) bool {
+	if bc.budget <= 0 {
+		return false
+	}
+	if obs.ClaimLatency > time.Duration(float64(bc.budget)*claimBudgetFraction) {
+		return true
+	}
+	if obs.PeakInFlight >= bc.parallelism && obs.Elapsed > time.Duration(float64(bc.budget)*saturatedBudgetFraction) {
+		return true
+	}
+	p95 := bc.hooks.percentile(0.95)
+	if p95 <= 0 {
+		return false
+	}
+	waves := math.Ceil(bc.current / float64(bc.parallelism))
+	if time.Duration(waves)*p95 > bc.budget {
+		return true
+	}
+	if obs.Window > 0 {
+		drainable := float64(bc.parallelism) * float64(obs.Window) / float64(p95)
+		if float64(obs.WheelLen) > drainable {
+			return true
+		}
+	}
+	return false
+}
+
+// latencyWindow keeps the most recent samples in a ring.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+func (lw *latencyWindow) add(d time.Duration) {
+	if len(lw.samples) < cap(lw.samples) {
+		lw.samples = append(lw.samples, d)
+		return
+	}
+	lw.samples[lw.next] = d
+	lw.next = (lw.next + 1) % len(lw.samples)
+}
+
+func (lw *latencyWindow) percentile(p float64) time.Duration {
+	if len(lw.samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(lw.samples)
+	slices.Sort(sorted)
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(index, 0), len(sorted)-1)]
+}
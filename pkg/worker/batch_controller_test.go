@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBatchController_growsWhileClaimsAreFull(t *testing.T) {
+	bc := newBatchController(100, 10, 420, 10, 5*time.Second)
+	for range 50 {
+		bc.ObserveHook(10 * time.Millisecond)
+	}
+	size := bc.Size()
+	next := bc.Observe(tickObservation{Requested: size, Claimed: size})
+	if next <= size {
+		t.Fatalf("expected growth from %d, got %d", size, next)
+	}
+	// a short claim means there's no more work; hold steady.
+	held := bc.Observe(tickObservation{Requested: next, Claimed: next / 2})
+	if held != next {
+		t.Fatalf("expected %d to hold, got %d", next, held)
+	}
+	for range 1000 {
+		size = bc.Size()
+		bc.Observe(tickObservation{Requested: size, Claimed: size})
+	}
+	if got := bc.Size(); got != 420 {
+		t.Fatalf("expected growth to stop at max 420, got %d", got)
+	}
+}
+
+func TestBatchController_backsOffOnSlowHooks(t *testing.T) {
+	bc := newBatchController(400, 10, 1000, 10, 5*time.Second)
+	// 40 waves of 500ms is well past the 5s budget.
+	for range 50 {
+		bc.ObserveHook(500 * time.Millisecond)
+	}
+	if got := bc.Observe(tickObservation{Requested: 400, Claimed: 400}); got != 200 {
+		t.Fatalf("expected halving to 200, got %d", got)
+	}
+	for range 10 {
+		bc.Observe(tickObservation{Requested: bc.Size(), Claimed: bc.Size()})
+	}
+	// 10 waves of 500ms fits; the batch settles rather than floors.
+	if got := bc.Size(); got < 10 || got > 100 {
+		t.Fatalf("expected a size in [10, 100], got %d", got)
+	}
+}
+
+func TestBatchController_backsOffOnSlowClaims(t *testing.T) {
+	bc := newBatchController(400, 10, 1000, 255, 5*time.Second)
+	if got := bc.Observe(tickObservation{Requested: 400, Claimed: 400, ClaimLatency: 2 * time.Second}); got != 200 {
+		t.Fatalf("expected halving to 200, got %d", got)
+	}
+}
+
+func TestBatchController_backsOffOnSaturation(t *testing.T) {
+	bc := newBatchController(400, 10, 1000, 8, 5*time.Second)
+	got := bc.Observe(tickObservation{Requested: 400, Claimed: 400, PeakInFlight: 8, Elapsed: 4500 * time.Millisecond})
+	if got != 200 {
+		t.Fatalf("expected halving to 200, got %d", got)
+	}
+}
+
+func TestBatchController_backsOffOnWheelOccupancy(t *testing.T) {
+	bc := newBatchController(20, 10, 1000, 4, 5*time.Second)
+	for range 50 {
+		bc.ObserveHook(100 * time.Millisecond)
+	}
+	// 4 slots at 100ms drain 1200 timers over 30s.
+	if got := bc.Observe(tickObservation{Requested: 20, Claimed: 20, WheelLen: 1000, Window: 30 * time.Second}); got <= 20 {
+		t.Fatalf("expected growth under capacity, got %d", got)
+	}
+	size := bc.Size()
+	if got := bc.Observe(tickObservation{Requested: size, Claimed: size, WheelLen: 5000, Window: 30 * time.Second}); got >= size {
+		t.Fatalf("expected back off from %d over capacity, got %d", size, got)
+	}
+}
@@ -0,0 +1,23 @@
+package worker
+
+// Error is a hard alias to string.
+type Error string
+
+// Error implements `error`
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	// ErrDraining is returned by Ready once a drain has started.
+	ErrDraining Error = "worker; draining"
+	// ErrNotSeen is returned by Ready until the worker has reported in
+	// to the workers table for the first time.
+	ErrNotSeen Error = "worker; has not reported in yet"
+	// ErrSeenStale is returned by Ready when the worker's last successful
+	// report to the workers table is too old for peers to count it.
+	ErrSeenStale Error = "worker; last report is stale"
+	// ErrClaimLoopStalled is returned by Alive when the claim loop hasn't
+	// started a tick in several intervals.
+	ErrClaimLoopStalled Error = "worker; claim loop stalled"
+)
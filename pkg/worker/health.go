@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sandman/pkg/log"
+	"sandman/pkg/wheel"
+)
+
+// readyStalenessTicks is how many tick intervals may pass since the last
+// successful WorkerSeen before the worker reports not ready. Kept well
+// under shardStaleness so the probe fails before peers drop the worker
+// from the ring.
+const readyStalenessTicks = 3
+
+// aliveStalenessTicks is how many tick intervals may pass without the
+// claim loop starting a tick before the worker reports not alive.
+const aliveStalenessTicks = 4
+
+const defaultDrainTimeout = 20 * time.Second
+
+func (w *Worker) drainTimeoutOrDefault() time.Duration {
+	if w.drainTimeout > 0 {
+		return w.drainTimeout
+	}
+	return defaultDrainTimeout
+}
+
+// Drain asks the worker to stop claiming and hand its work back. In
+// wheel mode prefetching stops, dispatch gets up to the drain timeout
+// to empty the wheel, whatever is left is relinquished, and the worker
+// deregisters so peers take over its shards. In the legacy mode the
+// in-flight tick finishes and the worker deregisters. Run keeps
+// returning only once its context is cancelled. Drain doesn't block and
+// is safe to call more than once.
+func (w *Worker) Drain() {
+	w.drainOnce.Do(func() { close(w.draining) })
+}
+
+// Drained returns a channel closed once a drain has finished.
+func (w *Worker) Drained() <-chan struct{} {
+	return w.drained
+}
+
+func (w *Worker) isDraining() bool {
+	select {
+	case <-w.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// Ready reports an error unless the worker is claiming work: it isn't
+// draining and has recently reported in to the workers table, which
+// also implies the DB is reachable.
+func (w *Worker) Ready(_ context.Context) error {
+	if w.isDraining() {
+		return ErrDraining
+	}
+	lastSeen := w.lastSeen.Load()
+	if lastSeen == 0 {
+		return ErrNotSeen
+	}
+	if since := w.clock.Since(time.Unix(0, lastSeen)); since > readyStalenessTicks*w.tickIntervalOrDefault() {
+		return fmt.Errorf("%w; %v ago", ErrSeenStale, since.Round(time.Second))
+	}
+	return nil
+}
+
+// Alive reports an error if the claim loop has stopped starting ticks.
+// A DB outage doesn't fail it (ticks still start and fail fast), so a
+// restart is only triggered for a wedged process. A drained worker is
+// always alive.
+func (w *Worker) Alive(_ context.Context) error {
+	if w.isDraining() {
+		return nil
+	}
+	lastTick := w.lastTick.Load()
+	if lastTick == 0 {
+		return nil
+	}
+	if since := w.clock.Since(time.Unix(0, lastTick)); since > aliveStalenessTicks*w.tickIntervalOrDefault() {
+		return fmt.Errorf("%w; %v ago", ErrClaimLoopStalled, since.Round(time.Second))
+	}
+	return nil
+}
+
+// markTick records the claim loop starting a tick.
+func (w *Worker) markTick() {
+	w.lastTick.Store(w.clock.Now().UnixNano())
+}
+
+// markSeen records a successful WorkerSeen.
+func (w *Worker) markSeen(ts time.Time) {
+	w.lastSeen.Store(ts.UnixNano())
+}
+
+// drainWheel runs once prefetching has stopped. Dispatch keeps firing
+// until the wheel is empty or the drain timeout passes; anything left
+// is relinquished so peers can claim it immediately, then the worker
+// deregisters.
+func (w *Worker) drainWheel(ctx context.Context, wh *wheel.Wheel) {
+	logger := log.GetLogger(ctx)
+	logger.Info("worker; draining wheel", log.Int("wheel_len", wh.Len()))
+	deadline := w.clock.NewTimer(w.drainTimeoutOrDefault())
+	defer deadline.Stop()
+	poll := w.clock.NewTicker(w.dispatchTickIntervalOrDefault())
+	defer poll.Stop()
+wait:
+	for wh.Len() > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C():
+			break wait
+		case <-poll.C():
+		}
+	}
+	w.shutdownWheel(wh)
+	w.deregister(ctx)
+	close(w.drained)
+	logger.Info("worker; drained")
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sandman/pkg/clock"
+)
+
+func TestWorker_Ready(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	w := New("worker-00", nil, OptPollingInterval(time.Second), OptClock(clk))
+	if err := w.Ready(context.Background()); !errors.Is(err, ErrNotSeen) {
+		t.Fatalf("expected ErrNotSeen before the first report, got %v", err)
+	}
+	w.markSeen(clk.Now())
+	if err := w.Ready(context.Background()); err != nil {
+		t.Fatalf("expected ready after a report, got %v", err)
+	}
+	clk.Advance(readyStalenessTicks * time.Second)
+	if err := w.Ready(context.Background()); err != nil {
+		t.Fatalf("expected ready at the staleness limit, got %v", err)
+	}
+	clk.Advance(time.Nanosecond)
+	if err := w.Ready(context.Background()); !errors.Is(err, ErrSeenStale) {
+		t.Fatalf("expected ErrSeenStale, got %v", err)
+	}
+	w.markSeen(clk.Now())
+	w.Drain()
+	w.Drain()
+	if err := w.Ready(context.Background()); !errors.Is(err, ErrDraining) {
+		t.Fatalf("expected ErrDraining, got %v", err)
+	}
+}
+
+func TestWorker_Alive(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	w := New("worker-00", nil, OptPollingInterval(time.Second), OptClock(clk))
+	if err := w.Alive(context.Background()); err != nil {
+		t.Fatalf("expected alive before the first tick, got %v", err)
+	}
+	w.markTick()
+	clk.Advance(aliveStalenessTicks * time.Second)
+	if err := w.Alive(context.Background()); err != nil {
+		t.Fatalf("expected alive at the staleness limit, got %v", err)
+	}
+	clk.Advance(time.Nanosecond)
+	if err := w.Alive(context.Background()); !errors.Is(err, ErrClaimLoopStalled) {
+		t.Fatalf("expected ErrClaimLoopStalled, got %v", err)
+	}
+	w.markTick()
+	if err := w.Alive(context.Background()); err != nil {
+		t.Fatalf("expected alive again after a tick, got %v", err)
+	}
+	clk.Advance(time.Minute)
+	// a drained worker stops ticking on purpose.
+	w.Drain()
+	if err := w.Alive(context.Background()); err != nil {
+		t.Fatalf("expected a draining worker to stay alive, got %v", err)
+	}
+}
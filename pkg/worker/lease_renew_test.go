@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"sandman/pkg/clock"
+	"sandman/pkg/model"
+	"sandman/pkg/utils"
+	"sandman/pkg/uuid"
+	"sandman/pkg/wheel"
+)
+
+func TestLeasesLapsingBefore(t *testing.T) {
+	now := time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)
+	deadline := now.Add(10 * time.Second)
+
+	claimedLong := &model.Timer{ID: uuid.V4(), AssignedUntilUTC: utils.Ref(now.Add(time.Minute))}
+	claimedShort := &model.Timer{ID: uuid.V4(), AssignedUntilUTC: utils.Ref(now.Add(5 * time.Second))}
+	renewedLong := &model.Timer{ID: uuid.V4(), AssignedUntilUTC: utils.Ref(now.Add(-time.Minute))}
+	renewedShort := &model.Timer{ID: uuid.V4(), AssignedUntilUTC: utils.Ref(now.Add(time.Hour))}
+	unleased := &model.Timer{ID: uuid.V4()}
+
+	leasedUntil := map[uuid.UUID]time.Time{
+		renewedLong.ID:  now.Add(time.Minute),
+		renewedShort.ID: now.Add(time.Second),
+	}
+	got := leasesLapsingBefore([]*model.Timer{claimedLong, claimedShort, renewedLong, renewedShort, unleased}, leasedUntil, deadline)
+	want := []uuid.UUID{claimedShort.ID, renewedShort.ID, unleased.ID}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %v to lapse, got %v", want, got)
+	}
+}
+
+func TestWorker_leaseRenewLoop_renewsAndEvictsLost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	now := clk.Now()
+	store := model.NewMemoryStore()
+	w := New("worker-00", store,
+		OptClock(clk),
+		OptPrefetchWindow(10*time.Second),
+		OptLeaseRenewInterval(5*time.Second),
+	)
+	for _, name := range []string{"kept", "stolen"} {
+		timer := model.Timer{Name: name, DueUTC: now.Add(8 * time.Second), HookURL: "http://localhost/hook"}
+		if err := store.CreateTimer(ctx, &timer); err != nil {
+			t.Fatal(err)
+		}
+	}
+	claimed, err := store.GetDueTimersWindowed(ctx, "worker-00", now, 10, model.AllShards(), 10, 40)
+	if err != nil || len(claimed) != 2 {
+		t.Fatalf("expected both timers claimed, got %d (%v)", len(claimed), err)
+	}
+	wh := wheel.New(30, now)
+	var kept, stolen model.Timer
+	for _, timer := range claimed {
+		wh.Insert(&timer)
+		if timer.Name == "kept" {
+			kept = timer
+		} else {
+			stolen = timer
+		}
+	}
+	// the claim on stolen is dropped behind the worker's back.
+	if err := store.BulkRelinquish(ctx, "worker-00", now, []model.TimerLease{stolen.Lease()}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.leaseRenewLoop(ctx, wh)
+	}()
+	waitUntil(t, func() bool { return clk.Waiters() == 1 })
+	clk.Advance(5 * time.Second)
+	waitUntil(t, func() bool { return wh.Len() == 1 })
+	cancel()
+	<-done
+
+	if held := wh.Held(); held[0].ID != kept.ID {
+		t.Fatalf("expected kept to stay in the wheel, got %q", held[0].Name)
+	}
+	if evicted := w.timersLeaseEvicted.Value(); evicted != 1 {
+		t.Fatalf("expected 1 eviction, got %d", evicted)
+	}
+	renewed, _, err := store.GetTimerByID(context.Background(), kept.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(5*time.Second + 10*time.Second + wheelLeaseSafetyMargin); !renewed.AssignedUntilUTC.Equal(want) {
+		t.Fatalf("expected the lease renewed to %v, got %v", want, renewed.AssignedUntilUTC)
+	}
+}
+
+// failingRenewStore is a store whose lease renewals always fail.
+type failingRenewStore struct {
+	model.TimerStore
+}
+
+func (failingRenewStore) RenewLeases(context.Context, string, time.Time, []model.TimerLease) ([]uuid.UUID, error) {
+	return nil, errors.New("renew failed")
+}
+
+func TestWorker_leaseRenewLoop_evictsLapsingOnFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	now := clk.Now()
+	w := New("worker-00", failingRenewStore{model.NewMemoryStore()},
+		OptClock(clk),
+		OptLeaseRenewInterval(5*time.Second),
+	)
+	wh := wheel.New(30, now)
+	lapsing := &model.Timer{ID: uuid.V4(), DueUTC: now.Add(20 * time.Second), AssignedUntilUTC: utils.Ref(now.Add(8 * time.Second))}
+	covered := &model.Timer{ID: uuid.V4(), DueUTC: now.Add(20 * time.Second), AssignedUntilUTC: utils.Ref(now.Add(time.Minute))}
+	wh.Insert(lapsing)
+	wh.Insert(covered)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.leaseRenewLoop(ctx, wh)
+	}()
+	waitUntil(t, func() bool { return clk.Waiters() == 1 })
+	// at +5s the next attempt is at +10s, after lapsing's lease ends.
+	clk.Advance(5 * time.Second)
+	waitUntil(t, func() bool { return wh.Len() == 1 })
+	cancel()
+	<-done
+
+	if held := wh.Held(); held[0].ID != covered.ID {
+		t.Fatalf("expected only the timer whose lease outlasts the next attempt to stay")
+	}
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"net/http"
+	"strconv"
+
+	"sandman/pkg/metrics"
+)
+
+// Prometheus metrics, served on /metrics when metrics are enabled. They
+// are process wide, unlike the per-worker expvar counters in WorkerVars,
+// except the wheel gauges, which are labeled by worker since several
+// workers can share a process.
+var (
+	metricDeliveryLateness = metrics.NewHistogram(
+		"sandman_worker_delivery_lateness_seconds",
+		"Seconds between a timer's due_utc and its hook being fired.",
+		metrics.LatenessBuckets,
+	)
+	metricHookDuration = metrics.NewHistogram(
+		"sandman_worker_hook_duration_seconds",
+		"Hook request latency in seconds.",
+		metrics.DefaultBuckets,
+	)
+	metricHookResponses = metrics.NewCounterVec(
+		"sandman_worker_hook_responses_total",
+		"Hook responses by status code; code is \"error\" for requests that got no response.",
+		"code",
+	)
+	metricClaimDuration = metrics.NewHistogram(
+		"sandman_worker_claim_duration_seconds",
+		"Claim query latency in seconds.",
+		metrics.DefaultBuckets,
+	)
+	metricWheelLength = metrics.NewGaugeVec(
+		"sandman_worker_wheel_length",
+		"Timers held in the wheel, by worker.",
+		"worker",
+	)
+	metricWheelOverflowLength = metrics.NewGaugeVec(
+		"sandman_worker_wheel_overflow_length",
+		"Timers held in the wheel's overflow heap, due beyond its horizon, by worker.",
+		"worker",
+	)
+	metricWheelOccupancy = metrics.NewGaugeVec(
+		"sandman_worker_wheel_occupancy",
+		"How full the wheel is against its held timers and bytes budgets, from 0 to 1, by worker.",
+		"worker",
+	)
+	metricPrewarms = metrics.NewCounterVec(
+		"sandman_worker_prewarms_total",
+		"Connections opened ahead of time to the hosts of upcoming timers, by result (ok, error).",
+		"result",
+	)
+	metricWakeups = metrics.NewCounterVec(
+		"sandman_worker_wakeups_total",
+		"Wakeups received for timers due before the next poll, by outcome (accepted, ignored, dropped).",
+		"outcome",
+	)
+	metricFlushBatchSize = metrics.NewHistogramVec(
+		"sandman_worker_flush_batch_size",
+		"Timers per completion write, by kind (delivered, expired, attempted).",
+		metrics.SizeBuckets,
+		"kind",
+	)
+)
+
+// hookResponseCode is the `code` label for a hook response.
+func hookResponseCode(res *http.Response, err error) string {
+	if err != nil || res == nil {
+		return "error"
+	}
+	return strconv.Itoa(res.StatusCode)
+}
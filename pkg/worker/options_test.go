@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"sandman/pkg/config"
+)
+
+func TestOptConfig(t *testing.T) {
+	w := New("worker-00", nil, OptConfig(config.WorkerConfig{}))
+	if w.batchSize != config.DefaultWorkerBatchSize || w.pollingInterval != config.DefaultWorkerPollingInterval {
+		t.Fatalf("expected the config defaults, got batch size %d and polling interval %v", w.batchSize, w.pollingInterval)
+	}
+	if w.prefetchWindow != 0 || w.batches != nil || w.dnsCache != nil {
+		t.Fatalf("expected zero values to leave wheel mode, adaptive batches and the dns cache off")
+	}
+
+	w = New("worker-00", nil, OptConfig(config.WorkerConfig{
+		BatchSize:         64,
+		PollingInterval:   time.Second,
+		PrefetchWindow:    30 * time.Second,
+		MaxBatchSize:      512,
+		DNSCacheTTL:       time.Minute,
+		CapacityWeight:    2,
+		HookSigningSecret: "secret",
+	}))
+	if w.batchSize != 64 || w.pollingInterval != time.Second || w.prefetchWindow != 30*time.Second {
+		t.Fatalf("expected the configured batch size, polling interval and prefetch window")
+	}
+	if w.batches == nil || w.dnsCache == nil || w.capacityWeight != 2 || string(w.hookSigningSecret) != "secret" {
+		t.Fatalf("expected adaptive batches, the dns cache, the capacity weight and the signing secret to be set")
+	}
+}
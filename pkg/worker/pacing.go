@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"sandman/pkg/async"
+	"sandman/pkg/model"
+	"sandman/pkg/wheel"
+)
+
+// pacedTimer is a fired timer and when paced dispatch sends it.
+type pacedTimer struct {
+	Timer  *model.Timer
+	FireAt time.Time
+}
+
+// pace spreads fired evenly across the interval from start, highest
+// priority (then earliest due) first. No timer is scheduled before its
+// due_utc, or more than tolerance after it unless it was already that
+// late at start. The schedule is returned in FireAt order.
+func pace(fired []*model.Timer, start time.Time, interval, tolerance time.Duration) []pacedTimer {
+	ordered := slices.Clone(fired)
+	slices.SortStableFunc(ordered, func(a, b *model.Timer) int {
+		if c := cmp.Compare(b.Priority, a.Priority); c != 0 {
+			return c
+		}
+		return a.DueUTC.Compare(b.DueUTC)
+	})
+	output := make([]pacedTimer, len(ordered))
+	for index, t := range ordered {
+		fireAt := start.Add(interval * time.Duration(index) / time.Duration(len(ordered)))
+		if fireAt.Before(t.DueUTC) {
+			fireAt = t.DueUTC
+		}
+		if latest := t.DueUTC.Add(tolerance); fireAt.After(latest) {
+			fireAt = latest
+		}
+		if fireAt.Before(start) {
+			fireAt = start
+		}
+		output[index] = pacedTimer{Timer: t, FireAt: fireAt}
+	}
+	slices.SortStableFunc(output, func(a, b pacedTimer) int {
+		return a.FireAt.Compare(b.FireAt)
+	})
+	return output
+}
+
+// dispatchPaced fires a slot's timers on the pace schedule rather than
+// in one burst, still bounded by parallelism. If ctx ends part way the
+// timers not yet fired go back into the wheel so the shutdown
+// relinquishes them.
+func (w *Worker) dispatchPaced(ctx context.Context, wh *wheel.Wheel, fired []*model.Timer, results chan<- dispatchResult) {
+	schedule := pace(fired, w.clock.Now().UTC(), w.dispatchTickIntervalOrDefault(), w.pacingTolerance)
+	b, _ := async.BatchContext(ctx)
+	b.SetLimit(w.parallelismOrDefault())
+	wait := w.clock.NewTimer(0)
+	defer wait.Stop()
+	for index, p := range schedule {
+		wait.Reset(p.FireAt.Sub(w.clock.Now()))
+		select {
+		case <-ctx.Done():
+			for _, rest := range schedule[index:] {
+				wh.Insert(rest.Timer)
+			}
+			_ = b.Wait()
+			return
+		case <-wait.C():
+		}
+		t := p.Timer
+		b.Go(func() error {
+			w.fireOne(ctx, t, results)
+			return nil
+		})
+	}
+	_ = b.Wait()
+}
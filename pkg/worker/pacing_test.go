@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"sandman/pkg/clock"
+	"sandman/pkg/model"
+	"sandman/pkg/uuid"
+	"sandman/pkg/wheel"
+)
+
+func TestPace_spreadsByPriority(t *testing.T) {
+	start := time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)
+	low := &model.Timer{ID: uuid.V4(), DueUTC: start.Add(-time.Second)}
+	high := &model.Timer{ID: uuid.V4(), DueUTC: start.Add(-time.Second), Priority: 10}
+	mid := &model.Timer{ID: uuid.V4(), DueUTC: start.Add(-time.Second), Priority: 5}
+	late := &model.Timer{ID: uuid.V4(), DueUTC: start.Add(-time.Second)}
+
+	schedule := pace([]*model.Timer{low, high, mid, late}, start, time.Second, 2*time.Second)
+	want := []struct {
+		id     uuid.UUID
+		offset time.Duration
+	}{
+		{high.ID, 0},
+		{mid.ID, 250 * time.Millisecond},
+		{low.ID, 500 * time.Millisecond},
+		{late.ID, 750 * time.Millisecond},
+	}
+	if len(schedule) != len(want) {
+		t.Fatalf("expected %d scheduled, got %d", len(want), len(schedule))
+	}
+	for index, w := range want {
+		if schedule[index].Timer.ID != w.id || !schedule[index].FireAt.Equal(start.Add(w.offset)) {
+			t.Fatalf("at %d: expected %v at +%v, got %v at %v", index, w.id, w.offset, schedule[index].Timer.ID, schedule[index].FireAt)
+		}
+	}
+}
+
+func TestPace_neverEarlyOrPastTolerance(t *testing.T) {
+	start := time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)
+	var fired []*model.Timer
+	for index := range 10 {
+		// all due in the first tenth of the tick, most of them low
+		// priority so even spreading would push them late.
+		fired = append(fired, &model.Timer{
+			ID:       uuid.V4(),
+			DueUTC:   start.Add(time.Duration(index) * 10 * time.Millisecond),
+			Priority: uint32(10 - index),
+		})
+	}
+	tolerance := 200 * time.Millisecond
+	schedule := pace(fired, start, time.Second, tolerance)
+	for index, p := range schedule {
+		if p.FireAt.Before(p.Timer.DueUTC) {
+			t.Fatalf("%v scheduled %v before its due_utc", p.Timer.ID, p.Timer.DueUTC.Sub(p.FireAt))
+		}
+		if late := p.FireAt.Sub(p.Timer.DueUTC); late > tolerance {
+			t.Fatalf("%v scheduled %v late, past the %v tolerance", p.Timer.ID, late, tolerance)
+		}
+		if index > 0 && p.FireAt.Before(schedule[index-1].FireAt) {
+			t.Fatalf("schedule out of order at %d", index)
+		}
+	}
+}
+
+func TestPace_overdueFiresAtStart(t *testing.T) {
+	start := time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC)
+	overdue := &model.Timer{ID: uuid.V4(), DueUTC: start.Add(-time.Minute)}
+	schedule := pace([]*model.Timer{{ID: uuid.V4(), DueUTC: start, Priority: 1}, overdue}, start, time.Second, 0)
+	if schedule[1].Timer.ID != overdue.ID || !schedule[1].FireAt.Equal(start) {
+		t.Fatalf("expected the overdue timer at start, got %v at %v", schedule[1].Timer.ID, schedule[1].FireAt)
+	}
+}
+
+func TestDispatchPaced_firesOnSchedule(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	start := clk.Now()
+	w := New("worker-00", nil, OptClock(clk), OptPacedDispatch(time.Second))
+	var fired []*model.Timer
+	for range 4 {
+		fired = append(fired, &model.Timer{ID: uuid.V4(), DueUTC: start, HookURL: srv.URL})
+	}
+	results := make(chan dispatchResult, len(fired))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.dispatchPaced(context.Background(), wheel.New(8, start), fired, results)
+	}()
+
+	for index := range fired {
+		if index > 0 {
+			// let dispatch start waiting on the next fire before moving
+			// the clock to it.
+			waitUntil(t, func() bool { return clk.Waiters() == 1 })
+			clk.Advance(250 * time.Millisecond)
+		}
+		r := <-results
+		if want := start.Add(time.Duration(index) * 250 * time.Millisecond); !r.FiredAt.Equal(want) {
+			t.Fatalf("fire %d: expected at %v, got %v", index, want, r.FiredAt)
+		}
+	}
+	<-done
+}
+
+func waitUntil(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"sandman/pkg/async"
+	"sandman/pkg/wheel"
+)
+
+const defaultPrewarmMaxHosts = 16
+
+func (w *Worker) prewarmMaxHostsOrDefault() int {
+	if w.prewarmMaxHosts > 0 {
+		return w.prewarmMaxHosts
+	}
+	return defaultPrewarmMaxHosts
+}
+
+// prewarmRefresh is how long prewarm leaves a host alone after warming
+// it; the transport keeps the connection idle in between, and the hooks
+// fired on it keep it that way.
+const prewarmRefresh = 30 * time.Second
+
+// prewarmLoop opens connections to the hosts of timers about to fire so
+// the DNS, TCP and TLS setup happens ahead of the due time rather than
+// adding to the delivery lateness.
+func (w *Worker) prewarmLoop(ctx context.Context, wh *wheel.Wheel) {
+	tick := w.clock.NewTicker(w.dispatchTickIntervalOrDefault())
+	defer tick.Stop()
+	warmedAt := make(map[string]time.Time)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C():
+			w.prewarm(ctx, wh, warmedAt)
+		}
+	}
+}
+
+// prewarm warms the origins of the timers due within the prewarm lead
+// that haven't been warmed recently, at most prewarmMaxHosts at a time.
+func (w *Worker) prewarm(ctx context.Context, wh *wheel.Wheel, warmedAt map[string]time.Time) {
+	now := w.clock.Now()
+	for origin, at := range warmedAt {
+		if now.Sub(at) >= prewarmRefresh {
+			delete(warmedAt, origin)
+		}
+	}
+	maxHosts := w.prewarmMaxHostsOrDefault()
+	var origins []string
+	for _, t := range wh.Upcoming(w.prewarmLead) {
+		origin, ok := hookOrigin(t.HookURL)
+		if !ok {
+			continue
+		}
+		if _, ok := warmedAt[origin]; ok {
+			continue
+		}
+		warmedAt[origin] = now
+		origins = append(origins, origin)
+		if len(origins) == maxHosts {
+			break
+		}
+	}
+	if len(origins) == 0 {
+		return
+	}
+	b, _ := async.BatchContext(ctx)
+	for _, origin := range origins {
+		b.Go(func() error {
+			result := "ok"
+			if err := w.warmOrigin(ctx, origin); err != nil {
+				result = "error"
+			}
+			metricPrewarms.WithLabelValues(result).Inc()
+			return nil
+		})
+	}
+	_ = b.Wait()
+}
+
+// warmOrigin sends `OPTIONS *` to origin through the hook transport so
+// it dials (and for https, handshakes) a connection it then keeps idle
+// for the hook requests that follow. The response itself is ignored.
+func (w *Worker) warmOrigin(ctx context.Context, origin string) error {
+	ctx, cancel := context.WithTimeout(ctx, w.hookTimeoutOrDefault())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, origin, nil)
+	if err != nil {
+		return err
+	}
+	req.URL.Opaque = "*"
+	res, err := w.http.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return res.Body.Close()
+}
+
+// hookOrigin returns the scheme and host:port a hook url is sent to,
+// the unit the transport pools connections by.
+func hookOrigin(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+	return u.Scheme + "://" + u.Host, true
+}
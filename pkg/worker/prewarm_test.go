@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"sandman/pkg/model"
+	"sandman/pkg/uuid"
+	"sandman/pkg/wheel"
+)
+
+func TestPrewarm_reusesWarmedConnection(t *testing.T) {
+	var mu sync.Mutex
+	var conns int
+	var methods []string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		methods = append(methods, req.Method+" "+req.RequestURI)
+		mu.Unlock()
+		rw.WriteHeader(http.StatusOK)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	w := New("worker-00", nil, OptPrewarm(10*time.Second, 4), OptDNSCacheTTL(time.Minute))
+	now := time.Now().UTC()
+	wh := wheel.New(60, now)
+	later := wheel.New(60, now)
+	for range 3 {
+		wh.Insert(&model.Timer{ID: uuid.V4(), DueUTC: now.Add(5 * time.Second), HookURL: srv.URL + "/hook"})
+		later.Insert(&model.Timer{ID: uuid.V4(), DueUTC: now.Add(30 * time.Second), HookURL: srv.URL + "/hook"})
+	}
+
+	warmedAt := make(map[string]time.Time)
+	w.prewarm(context.Background(), later, warmedAt)
+	if len(warmedAt) != 0 {
+		t.Fatalf("expected timers past the lead to be left alone, warmed %v", warmedAt)
+	}
+	w.prewarm(context.Background(), wh, warmedAt)
+	w.prewarm(context.Background(), wh, warmedAt)
+	mu.Lock()
+	if conns != 1 {
+		mu.Unlock()
+		t.Fatalf("expected one warmed connection for the host, got %d", conns)
+	}
+	mu.Unlock()
+	if _, err := w.makeHookRequest(&model.Timer{ID: uuid.V4(), DueUTC: now, HookURL: srv.URL + "/hook"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if conns != 1 {
+		t.Fatalf("expected the hook to reuse the warmed connection, got %d connections", conns)
+	}
+	// net/http answers `OPTIONS *` itself, so only the hook reaches the
+	// handler.
+	if len(methods) != 1 || methods[0] != "POST /hook" {
+		t.Fatalf("expected only the hook to reach the handler, got %v", methods)
+	}
+}
+
+func TestPrewarm_idleConnsPerHost(t *testing.T) {
+	w := New("worker-00", nil, OptParallelism(32))
+	if got := w.http.MaxIdleConnsPerHost; got != 0 {
+		t.Fatalf("expected the transport default without pre-warming, got %d", got)
+	}
+	w = New("worker-00", nil, OptParallelism(32), OptPrewarm(10*time.Second, 4))
+	if got := w.http.MaxIdleConnsPerHost; got != 32 {
+		t.Fatalf("expected an idle connection per dispatch slot, got %d", got)
+	}
+}
+
+func TestHookOrigin(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://hooks.example.com/a/b?c=d": "https://hooks.example.com",
+		"http://hooks.example.com:8080/":    "http://hooks.example.com:8080",
+		"ftp://hooks.example.com/":          "",
+		"not a url":                         "",
+	} {
+		got, ok := hookOrigin(raw)
+		if got != want || ok != (want != "") {
+			t.Fatalf("hookOrigin(%q): got %q, %v want %q", raw, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"math"
+
+	"sandman/pkg/model"
+)
+
+// shardVirtualRanges is how many equal sub-ranges the shard space is cut
+// into for assignment. Each sub-range is owned by exactly one worker, so
+// this bounds how finely the space can be balanced: with 1024 sub-ranges
+// a 32 worker fleet is within a few percent of its weighted share.
+const shardVirtualRanges = 1024
+
+// shardVirtualRangeSize is the width of one virtual sub-range.
+const shardVirtualRangeSize = shardSpace / shardVirtualRanges
+
+// assignShards returns the merged shard ranges owned by identity given
+// the current membership.
+//
+// Each virtual sub-range goes to the peer with the highest weighted
+// rendezvous score for it, so when a worker joins or leaves only the
+// sub-ranges it wins or held change hands (~1/N of the space) rather
+// than every band shifting as with a sorted, evenly split ring. The
+// score `-weight / ln(u)` for a uniform u in (0, 1) makes each peer's
+// expected share proportional to its capacity weight.
+//
+// If identity isn't in peers (first tick race, stale `workers` read) it
+// gets the whole space; the claim's lease re-check keeps that safe.
+func assignShards(identity string, peers []model.Worker) []model.ShardRange {
+	var found bool
+	for _, p := range peers {
+		if p.Hostname == identity {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return model.AllShards()
+	}
+
+	var output []model.ShardRange
+	for vnode := uint64(0); vnode < shardVirtualRanges; vnode++ {
+		if shardOwner(vnode, peers) != identity {
+			continue
+		}
+		lo := vnode * shardVirtualRangeSize
+		hi := lo + shardVirtualRangeSize
+		if last := len(output) - 1; last >= 0 && output[last].Hi == lo {
+			output[last].Hi = hi
+			continue
+		}
+		output = append(output, model.ShardRange{Lo: lo, Hi: hi})
+	}
+	return output
+}
+
+// shardOwner returns the hostname of the peer with the highest weighted
+// rendezvous score for the virtual sub-range. Ties break on hostname so
+// every worker computes the same owner.
+func shardOwner(vnode uint64, peers []model.Worker) (owner string) {
+	best := math.Inf(-1)
+	for _, p := range peers {
+		score := rendezvousScore(p.Hostname, vnode, p.CapacityWeightOrDefault())
+		if score > best || (score == best && p.Hostname < owner) {
+			best = score
+			owner = p.Hostname
+		}
+	}
+	return
+}
+
+// rendezvousScore is the weighted rendezvous (highest random weight)
+// score of a peer for a virtual sub-range.
+func rendezvousScore(hostname string, vnode uint64, weight int) float64 {
+	data := make([]byte, len(hostname)+8)
+	copy(data, hostname)
+	binary.BigEndian.PutUint64(data[len(hostname):], vnode)
+	sum := md5.Sum(data)
+	// Top 53 bits mapped into the open interval (0, 1).
+	u := (float64(binary.BigEndian.Uint64(sum[:8])>>11) + 0.5) / (1 << 53)
+	return -float64(weight) / math.Log(u)
+}
@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"fmt"
+	"testing"
+
+	"sandman/pkg/model"
+)
+
+func testPeers(n int) (output []model.Worker) {
+	for i := range n {
+		output = append(output, model.Worker{Hostname: fmt.Sprintf("worker-%02d", i)})
+	}
+	return
+}
+
+// ownerMap returns the owner of each virtual sub-range by asking every
+// peer for its assignment, failing if the peers overlap or leave a gap.
+func ownerMap(t *testing.T, peers []model.Worker) []string {
+	t.Helper()
+	owners := make([]string, shardVirtualRanges)
+	for _, p := range peers {
+		for _, r := range assignShards(p.Hostname, peers) {
+			if r.Lo%shardVirtualRangeSize != 0 || r.Hi%shardVirtualRangeSize != 0 {
+				t.Fatalf("range %+v not aligned to virtual sub-ranges", r)
+			}
+			for v := r.Lo / shardVirtualRangeSize; v < r.Hi/shardVirtualRangeSize; v++ {
+				if owners[v] != "" {
+					t.Fatalf("sub-range %d owned by both %s and %s", v, owners[v], p.Hostname)
+				}
+				owners[v] = p.Hostname
+			}
+		}
+	}
+	for v, owner := range owners {
+		if owner == "" {
+			t.Fatalf("sub-range %d has no owner", v)
+		}
+	}
+	return owners
+}
+
+func TestAssignShards_coversSpaceOnce(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 7, 16} {
+		ownerMap(t, testPeers(n))
+	}
+	single := assignShards("worker-00", testPeers(1))
+	if len(single) != 1 || single[0] != (model.ShardRange{Lo: 0, Hi: shardSpace}) {
+		t.Fatalf("expected a lone worker to own the whole space, got %+v", single)
+	}
+}
+
+func TestAssignShards_unknownIdentityGetsEverything(t *testing.T) {
+	got := assignShards("not-a-peer", testPeers(3))
+	if len(got) != 1 || got[0] != (model.ShardRange{Lo: 0, Hi: shardSpace}) {
+		t.Fatalf("expected the whole space, got %+v", got)
+	}
+}
+
+func TestAssignShards_joinMovesOnlyItsShare(t *testing.T) {
+	before := ownerMap(t, testPeers(8))
+	after := ownerMap(t, testPeers(9))
+	var moved int
+	for v := range before {
+		if before[v] != after[v] {
+			if after[v] != "worker-08" {
+				t.Fatalf("sub-range %d moved from %s to %s rather than to the new worker", v, before[v], after[v])
+			}
+			moved++
+		}
+	}
+	// the new worker should take ~1/9 of the space; allow for variance.
+	if expected := shardVirtualRanges / 9; moved < expected/2 || moved > expected*2 {
+		t.Fatalf("expected about %d sub-ranges to move, moved %d", expected, moved)
+	}
+}
+
+func TestAssignShards_leaveMovesOnlyItsShare(t *testing.T) {
+	peers := testPeers(8)
+	before := ownerMap(t, peers)
+	after := ownerMap(t, append(peers[:3:3], peers[4:]...))
+	for v := range before {
+		if before[v] != after[v] && before[v] != "worker-03" {
+			t.Fatalf("sub-range %d moved from %s though it didn't leave", v, before[v])
+		}
+	}
+}
+
+func TestAssignShards_weightsAreProportional(t *testing.T) {
+	peers := testPeers(4)
+	peers[0].CapacityWeight = 3
+	counts := make(map[string]int)
+	for _, owner := range ownerMap(t, peers) {
+		counts[owner]++
+	}
+	// weights 3:1:1:1, so worker-00 should own about half the space.
+	if got := counts["worker-00"]; got < 440 || got > 584 {
+		t.Fatalf("expected worker-00 to own about 512 sub-ranges, got %d (%v)", got, counts)
+	}
+}
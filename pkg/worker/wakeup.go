@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"sandman/pkg/log"
+	"sandman/pkg/model"
+	"sandman/pkg/wakeup"
+	"sandman/pkg/wheel"
+)
+
+// wakeupBuffer is how many wakeups may queue while a targeted claim
+// runs; beyond it they're dropped and left to the next poll.
+const wakeupBuffer = 1024
+
+// wantsWakeup reports whether a wakeup is for a shard this worker owns
+// and for a timer due before its next poll, i.e. one the poll would
+// pick up late.
+func (w *Worker) wantsWakeup(wu wakeup.Wakeup) bool {
+	if shards := w.shards.Load(); shards != nil && !model.InShardRanges(wu.Shard, *shards) {
+		return false
+	}
+	lastTick := w.lastTick.Load()
+	if lastTick == 0 {
+		return true
+	}
+	nextPoll := time.Unix(0, lastTick).Add(w.tickIntervalOrDefault())
+	return wu.DueUTC.Before(nextPoll)
+}
+
+// wakeupLoop runs a targeted claim for the shards named by wakeups from
+// the worker's wakeup source, between polls. Wakeups arriving together
+// are coalesced into one claim across their shards. If waitForDue is
+// set, a shard is only claimed once its timer is due, for the tick loop
+// whose claim doesn't look ahead; otherwise it's claimed immediately.
+//
+// It returns when ctx is done or the worker starts draining.
+func (w *Worker) wakeupLoop(ctx context.Context, waitForDue bool, claim func(context.Context, []model.ShardRange)) {
+	received := make(chan wakeup.Wakeup, wakeupBuffer)
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		err := w.wakeups.Watch(watchCtx, func(wu wakeup.Wakeup) {
+			select {
+			case received <- wu:
+			default:
+				metricWakeups.WithLabelValues("dropped").Inc()
+			}
+		})
+		if err != nil {
+			log.GetLogger(ctx).Error("worker; wakeup source failed", log.Any("err", err))
+		}
+	}()
+
+	// pending is when each woken shard should be claimed.
+	pending := make(map[uint32]time.Time)
+	timer := w.clock.NewTimer(w.tickIntervalOrDefault())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.draining:
+			return
+		case wu := <-received:
+			if !w.wantsWakeup(wu) {
+				metricWakeups.WithLabelValues("ignored").Inc()
+				continue
+			}
+			metricWakeups.WithLabelValues("accepted").Inc()
+			claimAt := w.clock.Now()
+			if waitForDue && wu.DueUTC.After(claimAt) {
+				claimAt = wu.DueUTC
+			}
+			if at, ok := pending[wu.Shard]; !ok || claimAt.Before(at) {
+				pending[wu.Shard] = claimAt
+			}
+		case <-timer.C():
+		}
+
+		now := w.clock.Now()
+		var shards []model.ShardRange
+		var next time.Time
+		for shard, at := range pending {
+			if !at.After(now) {
+				shards = append(shards, model.ShardRange{Lo: uint64(shard), Hi: uint64(shard) + 1})
+				delete(pending, shard)
+			} else if next.IsZero() || at.Before(next) {
+				next = at
+			}
+		}
+		if len(shards) > 0 {
+			claimCtx, claimCancel := context.WithTimeout(ctx, w.tickIntervalOrDefault())
+			claim(claimCtx, shards)
+			claimCancel()
+		}
+		if !next.IsZero() {
+			timer.Reset(next.Sub(now))
+		}
+	}
+}
+
+// claimWoken is the tick loop's targeted claim: it fires the due timers
+// in the woken shards as a tick would.
+func (w *Worker) claimWoken(ctx context.Context, shards []model.ShardRange) {
+	_, _ = w.claimAndFire(ctx, w.clock.Now().UTC(), w.claimBatchSizeOrDefault(), shards)
+}
+
+// claimWokenIntoWheel is wheel mode's targeted claim: it parks the
+// timers in the woken shards due within the prefetch window in the
+// wheel, as a prefetch would.
+func (w *Worker) claimWokenIntoWheel(ctx context.Context, wh *wheel.Wheel, shards []model.ShardRange) {
+	if wh.Occupancy() >= wheelBackpressureOccupancy {
+		return
+	}
+	limit := min(w.claimBatchSizeOrDefault(), wh.Free())
+	if _, _, err := w.claimIntoWheel(ctx, wh, w.clock.Now().UTC(), limit, shards); err != nil {
+		log.GetLogger(ctx).Error("worker; failed to claim woken timers", log.Any("err", err))
+	}
+}
@@ -0,0 +1,97 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"sandman/pkg/clock"
+	"sandman/pkg/model"
+	"sandman/pkg/wakeup"
+	"sandman/pkg/wheel"
+)
+
+func TestWorker_wantsWakeup(t *testing.T) {
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	w := New("worker-00", nil, OptPollingInterval(5*time.Second), OptClock(clk))
+	now := clk.Now()
+	if !w.wantsWakeup(wakeup.Wakeup{Shard: 500, DueUTC: now.Add(time.Minute)}) {
+		t.Fatalf("expected wakeups before the first poll to be wanted")
+	}
+
+	shards := []model.ShardRange{{Lo: 0, Hi: 100}}
+	w.shards.Store(&shards)
+	w.markTick()
+	clk.Advance(time.Second)
+	for _, tc := range []struct {
+		wakeup wakeup.Wakeup
+		want   bool
+	}{
+		{wakeup.Wakeup{Shard: 50, DueUTC: now.Add(2 * time.Second)}, true},
+		{wakeup.Wakeup{Shard: 99, DueUTC: now.Add(4 * time.Second)}, true},
+		{wakeup.Wakeup{Shard: 100, DueUTC: now.Add(2 * time.Second)}, false},
+		{wakeup.Wakeup{Shard: 50, DueUTC: now.Add(5 * time.Second)}, false},
+	} {
+		if got := w.wantsWakeup(tc.wakeup); got != tc.want {
+			t.Fatalf("wakeup for shard %d due in %v; expected %v, got %v", tc.wakeup.Shard, tc.wakeup.DueUTC.Sub(now), tc.want, got)
+		}
+	}
+}
+
+func TestWorker_wakeupLoop_claimsWokenShard(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clk := clock.NewFake(time.Date(2026, 4, 25, 12, 0, 0, 0, time.UTC))
+	store := model.NewMemoryStore()
+	hub := wakeup.NewHub()
+	w := New("worker-00", store,
+		OptClock(clk),
+		OptPollingInterval(5*time.Second),
+		OptPrefetchWindow(10*time.Second),
+		OptWakeups(hub),
+	)
+	now := clk.Now().UTC()
+	w.markTick()
+	for _, timer := range []model.Timer{
+		{Name: "woken", Shard: 7, DueUTC: now.Add(time.Second), HookURL: "http://localhost/hook"},
+		{Name: "other-shard", Shard: 8, DueUTC: now.Add(time.Second), HookURL: "http://localhost/hook"},
+	} {
+		if err := store.CreateTimer(ctx, &timer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wh := wheel.New(30, now)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.wakeupLoop(ctx, false, func(ctx context.Context, shards []model.ShardRange) {
+			w.claimWokenIntoWheel(ctx, wh, shards)
+		})
+	}()
+
+	// the loop subscribes to the hub asynchronously, so keep publishing
+	// until the claim lands.
+	deadline := time.Now().Add(5 * time.Second)
+	for wh.Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the woken timer to be claimed into the wheel")
+		}
+		_ = hub.Publish(ctx, wakeup.Wakeup{Shard: 7, DueUTC: now.Add(time.Second)})
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if wh.Len() != 1 {
+		t.Fatalf("expected only the woken shard's timer in the wheel, got %d", wh.Len())
+	}
+	other, _, err := store.GetTimerByName(context.Background(), "other-shard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.AssignedWorker != nil {
+		t.Fatalf("expected the other shard's timer to be left for the poll, claimed by %q", *other.AssignedWorker)
+	}
+}
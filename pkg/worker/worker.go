@@ -7,36 +7,180 @@ import (
 	"expvar"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
-	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"sandman/pkg/async"
+	"sandman/pkg/clock"
+	"sandman/pkg/config"
+	"sandman/pkg/dnscache"
+	"sandman/pkg/egress"
+	"sandman/pkg/hook"
 	"sandman/pkg/log"
 	"sandman/pkg/uuid"
 
 	"sandman/pkg/model"
 	"sandman/pkg/utils"
+	"sandman/pkg/wakeup"
 	"sandman/pkg/wheel"
 )
 
 // New returns a new worker.
-func New(identity string, mgr *model.Manager, opts ...WorkerOption) *Worker {
+func New(identity string, mgr model.TimerStore, opts ...WorkerOption) *Worker {
 	w := &Worker{
 		identity: identity,
 		mgr:      mgr,
+		clock:    clock.Real(),
 		http:     new(http.Transport),
+		draining: make(chan struct{}),
+		drained:  make(chan struct{}),
 	}
 	for _, opt := range opts {
 		opt(w)
 	}
+	if w.egressPolicy != nil || w.dnsCache != nil {
+		dial := (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   w.egressPolicy.Control,
+		}).DialContext
+		if w.dnsCache != nil {
+			dial = w.dnsCache.DialContext(dial)
+		}
+		w.http.DialContext = dial
+	}
+	if w.prewarmLead > 0 && w.http.MaxIdleConnsPerHost < w.parallelismOrDefault() {
+		w.http.MaxIdleConnsPerHost = w.parallelismOrDefault()
+	}
+	if w.maxBatchSize > 0 {
+		w.batches = newBatchController(w.batchSizeOrDefault(), w.minBatchSize, w.maxBatchSize, w.parallelismOrDefault(), w.tickIntervalOrDefault())
+	}
+	w.claimBatchSize.Set(int64(w.claimBatchSizeOrDefault()))
 	return w
 }
 
 type WorkerOption func(*Worker)
 
+// OptClock sets the clock the worker reads the time and ticks from.
+// Defaults to the wall clock; tests pass a clock.Fake.
+func OptClock(c clock.Clock) WorkerOption {
+	return func(w *Worker) {
+		w.clock = clock.OrReal(c)
+	}
+}
+
+// OptConfig applies the worker settings from the shared config file;
+// zero values leave the defaults in place.
+func OptConfig(cfg config.WorkerConfig) WorkerOption {
+	return func(w *Worker) {
+		opts := []WorkerOption{
+			OptBatchSize(cfg.BatchSizeOrDefault()),
+			OptPollingInterval(cfg.PollingIntervalOrDefault()),
+		}
+		if cfg.PrefetchWindow > 0 {
+			opts = append(opts, OptPrefetchWindow(cfg.PrefetchWindow))
+		}
+		if cfg.WheelResolution > 0 {
+			opts = append(opts, OptWheelResolution(cfg.WheelResolution))
+		}
+		if cfg.WheelMaxOverflow > 0 {
+			opts = append(opts, OptWheelMaxOverflow(cfg.WheelMaxOverflow))
+		}
+		if cfg.WheelMaxHeld > 0 {
+			opts = append(opts, OptWheelMaxHeld(cfg.WheelMaxHeld))
+		}
+		if cfg.WheelMaxHeldBytes > 0 {
+			opts = append(opts, OptWheelMaxHeldBytes(cfg.WheelMaxHeldBytes))
+		}
+		if cfg.DispatchTickInterval > 0 {
+			opts = append(opts, OptDispatchTickInterval(cfg.DispatchTickInterval))
+		}
+		if cfg.PacedDispatch {
+			opts = append(opts, OptPacedDispatch(cfg.PacingTolerance))
+		}
+		if cfg.PrewarmLead > 0 {
+			opts = append(opts, OptPrewarm(cfg.PrewarmLead, cfg.PrewarmMaxHosts))
+		}
+		if cfg.DNSCacheTTL > 0 {
+			opts = append(opts, OptDNSCacheTTL(cfg.DNSCacheTTL))
+		}
+		if cfg.FlushInterval > 0 {
+			opts = append(opts, OptFlushInterval(cfg.FlushInterval))
+		}
+		if cfg.DrainTimeout > 0 {
+			opts = append(opts, OptDrainTimeout(cfg.DrainTimeout))
+		}
+		if cfg.LeaseRenewInterval > 0 {
+			opts = append(opts, OptLeaseRenewInterval(cfg.LeaseRenewInterval))
+		}
+		if len(cfg.ShardKeyWeights) > 0 {
+			opts = append(opts, OptShardKeyWeights(cfg.ShardKeyWeights))
+		}
+		if cfg.MaxClaimsPerKey > 0 {
+			opts = append(opts, OptMaxClaimsPerKey(cfg.MaxClaimsPerKey))
+		}
+		if cfg.MaxBatchSize > 0 {
+			opts = append(opts, OptAdaptiveBatchSize(cfg.MinBatchSize, cfg.MaxBatchSize))
+		}
+		if cfg.CapacityWeight > 0 {
+			opts = append(opts, OptCapacityWeight(cfg.CapacityWeight))
+		}
+		if cfg.HookSigningSecret != "" {
+			opts = append(opts, OptHookSigningSecret([]byte(cfg.HookSigningSecret)))
+		}
+		for _, opt := range opts {
+			opt(w)
+		}
+	}
+}
+
+// OptEgressPolicy installs a dialer control on the hook transport that
+// rejects private, loopback and link-local addresses (after DNS
+// resolution) unless the policy overrides them.
+func OptEgressPolicy(policy *egress.Policy) WorkerOption {
+	return func(w *Worker) {
+		w.egressPolicy = policy
+	}
+}
+
+// OptDNSCacheTTL resolves hook hosts through a cache that keeps each
+// lookup for ttl rather than resolving on every new connection. The
+// egress policy still checks every address dialed.
+func OptDNSCacheTTL(ttl time.Duration) WorkerOption {
+	return func(w *Worker) {
+		w.dnsCache = dnscache.New(ttl)
+	}
+}
+
+// OptPrewarm has wheel mode open connections to the hosts of timers due
+// within the next `lead` before they fire, warming at most maxHosts
+// hosts at a time. Pre-warming is opt-in: a lead of zero (the default)
+// turns it off, and it is only meaningful when OptPrefetchWindow > 0.
+//
+// Warming a host sends it a real `OPTIONS *` request, at most once per
+// host every 30 seconds, whose response is discarded. It also raises
+// the hook transport's idle connections per host to at least the
+// dispatch parallelism so the warmed connections are kept.
+func OptPrewarm(lead time.Duration, maxHosts int) WorkerOption {
+	return func(w *Worker) {
+		w.prewarmLead = lead
+		w.prewarmMaxHosts = maxHosts
+	}
+}
+
+// OptWakeups has the worker watch source for timers created due before
+// its next poll and claim them straight away, rather than up to a
+// polling interval late. Nil (the default) leaves it to polling alone.
+func OptWakeups(source wakeup.Source) WorkerOption {
+	return func(w *Worker) {
+		w.wakeups = source
+	}
+}
+
 func OptParallelism(parallelism int) WorkerOption {
 	return func(w *Worker) {
 		w.parallelism = parallelism
@@ -55,12 +199,31 @@ func OptHookTimeout(timeout time.Duration) WorkerOption {
 	}
 }
 
+// OptHookSigningSecret signs every hook request body with the given
+// secret; see `hook.Sign` for the header format.
+func OptHookSigningSecret(secret []byte) WorkerOption {
+	return func(w *Worker) {
+		w.hookSigningSecret = secret
+	}
+}
+
 func OptBatchSize(batchSize int) WorkerOption {
 	return func(w *Worker) {
 		w.batchSize = batchSize
 	}
 }
 
+// OptAdaptiveBatchSize has the worker tune its claim batch between
+// minSize and maxSize from observed hook latency, parallelism
+// saturation, claim latency and wheel occupancy, starting from the
+// OptBatchSize value. A maxSize of zero keeps the batch fixed.
+func OptAdaptiveBatchSize(minSize, maxSize int) WorkerOption {
+	return func(w *Worker) {
+		w.minBatchSize = minSize
+		w.maxBatchSize = maxSize
+	}
+}
+
 // OptPrefetchWindow turns on wheel-mode dispatch. Each prefetch claims
 // every timer due within the next `window` and parks them in an in-
 // memory hash wheel; a 1-second dispatch loop drains the wheel and a
@@ -77,14 +240,64 @@ func OptPrefetchWindow(window time.Duration) WorkerOption {
 	}
 }
 
+// OptWheelResolution sets the width of the wheel's finest slots, which
+// must evenly divide a second. Defaults to 1s; only meaningful when
+// OptPrefetchWindow > 0.
+func OptWheelResolution(resolution time.Duration) WorkerOption {
+	return func(w *Worker) {
+		w.wheelResolution = resolution
+	}
+}
+
+// OptWheelMaxOverflow caps how many timers due beyond the wheel's
+// horizon the worker holds until they come within it; past the cap they
+// are relinquished as soon as they're claimed. Zero (the default) is
+// unbounded; only meaningful when OptPrefetchWindow > 0.
+func OptWheelMaxOverflow(maxOverflow int) WorkerOption {
+	return func(w *Worker) {
+		w.wheelMaxOverflow = maxOverflow
+	}
+}
+
+// OptWheelMaxHeld caps how many timers the wheel holds. Prefetch claims
+// no more than the wheel has room for, and skips a tick when it's nearly
+// full. Zero (the default) is unbounded; only meaningful when
+// OptPrefetchWindow > 0.
+func OptWheelMaxHeld(maxHeld int) WorkerOption {
+	return func(w *Worker) {
+		w.wheelMaxHeld = maxHeld
+	}
+}
+
+// OptWheelMaxHeldBytes caps the approximate memory the timers held in
+// the wheel take up, hook bodies included, with the same backpressure
+// as OptWheelMaxHeld. Zero (the default) is unbounded.
+func OptWheelMaxHeldBytes(maxHeldBytes int64) WorkerOption {
+	return func(w *Worker) {
+		w.wheelMaxHeldBytes = maxHeldBytes
+	}
+}
+
 // OptDispatchTickInterval overrides the wheel-mode dispatch cadence.
-// Defaults to 1s; only meaningful when OptPrefetchWindow > 0.
+// Defaults to the wheel resolution; only meaningful when
+// OptPrefetchWindow > 0.
 func OptDispatchTickInterval(d time.Duration) WorkerOption {
 	return func(w *Worker) {
 		w.dispatchTickInterval = d
 	}
 }
 
+// OptPacedDispatch spreads each dispatch tick's timers evenly across the
+// tick, highest priority first, instead of firing them in one burst at
+// its start. No timer fires before its due_utc or later than tolerance
+// after it. Only meaningful when OptPrefetchWindow > 0.
+func OptPacedDispatch(tolerance time.Duration) WorkerOption {
+	return func(w *Worker) {
+		w.pacedDispatch = true
+		w.pacingTolerance = tolerance
+	}
+}
+
 // OptFlushInterval overrides how often the wheel-mode flush loop pushes
 // batched delivered/attempted writes to the DB. Defaults to 1s.
 func OptFlushInterval(d time.Duration) WorkerOption {
@@ -93,36 +306,141 @@ func OptFlushInterval(d time.Duration) WorkerOption {
 	}
 }
 
+// OptLeaseRenewInterval overrides how often the wheel-mode renewal loop
+// extends the leases of timers still parked in the wheel. Defaults to a
+// third of the lease safety margin; only meaningful when
+// OptPrefetchWindow > 0.
+func OptLeaseRenewInterval(d time.Duration) WorkerOption {
+	return func(w *Worker) {
+		w.leaseRenewInterval = d
+	}
+}
+
+// OptDrainTimeout bounds how long a wheel-mode drain waits for the
+// wheel to empty before relinquishing what's left. Defaults to 20s.
+func OptDrainTimeout(d time.Duration) WorkerOption {
+	return func(w *Worker) {
+		w.drainTimeout = d
+	}
+}
+
+// OptShardKeyWeights sets per shard key weights for the claim query's
+// round-robin; see model.ClaimOptions. Keys without an entry get
+// model.DefaultClaimKeyWeight.
+func OptShardKeyWeights(weights map[string]uint32) WorkerOption {
+	return func(w *Worker) {
+		w.shardKeyWeights = weights
+	}
+}
+
+// OptMaxClaimsPerKey caps how many timers one (default-weight) shard key
+// can take out of a single claim. Zero leaves it bounded by the batch.
+func OptMaxClaimsPerKey(maxPerKey int) WorkerOption {
+	return func(w *Worker) {
+		w.maxClaimsPerKey = maxPerKey
+	}
+}
+
+// OptCapacityWeight sets the weight the worker advertises to its peers;
+// shard assignment gives each worker a share of the shard space
+// proportional to its weight. Defaults to 1.
+func OptCapacityWeight(weight int) WorkerOption {
+	return func(w *Worker) {
+		w.capacityWeight = weight
+	}
+}
+
 type Worker struct {
-	identity string
-	mgr      *model.Manager
+	identity       string
+	mgr            model.TimerStore
+	clock          clock.Clock
+	capacityWeight int
 
 	parallelism     int
 	pollingInterval time.Duration
 	hookTimeout     time.Duration
 	batchSize       int
+	minBatchSize    int
+	maxBatchSize    int
+	batches         *batchController
+
+	shardKeyWeights map[string]uint32
+	maxClaimsPerKey int
 
 	prefetchWindow       time.Duration
+	wheelResolution      time.Duration
+	wheelMaxOverflow     int
+	wheelMaxHeld         int
+	wheelMaxHeldBytes    int64
 	dispatchTickInterval time.Duration
+	pacedDispatch        bool
+	pacingTolerance      time.Duration
 	flushInterval        time.Duration
+	leaseRenewInterval   time.Duration
+	drainTimeout         time.Duration
+
+	http              *http.Transport
+	egressPolicy      *egress.Policy
+	dnsCache          *dnscache.Cache
+	hookSigningSecret []byte
 
-	http *http.Transport
+	prewarmLead     time.Duration
+	prewarmMaxHosts int
+
+	wakeups wakeup.Source
+	// shards is the band from the latest poll, which wakeups are
+	// filtered against.
+	shards atomic.Pointer[[]model.ShardRange]
 
 	timersProcessed              expvar.Int
 	timersProcessedRemoteError   expvar.Int
 	timersProcessedInternalError expvar.Int
+	timersExpired                expvar.Int
+	timersStaleWrites            expvar.Int
+	timersLeaseEvicted           expvar.Int
+	claimBatchSize               expvar.Int
+
+	inFlight     atomic.Int64
+	peakInFlight atomic.Int64
+
+	// lastSeen and lastTick are unix nanos backing Ready and Alive.
+	lastSeen  atomic.Int64
+	lastTick  atomic.Int64
+	drainOnce sync.Once
+	draining  chan struct{}
+	drained   chan struct{}
 }
 
 type WorkerVars struct {
 	TimersProcessed              *expvar.Int
 	TimersProcessedRemoteError   *expvar.Int
 	TimersProcessedInternalError *expvar.Int
+	// TimersExpired counts claimed timers that were past their
+	// expires_utc and were marked expired instead of delivered. They are
+	// not included in TimersProcessed.
+	TimersExpired *expvar.Int
+	// TimersStaleWrites counts completion writes (delivered, attempted,
+	// expired) the DB rejected because a peer had re-claimed the timer
+	// after this worker's lease ran out.
+	TimersStaleWrites *expvar.Int
+	// TimersLeaseEvicted counts timers dropped from the wheel unfired
+	// because their lease could not be renewed; a peer (or this worker,
+	// once the lease lapses) re-claims them.
+	TimersLeaseEvicted *expvar.Int
+	// ClaimBatchSize is the current claim batch size; it only moves when
+	// the batch is adaptive. In wheel mode each prefetch claims a
+	// multiple of it scaled to the window.
+	ClaimBatchSize *expvar.Int
 }
 
 func (wv WorkerVars) Publish() {
 	expvar.Publish("timers_processed", wv.TimersProcessed)
 	expvar.Publish("timers_processed_remote_error", wv.TimersProcessedRemoteError)
 	expvar.Publish("timers_processed_internal_error", wv.TimersProcessedInternalError)
+	expvar.Publish("timers_expired", wv.TimersExpired)
+	expvar.Publish("timers_stale_writes", wv.TimersStaleWrites)
+	expvar.Publish("timers_lease_evicted", wv.TimersLeaseEvicted)
+	expvar.Publish("claim_batch_size", wv.ClaimBatchSize)
 }
 
 func (w *Worker) Vars() WorkerVars {
@@ -130,6 +448,10 @@ func (w *Worker) Vars() WorkerVars {
 		TimersProcessed:              &w.timersProcessed,
 		TimersProcessedRemoteError:   &w.timersProcessedRemoteError,
 		TimersProcessedInternalError: &w.timersProcessedInternalError,
+		TimersExpired:                &w.timersExpired,
+		TimersStaleWrites:            &w.timersStaleWrites,
+		TimersLeaseEvicted:           &w.timersLeaseEvicted,
+		ClaimBatchSize:               &w.claimBatchSize,
 	}
 }
 
@@ -138,15 +460,29 @@ func (w *Worker) Run(ctx context.Context) error {
 	if w.prefetchWindow > 0 {
 		return w.runWheelMode(ctx)
 	}
-	tick := time.NewTicker(w.tickIntervalOrDefault())
+	tick := w.clock.NewTicker(w.tickIntervalOrDefault())
 	defer tick.Stop()
+	var ticks sync.WaitGroup
+	if w.wakeups != nil {
+		ticks.Add(1)
+		go func() { defer ticks.Done(); w.wakeupLoop(ctx, true, w.claimWoken) }()
+	}
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-tick.C:
+		case <-w.draining:
+			ticks.Wait()
+			w.deregister(ctx)
+			close(w.drained)
+			log.GetLogger(ctx).Info("worker; drained")
+			<-ctx.Done()
+			return nil
+		case <-tick.C():
 			deadlineCtx, deadlineCancel := context.WithTimeout(ctx, w.tickIntervalOrDefault())
+			ticks.Add(1)
 			go func() {
+				defer ticks.Done()
 				defer deadlineCancel()
 				w.processTick(deadlineCtx)
 			}()
@@ -204,21 +540,91 @@ func (w *Worker) batchSizeOrDefault() int {
 	return defaultBatchSize
 }
 
+func (w *Worker) capacityWeightOrDefault() int {
+	if w.capacityWeight > 0 {
+		return w.capacityWeight
+	}
+	return 1
+}
+
+// claimBatchSizeOrDefault is the batch to claim this tick; the adaptive
+// controller's current size if there is one.
+func (w *Worker) claimBatchSizeOrDefault() int {
+	if w.batches != nil {
+		return w.batches.Size()
+	}
+	return w.batchSizeOrDefault()
+}
+
+// observeTick feeds a tick's outcome to the adaptive batch controller.
+func (w *Worker) observeTick(obs tickObservation) {
+	if w.batches == nil {
+		return
+	}
+	obs.PeakInFlight = int(w.peakInFlight.Swap(w.inFlight.Load()))
+	w.claimBatchSize.Set(int64(w.batches.Observe(obs)))
+}
+
 func (w *Worker) processTick(ctx context.Context) {
-	nowUTC := time.Now().UTC()
+	w.markTick()
+	nowUTC := w.clock.Now().UTC()
 
-	if err := w.mgr.WorkerSeen(ctx, w.identity, nowUTC); err != nil {
+	if err := w.mgr.WorkerSeen(ctx, w.identity, nowUTC, w.capacityWeightOrDefault(), 0); err != nil {
 		log.GetLogger(ctx).Error("worker; failed to update last seen", log.Any("err", err))
 		return
 	}
+	w.markSeen(nowUTC)
 
-	shardLo, shardHi := w.currentShardBand(ctx, nowUTC)
+	shards := w.currentShards(ctx, nowUTC)
 
-	timers, err := w.mgr.GetDueTimers(ctx, w.identity, nowUTC, w.batchSizeOrDefault(), shardLo, shardHi)
+	if obs, ok := w.claimAndFire(ctx, nowUTC, w.claimBatchSizeOrDefault(), shards); ok {
+		w.observeTick(obs)
+	}
+}
+
+// claimAndFire claims up to batchSize timers in shards due as of nowUTC,
+// fires them and records the outcomes. ok is false if the claim itself
+// failed; otherwise obs describes the claim for the batch controller.
+func (w *Worker) claimAndFire(ctx context.Context, nowUTC time.Time, batchSize int, shards []model.ShardRange) (obs tickObservation, ok bool) {
+	claimStarted := w.clock.Now()
+	claimed, err := w.mgr.GetDueTimers(ctx, w.identity, nowUTC, batchSize, shards, w.claimOptions()...)
+	claimLatency := w.clock.Since(claimStarted)
+	metricClaimDuration.ObserveDuration(claimLatency)
 	if err != nil {
 		log.GetLogger(ctx).Error("worker; failed to get timers", log.Any("err", err))
 		return
 	}
+	ok = true
+	processStarted := w.clock.Now()
+	defer func() {
+		obs = tickObservation{
+			Requested:    batchSize,
+			Claimed:      len(claimed),
+			ClaimLatency: claimLatency,
+			Elapsed:      w.clock.Since(processStarted),
+		}
+	}()
+
+	// Timers claimed past their expiry are retired without firing; the
+	// rest go out as normal.
+	timers := claimed[:0]
+	var expired []model.TimerLease
+	for index := range claimed {
+		if claimed[index].IsExpired(nowUTC) {
+			expired = append(expired, claimed[index].Lease())
+			continue
+		}
+		timers = append(timers, claimed[index])
+	}
+	if len(expired) > 0 {
+		log.GetLogger(ctx).Info("worker; marking timers expired",
+			log.Int("timers", len(expired)),
+		)
+		if stale, err := w.bulkMarkExpiredWithRetry(ctx, expired); err == nil {
+			w.timersExpired.Add(int64(len(expired)) - stale)
+		}
+	}
+
 	b, _ := async.BatchContext(ctx)
 	b.SetLimit(w.parallelismOrDefault())
 	for index := range timers {
@@ -230,19 +636,20 @@ func (w *Worker) processTick(ctx context.Context) {
 		return
 	}
 
-	var deliveredIDs []uuid.UUID
+	var delivered []model.TimerDelivery
 	for index := range timers {
 		if timers[index].DeliveredUTC != nil && !timers[index].DeliveredUTC.IsZero() {
-			deliveredIDs = append(deliveredIDs, timers[index].ID)
+			delivered = append(delivered, timers[index].Delivery(*timers[index].FiredUTC))
 		}
 	}
 
-	if len(deliveredIDs) > 0 {
+	if len(delivered) > 0 {
 		log.GetLogger(ctx).Info("worker; marking timers delivered",
-			log.Int("timers", len(deliveredIDs)),
+			log.Int("timers", len(delivered)),
 		)
-		w.bulkMarkDeliveredWithRetry(ctx, deliveredIDs)
+		_, _ = w.bulkMarkDeliveredWithRetry(ctx, delivered)
 	}
+	return
 }
 
 // shardStaleness is how recently a peer must have reported in to count
@@ -250,43 +657,30 @@ func (w *Worker) processTick(ctx context.Context) {
 // interval so a transiently-slow worker doesn't drop out.
 const shardStaleness = 30 * time.Second
 
-// shardSpace is one past the max uint32 shard value; using uint64 so
-// the upper bound is expressible without overflow.
-const shardSpace uint64 = 1 << 32
+// shardSpace is one past the max uint32 shard value.
+const shardSpace = model.ShardSpace
 
-// currentShardBand computes the half-open [lo, hi) slice of the uint32
-// shard space this worker should poll this tick. Workers sort by
-// hostname and each claims its indexed slice of the space. If the
-// worker isn't yet in the visible set (first tick race, stale `workers`
-// read) we fall back to the whole space — SKIP LOCKED keeps that safe.
-func (w *Worker) currentShardBand(ctx context.Context, now time.Time) (uint64, uint64) {
+// currentShards computes the ranges of the uint32 shard space this
+// worker should poll this tick; see assignShards. With no visible peers
+// (or on error) it falls back to the whole space.
+func (w *Worker) currentShards(ctx context.Context, now time.Time) []model.ShardRange {
+	shards := model.AllShards()
 	peers, err := w.mgr.GetWorkers(ctx, now.Add(-shardStaleness))
-	if err != nil || len(peers) == 0 {
-		if err != nil {
-			log.GetLogger(ctx).Error("worker; failed to list peers for shard band", log.Any("err", err))
-		}
-		return 0, shardSpace
-	}
-	sort.Slice(peers, func(i, j int) bool { return peers[i].Hostname < peers[j].Hostname })
-	myIndex := -1
-	for i, p := range peers {
-		if p.Hostname == w.identity {
-			myIndex = i
-			break
-		}
-	}
-	if myIndex < 0 {
-		return 0, shardSpace
+	if err != nil {
+		log.GetLogger(ctx).Error("worker; failed to list peers for shard assignment", log.Any("err", err))
+	} else if len(peers) > 0 {
+		shards = assignShards(w.identity, peers)
 	}
-	n := uint64(len(peers))
-	band := shardSpace / n
-	lo := uint64(myIndex) * band
-	hi := lo + band
-	// Last worker absorbs the remainder so the whole space is covered.
-	if myIndex == len(peers)-1 {
-		hi = shardSpace
+	w.shards.Store(&shards)
+	return shards
+}
+
+// claimOptions are the fairness settings passed to every claim.
+func (w *Worker) claimOptions() []model.ClaimOption {
+	return []model.ClaimOption{
+		model.OptClaimKeyWeights(w.shardKeyWeights),
+		model.OptClaimMaxPerKey(w.maxClaimsPerKey),
 	}
-	return lo, hi
 }
 
 const dbWriteMaxRetries = 3
@@ -360,16 +754,63 @@ func retryDBWrite(ctx context.Context, label string, fn func(context.Context) er
 	return lastErr
 }
 
-func (w *Worker) bulkMarkDeliveredWithRetry(ctx context.Context, ids []uuid.UUID) error {
-	return retryDBWrite(ctx, "worker; failed to mark timers delivered", func(c context.Context) error {
-		return w.mgr.BulkMarkDelivered(c, time.Now().UTC(), ids)
+// The completion writes below are fenced on each timer's lease token;
+// writes for timers whose lease was lost to a peer are rejected by the
+// DB and counted in timersStaleWrites rather than treated as errors.
+
+func (w *Worker) bulkMarkDeliveredWithRetry(ctx context.Context, deliveries []model.TimerDelivery) (stale int64, err error) {
+	metricFlushBatchSize.WithLabelValues("delivered").Observe(float64(len(deliveries)))
+	err = retryDBWrite(ctx, "worker; failed to mark timers delivered", func(c context.Context) (writeErr error) {
+		stale, writeErr = w.mgr.BulkMarkDelivered(c, w.clock.Now().UTC(), deliveries)
+		return
+	})
+	w.observeStaleWrites(ctx, "delivered", stale)
+	return
+}
+
+func (w *Worker) bulkMarkExpiredWithRetry(ctx context.Context, leases []model.TimerLease) (stale int64, err error) {
+	metricFlushBatchSize.WithLabelValues("expired").Observe(float64(len(leases)))
+	err = retryDBWrite(ctx, "worker; failed to mark timers expired", func(c context.Context) (writeErr error) {
+		stale, writeErr = w.mgr.BulkMarkExpired(c, w.clock.Now().UTC(), leases)
+		return
 	})
+	w.observeStaleWrites(ctx, "expired", stale)
+	return
 }
 
-func (w *Worker) markAttemptedWithRetry(ctx context.Context, id uuid.UUID, statusCode uint32, remoteErr error, asOf time.Time) error {
-	return retryDBWrite(ctx, "worker; failed to mark attempted", func(c context.Context) error {
-		return w.mgr.MarkAttempted(c, id, statusCode, remoteErr, asOf)
+func (w *Worker) bulkMarkAttemptedWithRetry(ctx context.Context, statusCode uint32, remoteErr error, leases []model.TimerLease) (stale int64, err error) {
+	metricFlushBatchSize.WithLabelValues("attempted").Observe(float64(len(leases)))
+	err = retryDBWrite(ctx, "worker; failed to mark batch attempted", func(c context.Context) (writeErr error) {
+		stale, writeErr = w.mgr.BulkMarkAttempted(c, statusCode, remoteErr, w.clock.Now().UTC(), leases)
+		return
 	})
+	w.observeStaleWrites(ctx, "attempted", stale)
+	return
+}
+
+func (w *Worker) markAttemptedWithRetry(ctx context.Context, lease model.TimerLease, statusCode uint32, remoteErr error, asOf time.Time) error {
+	var stale bool
+	err := retryDBWrite(ctx, "worker; failed to mark attempted", func(c context.Context) (writeErr error) {
+		stale, writeErr = w.mgr.MarkAttempted(c, lease, statusCode, remoteErr, asOf)
+		return
+	})
+	if stale {
+		w.observeStaleWrites(ctx, "attempted", 1)
+	}
+	return err
+}
+
+// observeStaleWrites counts and logs completion writes rejected because
+// this worker no longer held the timers' leases.
+func (w *Worker) observeStaleWrites(ctx context.Context, kind string, stale int64) {
+	if stale <= 0 {
+		return
+	}
+	w.timersStaleWrites.Add(stale)
+	log.GetLogger(ctx).Warn("worker; rejected stale completion writes, leases were lost",
+		log.String("kind", kind),
+		log.Int("timers", int(stale)),
+	)
 }
 
 func (w *Worker) processTickTimer(ctx context.Context, t *model.Timer) func() error {
@@ -385,7 +826,7 @@ func (w *Worker) processTickTimer(ctx context.Context, t *model.Timer) func() er
 			}
 		}()
 
-		started := time.Now()
+		started := w.clock.Now()
 		var res *http.Response
 		res, remoteErr = w.makeHookRequest(t)
 		if remoteErr != nil || res.StatusCode >= http.StatusBadRequest {
@@ -396,20 +837,21 @@ func (w *Worker) processTickTimer(ctx context.Context, t *model.Timer) func() er
 			if remoteErr != nil {
 				log.GetLogger(ctx).Err(fmt.Errorf("worker; failed to deliver to remote: %w", remoteErr), w.logAttrs(t,
 					log.String("err_type", "remote"),
-					log.Duration("elapsed", time.Since(started)),
+					log.Duration("elapsed", w.clock.Since(started)),
 				)...)
 			} else {
 				log.GetLogger(ctx).Err(fmt.Errorf("worker; failed to deliver to remote: non-200 status code returned %d", statusCode), w.logAttrs(t,
 					log.String("err_type", "remote"),
-					log.Duration("elapsed", time.Since(started)),
+					log.Duration("elapsed", w.clock.Since(started)),
 				)...)
 			}
-			internalErr = w.markAttemptedWithRetry(ctx, t.ID, uint32(statusCode), remoteErr, time.Now().UTC())
+			internalErr = w.markAttemptedWithRetry(ctx, t.Lease(), uint32(statusCode), remoteErr, w.clock.Now().UTC())
 			return nil
 		}
 
 		// mark the timer as delivered
-		t.DeliveredUTC = utils.Ref(time.Now().UTC())
+		t.FiredUTC = utils.Ref(started.UTC())
+		t.DeliveredUTC = utils.Ref(w.clock.Now().UTC())
 		return nil
 	}
 }
@@ -428,6 +870,21 @@ func (w *Worker) makeHookRequest(t *model.Timer) (*http.Response, error) {
 		method = http.MethodPost
 	}
 
+	inFlight := w.inFlight.Add(1)
+	for peak := w.peakInFlight.Load(); inFlight > peak && !w.peakInFlight.CompareAndSwap(peak, inFlight); {
+		peak = w.peakInFlight.Load()
+	}
+	started := w.clock.Now()
+	metricDeliveryLateness.ObserveDuration(started.Sub(t.DueUTC))
+	defer func() {
+		w.inFlight.Add(-1)
+		elapsed := w.clock.Since(started)
+		metricHookDuration.ObserveDuration(elapsed)
+		if w.batches != nil {
+			w.batches.ObserveHook(elapsed)
+		}
+	}()
+
 	requestContext, cancelTimeout := context.WithTimeout(context.Background(), w.hookTimeoutOrDefault())
 	defer cancelTimeout()
 	req, err := http.NewRequestWithContext(requestContext, method, t.HookURL, body)
@@ -435,10 +892,14 @@ func (w *Worker) makeHookRequest(t *model.Timer) (*http.Response, error) {
 		return nil, fmt.Errorf("failed to parse hook details: %w", err)
 	}
 	req.Header = w.metadata(t)
+	if len(w.hookSigningSecret) > 0 {
+		req.Header.Set(hook.HeaderSignature, hook.Sign(w.hookSigningSecret, w.clock.Now(), t.HookBody))
+	}
 	client := &http.Client{
 		Transport: w.http,
 	}
 	res, err := client.Do(req)
+	metricHookResponses.WithLabelValues(hookResponseCode(res, err)).Inc()
 	if res != nil {
 		// Drain and close so the underlying TCP connection is returned
 		// to the Transport's idle pool. Without this every fired timer
@@ -458,11 +919,22 @@ func (w *Worker) logAttrs(t *model.Timer, extra ...any) []any {
 	}, extra...)
 }
 
+// metadata returns the request headers for a timer's hook.
+//
+// The timer's own headers are applied first so the delivery headers can't
+// be overridden by them; the idempotency key is the timer id so it's
+// stable across attempts.
 func (w *Worker) metadata(t *model.Timer) (output http.Header) {
 	output = make(http.Header)
 	for key, value := range t.HookHeaders {
 		output.Set(key, value)
 	}
+	hook.Delivery{
+		TimerID:        t.ID,
+		Attempt:        t.Attempt,
+		DueUTC:         t.DueUTC,
+		IdempotencyKey: t.ID.String(),
+	}.Header(output)
 	return
 }
 
@@ -483,13 +955,20 @@ const wheelLeaseSafetyMargin = 30 * time.Second
 // hash an inserted timer into the slot the cursor just emptied.
 const wheelSlotHeadroom = 4
 
-const defaultDispatchTickInterval = 1 * time.Second
+func (w *Worker) wheelResolutionOrDefault() time.Duration {
+	if w.wheelResolution > 0 {
+		return w.wheelResolution
+	}
+	return wheel.DefaultResolution
+}
 
+// dispatchTickIntervalOrDefault ticks once per wheel slot by default;
+// ticking slower than the resolution fires several slots at once.
 func (w *Worker) dispatchTickIntervalOrDefault() time.Duration {
 	if w.dispatchTickInterval > 0 {
 		return w.dispatchTickInterval
 	}
-	return defaultDispatchTickInterval
+	return w.wheelResolutionOrDefault()
 }
 
 const defaultFlushInterval = 1 * time.Second
@@ -501,34 +980,61 @@ func (w *Worker) flushIntervalOrDefault() time.Duration {
 	return defaultFlushInterval
 }
 
+func (w *Worker) leaseRenewIntervalOrDefault() time.Duration {
+	if w.leaseRenewInterval > 0 {
+		return w.leaseRenewInterval
+	}
+	return wheelLeaseSafetyMargin / 3
+}
+
 // dispatchResult is the outcome of a single hook firing, queued onto the
-// flushLoop's results channel. Either DeliveredAt is set (success) or
-// StatusCode/RemoteErr describe the failure to record.
+// flushLoop's results channel. Either DeliveredAt is set (success, with
+// FiredAt when the request went out), StatusCode/RemoteErr describe the
+// failure to record, or Expired marks a timer whose expires_utc passed
+// while it sat in the wheel.
 type dispatchResult struct {
-	ID          uuid.UUID
+	Lease       model.TimerLease
+	FiredAt     time.Time
 	DeliveredAt time.Time
 	StatusCode  uint32
 	RemoteErr   error
 	Failed      bool
+	Expired     bool
 }
 
 func (w *Worker) runWheelMode(ctx context.Context) error {
 	logger := log.GetLogger(ctx)
-	now := time.Now().UTC()
+	now := w.clock.Now().UTC()
 	slotCount := max(int(w.prefetchWindow/time.Second)+wheelSlotHeadroom, 8)
-	wh := wheel.New(slotCount, now)
+	wh := wheel.New(slotCount, now,
+		wheel.OptResolution(w.wheelResolutionOrDefault()),
+		wheel.OptMaxOverflow(w.wheelMaxOverflow),
+		wheel.OptMaxHeld(w.wheelMaxHeld),
+		wheel.OptMaxHeldBytes(w.wheelMaxHeldBytes),
+	)
 	results := make(chan dispatchResult, 4096)
 
 	logger.Info("worker; wheel mode start",
 		log.Duration("prefetch_window", w.prefetchWindow),
 		log.Int("slot_count", slotCount),
+		log.Duration("wheel_resolution", wh.Resolution()),
+		log.Int("wheel_max_overflow", w.wheelMaxOverflow),
+		log.Int("wheel_max_held", w.wheelMaxHeld),
+		log.Int64("wheel_max_held_bytes", w.wheelMaxHeldBytes),
 		log.Duration("dispatch_tick", w.dispatchTickIntervalOrDefault()),
+		log.Bool("paced_dispatch", w.pacedDispatch),
+		log.Duration("pacing_tolerance", w.pacingTolerance),
+		log.Duration("prewarm_lead", w.prewarmLead),
+		log.Int("prewarm_max_hosts", w.prewarmMaxHostsOrDefault()),
+		log.Bool("wakeups", w.wakeups != nil),
 		log.Duration("flush_interval", w.flushIntervalOrDefault()),
+		log.Duration("lease_renew_interval", w.leaseRenewIntervalOrDefault()),
 	)
 
 	var loops sync.WaitGroup
-	loops.Add(3)
+	loops.Add(4)
 	go func() { defer loops.Done(); w.prefetchLoop(ctx, wh) }()
+	go func() { defer loops.Done(); w.leaseRenewLoop(ctx, wh) }()
 	go func() {
 		defer loops.Done()
 		// dispatch is the sole producer; closing results when it
@@ -538,6 +1044,19 @@ func (w *Worker) runWheelMode(ctx context.Context) error {
 		w.dispatchLoop(ctx, wh, results)
 	}()
 	go func() { defer loops.Done(); w.flushLoop(ctx, results) }()
+	if w.prewarmLead > 0 {
+		loops.Add(1)
+		go func() { defer loops.Done(); w.prewarmLoop(ctx, wh) }()
+	}
+	if w.wakeups != nil {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			w.wakeupLoop(ctx, false, func(ctx context.Context, shards []model.ShardRange) {
+				w.claimWokenIntoWheel(ctx, wh, shards)
+			})
+		}()
+	}
 	loops.Wait()
 
 	w.shutdownWheel(wh)
@@ -549,24 +1068,35 @@ func (w *Worker) runWheelMode(ctx context.Context) error {
 // it in the wheel; a follow-up prefetch deduplicates on timer ID so an
 // overlapping window can't double-fire a row.
 func (w *Worker) prefetchLoop(ctx context.Context, wh *wheel.Wheel) {
-	tick := time.NewTicker(w.tickIntervalOrDefault())
+	tick := w.clock.NewTicker(w.tickIntervalOrDefault())
 	defer tick.Stop()
 	logger := log.GetLogger(ctx)
 
 	prefetch := func() {
-		nowUTC := time.Now().UTC()
-		if err := w.mgr.WorkerSeen(ctx, w.identity, nowUTC); err != nil {
+		w.markTick()
+		nowUTC := w.clock.Now().UTC()
+		occupancy := wh.Occupancy()
+		metricWheelOccupancy.WithLabelValues(w.identity).Set(occupancy)
+		if err := w.mgr.WorkerSeen(ctx, w.identity, nowUTC, w.capacityWeightOrDefault(), occupancy); err != nil {
 			logger.Error("worker; failed to update last seen", log.Any("err", err))
 			return
 		}
-		shardLo, shardHi := w.currentShardBand(ctx, nowUTC)
+		w.markSeen(nowUTC)
+		if occupancy >= wheelBackpressureOccupancy {
+			logger.Info("worker; wheel near capacity, skipping prefetch",
+				log.Float64("occupancy", occupancy),
+				log.Int("wheel_len", wh.Len()),
+			)
+			return
+		}
+		shards := w.currentShards(ctx, nowUTC)
 		windowSeconds := int(w.prefetchWindow / time.Second)
-		leaseSeconds := windowSeconds + int(wheelLeaseSafetyMargin/time.Second)
 		// Scale the claim batch so we hit the same wall-clock throughput
 		// regardless of how wide the window is. Without the scale the
 		// batch caps at the same count we previously claimed every 5s,
 		// starving the wheel when the window is large.
-		batch := w.batchSizeOrDefault()
+		baseBatch := w.claimBatchSizeOrDefault()
+		batch := baseBatch
 		tickSeconds := int(w.tickIntervalOrDefault() / time.Second)
 		if tickSeconds < 1 {
 			tickSeconds = 1
@@ -577,6 +1107,10 @@ func (w *Worker) prefetchLoop(ctx context.Context, wh *wheel.Wheel) {
 				batch = batch * scale
 			}
 		}
+		// near the wheel's budgets, claim only what it can still take so
+		// the rest stays unclaimed for peers instead of being claimed and
+		// relinquished.
+		limit := min(batch, wh.Free())
 
 		// Intentionally NOT wrapped in retryDBWrite: the claim CTE's UPDATE
 		// input scan carries an implicit `for-update,nowait-retry` lock
@@ -586,26 +1120,20 @@ func (w *Worker) prefetchLoop(ctx context.Context, wh *wheel.Wheel) {
 		// (workers starting at different times and briefly seeing different
 		// memberships) into a livelock. If mikoshi exhausts its own retry
 		// budget, the error surfaces here and we just skip this tick.
-		timers, err := w.mgr.GetDueTimersWindowed(ctx, w.identity, nowUTC, batch, shardLo, shardHi, windowSeconds, leaseSeconds)
+		claimed, claimLatency, err := w.claimIntoWheel(ctx, wh, nowUTC, limit, shards)
 		if err != nil {
 			logger.Error("worker; failed to prefetch timers", log.Any("err", err))
 			return
 		}
-		var inserted, dropped int
-		for i := range timers {
-			if wh.Insert(&timers[i]) {
-				inserted++
-			} else {
-				dropped++
-			}
-		}
-		if inserted > 0 || dropped > 0 {
-			logger.Info("worker; wheel prefetch",
-				log.Int("inserted", inserted),
-				log.Int("dropped", dropped),
-				log.Int("wheel_len", wh.Len()),
-			)
-		}
+		// observations are in units of the base batch so the controller
+		// sees the same scale in both modes.
+		w.observeTick(tickObservation{
+			Requested:    baseBatch,
+			Claimed:      claimed * baseBatch / batch,
+			ClaimLatency: claimLatency,
+			WheelLen:     wh.Len(),
+			Window:       w.prefetchWindow,
+		})
 	}
 
 	prefetch() // run once immediately so the wheel isn't empty for the first dispatch tick.
@@ -613,28 +1141,177 @@ func (w *Worker) prefetchLoop(ctx context.Context, wh *wheel.Wheel) {
 		select {
 		case <-ctx.Done():
 			return
-		case <-tick.C:
+		case <-w.draining:
+			w.drainWheel(ctx, wh)
+			<-ctx.Done()
+			return
+		case <-tick.C():
 			prefetch()
 		}
 	}
 }
 
+// claimIntoWheel claims up to limit timers in shards due within the
+// prefetch window of nowUTC and parks them in the wheel, relinquishing
+// any it rejects. It returns how many timers were claimed.
+func (w *Worker) claimIntoWheel(ctx context.Context, wh *wheel.Wheel, nowUTC time.Time, limit int, shards []model.ShardRange) (claimed int, claimLatency time.Duration, err error) {
+	windowSeconds := int(w.prefetchWindow / time.Second)
+	leaseSeconds := windowSeconds + int(wheelLeaseSafetyMargin/time.Second)
+	claimStarted := w.clock.Now()
+	timers, err := w.mgr.GetDueTimersWindowed(ctx, w.identity, nowUTC, limit, shards, windowSeconds, leaseSeconds, w.claimOptions()...)
+	claimLatency = w.clock.Since(claimStarted)
+	metricClaimDuration.ObserveDuration(claimLatency)
+	if err != nil {
+		return 0, 0, err
+	}
+	var inserted, deferred, duplicates int
+	var rejected []model.TimerLease
+	for i := range timers {
+		switch wh.Insert(&timers[i]) {
+		case wheel.Inserted:
+			inserted++
+		case wheel.Deferred:
+			deferred++
+		case wheel.Duplicate:
+			duplicates++
+		case wheel.Rejected:
+			rejected = append(rejected, timers[i].Lease())
+		}
+	}
+	if len(rejected) > 0 {
+		w.relinquishRejected(ctx, rejected)
+	}
+	metricWheelLength.WithLabelValues(w.identity).Set(float64(wh.Len()))
+	metricWheelOverflowLength.WithLabelValues(w.identity).Set(float64(wh.OverflowLen()))
+	if inserted > 0 || deferred > 0 || duplicates > 0 || len(rejected) > 0 {
+		log.GetLogger(ctx).Info("worker; wheel prefetch",
+			log.Int("inserted", inserted),
+			log.Int("deferred", deferred),
+			log.Int("duplicates", duplicates),
+			log.Int("rejected", len(rejected)),
+			log.Int("wheel_len", wh.Len()),
+		)
+	}
+	return len(timers), claimLatency, nil
+}
+
+// leaseRenewLoop keeps the leases of timers parked in the wheel ahead of
+// the clock. The claim lease only covers the prefetch window plus a
+// safety margin, so if dispatch falls behind (a slow destination, a GC
+// pause) the leases would otherwise lapse while the timers are still
+// held and a peer would re-claim and double-fire them.
+//
+// Timers whose lease was lost are evicted from the wheel. If the renewal
+// itself fails, every timer whose lease lapses before the next attempt
+// is evicted too, since we can no longer be sure we own it by then.
+func (w *Worker) leaseRenewLoop(ctx context.Context, wh *wheel.Wheel) {
+	interval := w.leaseRenewIntervalOrDefault()
+	tick := w.clock.NewTicker(interval)
+	defer tick.Stop()
+	logger := log.GetLogger(ctx)
+
+	// leasedUntil tracks the renewed lease for each held timer; timers
+	// not yet renewed fall back to their claim-time assigned_until_utc.
+	leasedUntil := make(map[uuid.UUID]time.Time)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C():
+		}
+		held := wh.Held()
+		if len(held) == 0 {
+			clear(leasedUntil)
+			continue
+		}
+		nowUTC := w.clock.Now().UTC()
+		leases := make([]model.TimerLease, 0, len(held))
+		for _, t := range held {
+			leases = append(leases, t.Lease())
+		}
+		leaseUntil := nowUTC.Add(w.prefetchWindow + wheelLeaseSafetyMargin)
+		lost, err := w.mgr.RenewLeases(ctx, w.identity, leaseUntil, leases)
+		if err != nil {
+			lapsing := leasesLapsingBefore(held, leasedUntil, nowUTC.Add(interval))
+			logger.Error("worker; failed to renew wheel leases",
+				log.Int("held", len(held)),
+				log.Int("evicting", len(lapsing)),
+				log.Any("err", err),
+			)
+			w.evictFromWheel(ctx, wh, lapsing)
+			continue
+		}
+		renewed := make(map[uuid.UUID]time.Time, len(held))
+		for _, t := range held {
+			renewed[t.ID] = leaseUntil
+		}
+		for _, id := range lost {
+			delete(renewed, id)
+		}
+		leasedUntil = renewed
+		w.evictFromWheel(ctx, wh, lost)
+	}
+}
+
+// leasesLapsingBefore returns the ids of held timers whose lease ends
+// before the deadline, using the renewed lease where there is one and
+// the claim-time lease otherwise.
+func leasesLapsingBefore(held []*model.Timer, leasedUntil map[uuid.UUID]time.Time, deadline time.Time) (output []uuid.UUID) {
+	for _, t := range held {
+		until, ok := leasedUntil[t.ID]
+		if !ok {
+			if t.AssignedUntilUTC == nil {
+				output = append(output, t.ID)
+				continue
+			}
+			until = *t.AssignedUntilUTC
+		}
+		if until.Before(deadline) {
+			output = append(output, t.ID)
+		}
+	}
+	return
+}
+
+// evictFromWheel drops timers whose lease we no longer hold so they
+// never fire from this worker.
+func (w *Worker) evictFromWheel(ctx context.Context, wh *wheel.Wheel, ids []uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+	evicted := wh.Remove(ids...)
+	if evicted == 0 {
+		return
+	}
+	w.timersLeaseEvicted.Add(int64(evicted))
+	log.GetLogger(ctx).Warn("worker; evicted timers from wheel, leases were lost",
+		log.Int("timers", evicted),
+	)
+}
+
 // dispatchLoop drives the wheel cursor and fires hooks for every timer
 // whose slot has come due. Concurrency is bounded by parallelism so a
 // large slot can't fan out beyond the configured limit; results stream
-// onto a channel for the flush loop to batch.
+// onto a channel for the flush loop to batch. With paced dispatch the
+// slot is spread across the tick instead of fired in one burst.
 func (w *Worker) dispatchLoop(ctx context.Context, wh *wheel.Wheel, results chan<- dispatchResult) {
-	tick := time.NewTicker(w.dispatchTickIntervalOrDefault())
+	tick := w.clock.NewTicker(w.dispatchTickIntervalOrDefault())
 	defer tick.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-tick.C:
-			fired := wh.Advance(time.Now().UTC())
+		case <-tick.C():
+			fired := wh.Advance(w.clock.Now().UTC())
+			metricWheelLength.WithLabelValues(w.identity).Set(float64(wh.Len()))
+			metricWheelOverflowLength.WithLabelValues(w.identity).Set(float64(wh.OverflowLen()))
 			if len(fired) == 0 {
 				continue
 			}
+			if w.pacedDispatch {
+				w.dispatchPaced(ctx, wh, fired, results)
+				continue
+			}
 			b, _ := async.BatchContext(ctx)
 			b.SetLimit(w.parallelismOrDefault())
 			for i := range fired {
@@ -654,6 +1331,16 @@ func (w *Worker) dispatchLoop(ctx context.Context, wh *wheel.Wheel, results chan
 // write; failures and successes alike must always reach the channel
 // so a stuck flush can't silently lose attempts.
 func (w *Worker) fireOne(ctx context.Context, t *model.Timer, results chan<- dispatchResult) {
+	// The wheel can hold a timer for the full prefetch window, so the
+	// expiry check happens at fire time rather than at claim time.
+	if t.IsExpired(w.clock.Now().UTC()) {
+		select {
+		case results <- dispatchResult{Lease: t.Lease(), Expired: true}:
+		case <-ctx.Done():
+		}
+		return
+	}
+
 	var internalErr, remoteErr error
 	defer func() {
 		w.timersProcessed.Add(1)
@@ -665,7 +1352,7 @@ func (w *Worker) fireOne(ctx context.Context, t *model.Timer, results chan<- dis
 		}
 	}()
 
-	started := time.Now()
+	started := w.clock.Now()
 	var res *http.Response
 	res, remoteErr = w.makeHookRequest(t)
 	if remoteErr != nil || res.StatusCode >= http.StatusBadRequest {
@@ -676,61 +1363,68 @@ func (w *Worker) fireOne(ctx context.Context, t *model.Timer, results chan<- dis
 		if remoteErr != nil {
 			log.GetLogger(ctx).Err(fmt.Errorf("worker; failed to deliver to remote: %w", remoteErr), w.logAttrs(t,
 				log.String("err_type", "remote"),
-				log.Duration("elapsed", time.Since(started)),
+				log.Duration("elapsed", w.clock.Since(started)),
 			)...)
 		} else {
 			log.GetLogger(ctx).Err(fmt.Errorf("worker; failed to deliver to remote: non-200 status code returned %d", statusCode), w.logAttrs(t,
 				log.String("err_type", "remote"),
-				log.Duration("elapsed", time.Since(started)),
+				log.Duration("elapsed", w.clock.Since(started)),
 			)...)
 		}
 		select {
-		case results <- dispatchResult{ID: t.ID, Failed: true, StatusCode: uint32(statusCode), RemoteErr: remoteErr}:
+		case results <- dispatchResult{Lease: t.Lease(), Failed: true, StatusCode: uint32(statusCode), RemoteErr: remoteErr}:
 		case <-ctx.Done():
 		}
 		return
 	}
 	select {
-	case results <- dispatchResult{ID: t.ID, DeliveredAt: time.Now().UTC()}:
+	case results <- dispatchResult{Lease: t.Lease(), FiredAt: started.UTC(), DeliveredAt: w.clock.Now().UTC()}:
 	case <-ctx.Done():
 	}
 }
 
 // flushLoop coalesces dispatch results into batched DB writes. Success
-// rows go through BulkMarkDelivered; failures group by (status, err)
+// rows go through BulkMarkDelivered, expired rows through
+// BulkMarkExpired; failures group by (status, err)
 // so each "family" of failure costs one UPDATE per flush instead of
 // one per timer. Termination is keyed off the results channel closing
 // (dispatch loop is the sole producer) rather than ctx.Done — that
 // guarantees the very last batch's outcomes always reach the DB even
 // if shutdown races with a tick boundary.
 func (w *Worker) flushLoop(ctx context.Context, results <-chan dispatchResult) {
-	tick := time.NewTicker(w.flushIntervalOrDefault())
+	tick := w.clock.NewTicker(w.flushIntervalOrDefault())
 	defer tick.Stop()
 
-	var delivered []uuid.UUID
+	var delivered []model.TimerDelivery
+	var expired []model.TimerLease
 	type failureKey struct {
 		status uint32
 		errMsg string
 	}
-	failures := map[failureKey][]uuid.UUID{}
+	failures := map[failureKey][]model.TimerLease{}
 	logger := log.GetLogger(ctx)
 
 	flush := func() {
 		if len(delivered) > 0 {
-			w.bulkMarkDeliveredWithRetry(ctx, delivered)
+			_, _ = w.bulkMarkDeliveredWithRetry(ctx, delivered)
 			logger.Info("worker; flushed deliveries", log.Int("count", len(delivered)))
 			delivered = delivered[:0]
 		}
-		for k, ids := range failures {
-			if len(ids) == 0 {
+		if len(expired) > 0 {
+			if stale, err := w.bulkMarkExpiredWithRetry(ctx, expired); err == nil {
+				w.timersExpired.Add(int64(len(expired)) - stale)
+				logger.Info("worker; flushed expirations", log.Int("count", len(expired)))
+			}
+			expired = expired[:0]
+		}
+		for k, leases := range failures {
+			if len(leases) == 0 {
 				continue
 			}
-			err := retryDBWrite(ctx, "worker; failed to mark batch attempted", func(c context.Context) error {
-				return w.mgr.BulkMarkAttempted(c, k.status, errOrNil(k.errMsg), time.Now().UTC(), ids)
-			})
+			_, err := w.bulkMarkAttemptedWithRetry(ctx, k.status, errOrNil(k.errMsg), leases)
 			if err == nil {
 				logger.Info("worker; flushed attempts",
-					log.Int("count", len(ids)),
+					log.Int("count", len(leases)),
 					log.Int("status", int(k.status)),
 				)
 			}
@@ -745,13 +1439,15 @@ func (w *Worker) flushLoop(ctx context.Context, results <-chan dispatchResult) {
 				flush()
 				return
 			}
-			if r.Failed {
+			if r.Expired {
+				expired = append(expired, r.Lease)
+			} else if r.Failed {
 				k := failureKey{status: r.StatusCode, errMsg: errString(r.RemoteErr)}
-				failures[k] = append(failures[k], r.ID)
+				failures[k] = append(failures[k], r.Lease)
 			} else {
-				delivered = append(delivered, r.ID)
+				delivered = append(delivered, model.TimerDelivery{TimerLease: r.Lease, FiredUTC: r.FiredAt})
 			}
-		case <-tick.C:
+		case <-tick.C():
 			flush()
 		}
 	}
@@ -778,6 +1474,22 @@ type errString_synthetic struct{ msg string }
 
 func (e errString_synthetic) Error() string { return e.msg }
 
+// wheelBackpressureOccupancy is the wheel occupancy at which prefetch
+// skips its claim for the tick and lets dispatch catch up.
+const wheelBackpressureOccupancy = 0.9
+
+// relinquishRejected hands back timers the wheel had no room for as soon
+// as they're claimed, so a peer can pick them up on its next prefetch
+// rather than after the lease runs out.
+func (w *Worker) relinquishRejected(ctx context.Context, leases []model.TimerLease) {
+	if err := w.mgr.BulkRelinquish(ctx, w.identity, w.clock.Now().UTC(), leases); err != nil {
+		log.GetLogger(ctx).Error("worker; failed to relinquish timers the wheel rejected",
+			log.Int("count", len(leases)),
+			log.Any("err", err),
+		)
+	}
+}
+
 // shutdownWheel is invoked after Run's loops exit. It tries, on a fresh
 // context, to relinquish the wheel's still-claimed contents so peers
 // can pick them up immediately on their next prefetch instead of
@@ -787,15 +1499,15 @@ func (w *Worker) shutdownWheel(wh *wheel.Wheel) {
 	if len(left) == 0 {
 		return
 	}
-	ids := make([]uuid.UUID, 0, len(left))
+	leases := make([]model.TimerLease, 0, len(left))
 	for _, t := range left {
-		ids = append(ids, t.ID)
+		leases = append(leases, t.Lease())
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err := w.mgr.BulkRelinquish(ctx, w.identity, time.Now().UTC(), ids); err != nil {
+	if err := w.mgr.BulkRelinquish(ctx, w.identity, w.clock.Now().UTC(), leases); err != nil {
 		log.GetLogger(ctx).Error("worker; failed to relinquish wheel on shutdown",
-			log.Int("count", len(ids)),
+			log.Int("count", len(leases)),
 			log.Any("err", err),
 		)
 	}
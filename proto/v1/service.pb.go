@@ -2,13 +2,14 @@
 // versions:
 // 	protoc-gen-go v1.35.1
 // 	protoc        v5.28.3
-// source: proto/v1/service.proto
+// source: v1/service.proto
 
 package v1
 
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
@@ -22,20 +23,86 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ImportConflictPolicy is what to do when an imported timer's name is
+// already taken.
+type ImportConflictPolicy int32
+
+const (
+	// IMPORT_CONFLICT_FAIL aborts the import.
+	ImportConflictPolicy_IMPORT_CONFLICT_FAIL ImportConflictPolicy = 0
+	// IMPORT_CONFLICT_SKIP keeps the existing timer.
+	ImportConflictPolicy_IMPORT_CONFLICT_SKIP ImportConflictPolicy = 1
+	// IMPORT_CONFLICT_REPLACE overwrites the existing timer, resetting
+	// its delivery state.
+	ImportConflictPolicy_IMPORT_CONFLICT_REPLACE ImportConflictPolicy = 2
+)
+
+// Enum value maps for ImportConflictPolicy.
+var (
+	ImportConflictPolicy_name = map[int32]string{
+		0: "IMPORT_CONFLICT_FAIL",
+		1: "IMPORT_CONFLICT_SKIP",
+		2: "IMPORT_CONFLICT_REPLACE",
+	}
+	ImportConflictPolicy_value = map[string]int32{
+		"IMPORT_CONFLICT_FAIL":    0,
+		"IMPORT_CONFLICT_SKIP":    1,
+		"IMPORT_CONFLICT_REPLACE": 2,
+	}
+)
+
+func (x ImportConflictPolicy) Enum() *ImportConflictPolicy {
+	p := new(ImportConflictPolicy)
+	*p = x
+	return p
+}
+
+func (x ImportConflictPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImportConflictPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_service_proto_enumTypes[0].Descriptor()
+}
+
+func (ImportConflictPolicy) Type() protoreflect.EnumType {
+	return &file_v1_service_proto_enumTypes[0]
+}
+
+func (x ImportConflictPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImportConflictPolicy.Descriptor instead.
+func (ImportConflictPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{0}
+}
+
 type Timer struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id                  string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Labels              map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Priority            uint32                 `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
-	ShardKey            string                 `protobuf:"bytes,5,opt,name=shard_key,json=shardKey,proto3" json:"shard_key,omitempty"`
-	CreatedUtc          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_utc,json=createdUtc,proto3" json:"created_utc,omitempty"`
-	DueUtc              *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=due_utc,json=dueUtc,proto3" json:"due_utc,omitempty"`
-	AssignedUntilUtc    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=assigned_until_utc,json=assignedUntilUtc,proto3" json:"assigned_until_utc,omitempty"`
-	RetryUtc            *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=retry_utc,json=retryUtc,proto3" json:"retry_utc,omitempty"`
+	Id       string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string            `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Labels   map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Priority uint32            `protobuf:"varint,4,opt,name=priority,proto3" json:"priority,omitempty"`
+	ShardKey string            `protobuf:"bytes,5,opt,name=shard_key,json=shardKey,proto3" json:"shard_key,omitempty"`
+	// ordering_key makes delivery strictly sequential, in (due_utc, id)
+	// order, across timers with the same ordering_key. An ordered timer's
+	// shard is derived from its ordering key, not its shard_key, so
+	// shard_key weights and fairness don't apply to it.
+	OrderingKey      string                 `protobuf:"bytes,6,opt,name=ordering_key,json=orderingKey,proto3" json:"ordering_key,omitempty"`
+	CreatedUtc       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_utc,json=createdUtc,proto3" json:"created_utc,omitempty"`
+	DueUtc           *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=due_utc,json=dueUtc,proto3" json:"due_utc,omitempty"`
+	AssignedUntilUtc *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=assigned_until_utc,json=assignedUntilUtc,proto3" json:"assigned_until_utc,omitempty"`
+	RetryUtc         *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=retry_utc,json=retryUtc,proto3" json:"retry_utc,omitempty"`
+	// expires_utc is when delivering the timer stops being useful; a
+	// timer claimed after this point is marked expired instead of sent.
+	ExpiresUtc *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=expires_utc,json=expiresUtc,proto3" json:"expires_utc,omitempty"`
+	// stale_after is an alternative to expires_utc on create, resolved
+	// to expires_utc = due_utc + stale_after.
+	StaleAfter          *durationpb.Duration   `protobuf:"bytes,15,opt,name=stale_after,json=staleAfter,proto3" json:"stale_after,omitempty"`
 	Attempt             uint32                 `protobuf:"varint,20,opt,name=attempt,proto3" json:"attempt,omitempty"`
 	AssignedWorker      string                 `protobuf:"bytes,21,opt,name=assigned_worker,json=assignedWorker,proto3" json:"assigned_worker,omitempty"`
 	HookUrl             string                 `protobuf:"bytes,30,opt,name=hook_url,json=hookUrl,proto3" json:"hook_url,omitempty"`
@@ -45,11 +112,15 @@ type Timer struct {
 	DeliveredUtc        *timestamppb.Timestamp `protobuf:"bytes,50,opt,name=delivered_utc,json=deliveredUtc,proto3" json:"delivered_utc,omitempty"`
 	DeliveredStatusCode uint32                 `protobuf:"varint,51,opt,name=delivered_status_code,json=deliveredStatusCode,proto3" json:"delivered_status_code,omitempty"`
 	DeliveredErr        string                 `protobuf:"bytes,52,opt,name=delivered_err,json=deliveredErr,proto3" json:"delivered_err,omitempty"`
+	ExpiredUtc          *timestamppb.Timestamp `protobuf:"bytes,53,opt,name=expired_utc,json=expiredUtc,proto3" json:"expired_utc,omitempty"`
+	// fired_utc is when the successful hook request was sent; the
+	// delivery's lateness is fired_utc - due_utc.
+	FiredUtc *timestamppb.Timestamp `protobuf:"bytes,54,opt,name=fired_utc,json=firedUtc,proto3" json:"fired_utc,omitempty"`
 }
 
 func (x *Timer) Reset() {
 	*x = Timer{}
-	mi := &file_proto_v1_service_proto_msgTypes[0]
+	mi := &file_v1_service_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -61,7 +132,7 @@ func (x *Timer) String() string {
 func (*Timer) ProtoMessage() {}
 
 func (x *Timer) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[0]
+	mi := &file_v1_service_proto_msgTypes[0]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -74,7 +145,7 @@ func (x *Timer) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Timer.ProtoReflect.Descriptor instead.
 func (*Timer) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{0}
+	return file_v1_service_proto_rawDescGZIP(), []int{0}
 }
 
 func (x *Timer) GetId() string {
@@ -112,6 +183,13 @@ func (x *Timer) GetShardKey() string {
 	return ""
 }
 
+func (x *Timer) GetOrderingKey() string {
+	if x != nil {
+		return x.OrderingKey
+	}
+	return ""
+}
+
 func (x *Timer) GetCreatedUtc() *timestamppb.Timestamp {
 	if x != nil {
 		return x.CreatedUtc
@@ -140,6 +218,20 @@ func (x *Timer) GetRetryUtc() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Timer) GetExpiresUtc() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresUtc
+	}
+	return nil
+}
+
+func (x *Timer) GetStaleAfter() *durationpb.Duration {
+	if x != nil {
+		return x.StaleAfter
+	}
+	return nil
+}
+
 func (x *Timer) GetAttempt() uint32 {
 	if x != nil {
 		return x.Attempt
@@ -203,6 +295,20 @@ func (x *Timer) GetDeliveredErr() string {
 	return ""
 }
 
+func (x *Timer) GetExpiredUtc() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiredUtc
+	}
+	return nil
+}
+
+func (x *Timer) GetFiredUtc() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FiredUtc
+	}
+	return nil
+}
+
 type GetTimerArgs struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -214,7 +320,7 @@ type GetTimerArgs struct {
 
 func (x *GetTimerArgs) Reset() {
 	*x = GetTimerArgs{}
-	mi := &file_proto_v1_service_proto_msgTypes[1]
+	mi := &file_v1_service_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -226,7 +332,7 @@ func (x *GetTimerArgs) String() string {
 func (*GetTimerArgs) ProtoMessage() {}
 
 func (x *GetTimerArgs) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[1]
+	mi := &file_v1_service_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -239,7 +345,7 @@ func (x *GetTimerArgs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTimerArgs.ProtoReflect.Descriptor instead.
 func (*GetTimerArgs) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{1}
+	return file_v1_service_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *GetTimerArgs) GetId() string {
@@ -268,7 +374,7 @@ type ListTimersArgs struct {
 
 func (x *ListTimersArgs) Reset() {
 	*x = ListTimersArgs{}
-	mi := &file_proto_v1_service_proto_msgTypes[2]
+	mi := &file_v1_service_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -280,7 +386,7 @@ func (x *ListTimersArgs) String() string {
 func (*ListTimersArgs) ProtoMessage() {}
 
 func (x *ListTimersArgs) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[2]
+	mi := &file_v1_service_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -293,7 +399,7 @@ func (x *ListTimersArgs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTimersArgs.ProtoReflect.Descriptor instead.
 func (*ListTimersArgs) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{2}
+	return file_v1_service_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ListTimersArgs) GetAfter() *timestamppb.Timestamp {
@@ -328,7 +434,7 @@ type DeleteTimerArgs struct {
 
 func (x *DeleteTimerArgs) Reset() {
 	*x = DeleteTimerArgs{}
-	mi := &file_proto_v1_service_proto_msgTypes[3]
+	mi := &file_v1_service_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -340,7 +446,7 @@ func (x *DeleteTimerArgs) String() string {
 func (*DeleteTimerArgs) ProtoMessage() {}
 
 func (x *DeleteTimerArgs) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[3]
+	mi := &file_v1_service_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -353,7 +459,7 @@ func (x *DeleteTimerArgs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteTimerArgs.ProtoReflect.Descriptor instead.
 func (*DeleteTimerArgs) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{3}
+	return file_v1_service_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *DeleteTimerArgs) GetId() string {
@@ -382,7 +488,7 @@ type DeleteTimersArgs struct {
 
 func (x *DeleteTimersArgs) Reset() {
 	*x = DeleteTimersArgs{}
-	mi := &file_proto_v1_service_proto_msgTypes[4]
+	mi := &file_v1_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -394,7 +500,7 @@ func (x *DeleteTimersArgs) String() string {
 func (*DeleteTimersArgs) ProtoMessage() {}
 
 func (x *DeleteTimersArgs) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[4]
+	mi := &file_v1_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -407,7 +513,7 @@ func (x *DeleteTimersArgs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteTimersArgs.ProtoReflect.Descriptor instead.
 func (*DeleteTimersArgs) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{4}
+	return file_v1_service_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *DeleteTimersArgs) GetAfter() *timestamppb.Timestamp {
@@ -431,6 +537,534 @@ func (x *DeleteTimersArgs) GetMatchLabels() map[string]string {
 	return nil
 }
 
+type ExportTimersArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	After    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=after,proto3" json:"after,omitempty"`
+	Before   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+	Selector string                 `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`
+}
+
+func (x *ExportTimersArgs) Reset() {
+	*x = ExportTimersArgs{}
+	mi := &file_v1_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTimersArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTimersArgs) ProtoMessage() {}
+
+func (x *ExportTimersArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTimersArgs.ProtoReflect.Descriptor instead.
+func (*ExportTimersArgs) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExportTimersArgs) GetAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *ExportTimersArgs) GetBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *ExportTimersArgs) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+type ImportTimerArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Timer      *Timer               `protobuf:"bytes,1,opt,name=timer,proto3" json:"timer,omitempty"`
+	OnConflict ImportConflictPolicy `protobuf:"varint,2,opt,name=on_conflict,json=onConflict,proto3,enum=v1.ImportConflictPolicy" json:"on_conflict,omitempty"`
+}
+
+func (x *ImportTimerArgs) Reset() {
+	*x = ImportTimerArgs{}
+	mi := &file_v1_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTimerArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTimerArgs) ProtoMessage() {}
+
+func (x *ImportTimerArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTimerArgs.ProtoReflect.Descriptor instead.
+func (*ImportTimerArgs) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ImportTimerArgs) GetTimer() *Timer {
+	if x != nil {
+		return x.Timer
+	}
+	return nil
+}
+
+func (x *ImportTimerArgs) GetOnConflict() ImportConflictPolicy {
+	if x != nil {
+		return x.OnConflict
+	}
+	return ImportConflictPolicy_IMPORT_CONFLICT_FAIL
+}
+
+type ImportTimersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Created  uint32 `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Skipped  uint32 `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Replaced uint32 `protobuf:"varint,3,opt,name=replaced,proto3" json:"replaced,omitempty"`
+}
+
+func (x *ImportTimersResponse) Reset() {
+	*x = ImportTimersResponse{}
+	mi := &file_v1_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTimersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTimersResponse) ProtoMessage() {}
+
+func (x *ImportTimersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTimersResponse.ProtoReflect.Descriptor instead.
+func (*ImportTimersResponse) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ImportTimersResponse) GetCreated() uint32 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportTimersResponse) GetSkipped() uint32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ImportTimersResponse) GetReplaced() uint32 {
+	if x != nil {
+		return x.Replaced
+	}
+	return 0
+}
+
+type ReplayTimersArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Selector        string                 `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	DeliveredAfter  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=delivered_after,json=deliveredAfter,proto3" json:"delivered_after,omitempty"`
+	DeliveredBefore *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=delivered_before,json=deliveredBefore,proto3" json:"delivered_before,omitempty"`
+	// Types that are assignable to Schedule:
+	//	*ReplayTimersArgs_DueUtc
+	//	*ReplayTimersArgs_Spread
+	Schedule isReplayTimersArgs_Schedule `protobuf_oneof:"schedule"`
+}
+
+func (x *ReplayTimersArgs) Reset() {
+	*x = ReplayTimersArgs{}
+	mi := &file_v1_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayTimersArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayTimersArgs) ProtoMessage() {}
+
+func (x *ReplayTimersArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayTimersArgs.ProtoReflect.Descriptor instead.
+func (*ReplayTimersArgs) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReplayTimersArgs) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *ReplayTimersArgs) GetDeliveredAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAfter
+	}
+	return nil
+}
+
+func (x *ReplayTimersArgs) GetDeliveredBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredBefore
+	}
+	return nil
+}
+
+func (m *ReplayTimersArgs) GetSchedule() isReplayTimersArgs_Schedule {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
+}
+
+func (x *ReplayTimersArgs) GetDueUtc() *timestamppb.Timestamp {
+	if x, ok := x.GetSchedule().(*ReplayTimersArgs_DueUtc); ok {
+		return x.DueUtc
+	}
+	return nil
+}
+
+func (x *ReplayTimersArgs) GetSpread() *durationpb.Duration {
+	if x, ok := x.GetSchedule().(*ReplayTimersArgs_Spread); ok {
+		return x.Spread
+	}
+	return nil
+}
+
+type isReplayTimersArgs_Schedule interface {
+	isReplayTimersArgs_Schedule()
+}
+
+type ReplayTimersArgs_DueUtc struct {
+	// due_utc is when every replayed timer is due; unset means now.
+	DueUtc *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=due_utc,json=dueUtc,proto3,oneof"`
+}
+
+type ReplayTimersArgs_Spread struct {
+	// spread evenly staggers the replays, in their original delivery
+	// order, from now until now + spread.
+	Spread *durationpb.Duration `protobuf:"bytes,5,opt,name=spread,proto3,oneof"`
+}
+
+func (*ReplayTimersArgs_DueUtc) isReplayTimersArgs_Schedule() {}
+
+func (*ReplayTimersArgs_Spread) isReplayTimersArgs_Schedule() {}
+
+type ReplayTimersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// replay_id is the suffix added to the replayed timer names.
+	ReplayId string `protobuf:"bytes,1,opt,name=replay_id,json=replayId,proto3" json:"replay_id,omitempty"`
+	Replayed uint32 `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`
+}
+
+func (x *ReplayTimersResponse) Reset() {
+	*x = ReplayTimersResponse{}
+	mi := &file_v1_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayTimersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayTimersResponse) ProtoMessage() {}
+
+func (x *ReplayTimersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayTimersResponse.ProtoReflect.Descriptor instead.
+func (*ReplayTimersResponse) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReplayTimersResponse) GetReplayId() string {
+	if x != nil {
+		return x.ReplayId
+	}
+	return ""
+}
+
+func (x *ReplayTimersResponse) GetReplayed() uint32 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+type GetDeliveryStatsArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// selector matches the `shard_key` label of each rollup.
+	Selector string                 `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	After    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	Before   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=before,proto3" json:"before,omitempty"`
+}
+
+func (x *GetDeliveryStatsArgs) Reset() {
+	*x = GetDeliveryStatsArgs{}
+	mi := &file_v1_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatsArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatsArgs) ProtoMessage() {}
+
+func (x *GetDeliveryStatsArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatsArgs.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatsArgs) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetDeliveryStatsArgs) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *GetDeliveryStatsArgs) GetAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *GetDeliveryStatsArgs) GetBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+// DeliveryStats summarizes the lateness of the deliveries of one shard
+// key fired within one minute.
+type DeliveryStats struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BucketUtc   *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=bucket_utc,json=bucketUtc,proto3" json:"bucket_utc,omitempty"`
+	ShardKey    string                 `protobuf:"bytes,2,opt,name=shard_key,json=shardKey,proto3" json:"shard_key,omitempty"`
+	Count       uint64                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	LatenessP50 *durationpb.Duration   `protobuf:"bytes,4,opt,name=lateness_p50,json=latenessP50,proto3" json:"lateness_p50,omitempty"`
+	LatenessP99 *durationpb.Duration   `protobuf:"bytes,5,opt,name=lateness_p99,json=latenessP99,proto3" json:"lateness_p99,omitempty"`
+	LatenessMax *durationpb.Duration   `protobuf:"bytes,6,opt,name=lateness_max,json=latenessMax,proto3" json:"lateness_max,omitempty"`
+}
+
+func (x *DeliveryStats) Reset() {
+	*x = DeliveryStats{}
+	mi := &file_v1_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryStats) ProtoMessage() {}
+
+func (x *DeliveryStats) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryStats.ProtoReflect.Descriptor instead.
+func (*DeliveryStats) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeliveryStats) GetBucketUtc() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BucketUtc
+	}
+	return nil
+}
+
+func (x *DeliveryStats) GetShardKey() string {
+	if x != nil {
+		return x.ShardKey
+	}
+	return ""
+}
+
+func (x *DeliveryStats) GetCount() uint64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetLatenessP50() *durationpb.Duration {
+	if x != nil {
+		return x.LatenessP50
+	}
+	return nil
+}
+
+func (x *DeliveryStats) GetLatenessP99() *durationpb.Duration {
+	if x != nil {
+		return x.LatenessP99
+	}
+	return nil
+}
+
+func (x *DeliveryStats) GetLatenessMax() *durationpb.Duration {
+	if x != nil {
+		return x.LatenessMax
+	}
+	return nil
+}
+
+type GetDeliveryStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stats []*DeliveryStats `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+}
+
+func (x *GetDeliveryStatsResponse) Reset() {
+	*x = GetDeliveryStatsResponse{}
+	mi := &file_v1_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatsResponse) ProtoMessage() {}
+
+func (x *GetDeliveryStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetDeliveryStatsResponse) GetStats() []*DeliveryStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
 type ListTimersResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -441,7 +1075,7 @@ type ListTimersResponse struct {
 
 func (x *ListTimersResponse) Reset() {
 	*x = ListTimersResponse{}
-	mi := &file_proto_v1_service_proto_msgTypes[5]
+	mi := &file_v1_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -453,7 +1087,7 @@ func (x *ListTimersResponse) String() string {
 func (*ListTimersResponse) ProtoMessage() {}
 
 func (x *ListTimersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[5]
+	mi := &file_v1_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,7 +1100,7 @@ func (x *ListTimersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListTimersResponse.ProtoReflect.Descriptor instead.
 func (*ListTimersResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{5}
+	return file_v1_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListTimersResponse) GetTimers() []*Timer {
@@ -486,7 +1120,7 @@ type IdentifierResponse struct {
 
 func (x *IdentifierResponse) Reset() {
 	*x = IdentifierResponse{}
-	mi := &file_proto_v1_service_proto_msgTypes[6]
+	mi := &file_v1_service_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -498,7 +1132,7 @@ func (x *IdentifierResponse) String() string {
 func (*IdentifierResponse) ProtoMessage() {}
 
 func (x *IdentifierResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[6]
+	mi := &file_v1_service_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,7 +1145,7 @@ func (x *IdentifierResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IdentifierResponse.ProtoReflect.Descriptor instead.
 func (*IdentifierResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{6}
+	return file_v1_service_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *IdentifierResponse) GetId() string {
@@ -526,14 +1160,18 @@ type Worker struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Hostname    string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	CreatedUtc  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_utc,json=createdUtc,proto3" json:"created_utc,omitempty"`
-	LastSeenUtc *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_seen_utc,json=lastSeenUtc,proto3" json:"last_seen_utc,omitempty"`
+	Hostname       string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	CreatedUtc     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_utc,json=createdUtc,proto3" json:"created_utc,omitempty"`
+	LastSeenUtc    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_seen_utc,json=lastSeenUtc,proto3" json:"last_seen_utc,omitempty"`
+	CapacityWeight int64                  `protobuf:"varint,4,opt,name=capacity_weight,json=capacityWeight,proto3" json:"capacity_weight,omitempty"`
+	// wheel_occupancy is how full the worker's wheel is against its
+	// budgets, from 0 to 1.
+	WheelOccupancy float64 `protobuf:"fixed64,5,opt,name=wheel_occupancy,json=wheelOccupancy,proto3" json:"wheel_occupancy,omitempty"`
 }
 
 func (x *Worker) Reset() {
 	*x = Worker{}
-	mi := &file_proto_v1_service_proto_msgTypes[7]
+	mi := &file_v1_service_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -545,7 +1183,7 @@ func (x *Worker) String() string {
 func (*Worker) ProtoMessage() {}
 
 func (x *Worker) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[7]
+	mi := &file_v1_service_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -558,7 +1196,7 @@ func (x *Worker) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Worker.ProtoReflect.Descriptor instead.
 func (*Worker) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{7}
+	return file_v1_service_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *Worker) GetHostname() string {
@@ -582,6 +1220,20 @@ func (x *Worker) GetLastSeenUtc() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Worker) GetCapacityWeight() int64 {
+	if x != nil {
+		return x.CapacityWeight
+	}
+	return 0
+}
+
+func (x *Worker) GetWheelOccupancy() float64 {
+	if x != nil {
+		return x.WheelOccupancy
+	}
+	return 0
+}
+
 type ListWorkersArgs struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -592,7 +1244,7 @@ type ListWorkersArgs struct {
 
 func (x *ListWorkersArgs) Reset() {
 	*x = ListWorkersArgs{}
-	mi := &file_proto_v1_service_proto_msgTypes[8]
+	mi := &file_v1_service_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -604,7 +1256,7 @@ func (x *ListWorkersArgs) String() string {
 func (*ListWorkersArgs) ProtoMessage() {}
 
 func (x *ListWorkersArgs) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[8]
+	mi := &file_v1_service_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -617,7 +1269,7 @@ func (x *ListWorkersArgs) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListWorkersArgs.ProtoReflect.Descriptor instead.
 func (*ListWorkersArgs) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{8}
+	return file_v1_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ListWorkersArgs) GetLastSeenAfter() *timestamppb.Timestamp {
@@ -637,7 +1289,7 @@ type ListWorkersResponse struct {
 
 func (x *ListWorkersResponse) Reset() {
 	*x = ListWorkersResponse{}
-	mi := &file_proto_v1_service_proto_msgTypes[9]
+	mi := &file_v1_service_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -649,7 +1301,7 @@ func (x *ListWorkersResponse) String() string {
 func (*ListWorkersResponse) ProtoMessage() {}
 
 func (x *ListWorkersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_service_proto_msgTypes[9]
+	mi := &file_v1_service_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -662,7 +1314,7 @@ func (x *ListWorkersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListWorkersResponse.ProtoReflect.Descriptor instead.
 func (*ListWorkersResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_service_proto_rawDescGZIP(), []int{9}
+	return file_v1_service_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *ListWorkersResponse) GetWorkers() []*Worker {
@@ -672,87 +1324,220 @@ func (x *ListWorkersResponse) GetWorkers() []*Worker {
 	return nil
 }
 
-var File_proto_v1_service_proto protoreflect.FileDescriptor
-
-var file_proto_v1_service_proto_rawDesc = []byte{
-	0x0a, 0x16, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65,
-	0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf8, 0x06, 0x0a, 0x05, 0x54,
-	0x69, 0x6d, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65,
-	0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69,
-	0x6d, 0x65, 0x72, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
-	0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72,
-	0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72,
-	0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x6b, 0x65, 0x79,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x4b, 0x65, 0x79,
-	0x12, 0x3b, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x63, 0x18,
-	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+type WatchWakeupsArgs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hostname string `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+}
+
+func (x *WatchWakeupsArgs) Reset() {
+	*x = WatchWakeupsArgs{}
+	mi := &file_v1_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchWakeupsArgs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchWakeupsArgs) ProtoMessage() {}
+
+func (x *WatchWakeupsArgs) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchWakeupsArgs.ProtoReflect.Descriptor instead.
+func (*WatchWakeupsArgs) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchWakeupsArgs) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+type Wakeup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Shard  uint32                 `protobuf:"varint,1,opt,name=shard,proto3" json:"shard,omitempty"`
+	DueUtc *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=due_utc,json=dueUtc,proto3" json:"due_utc,omitempty"`
+}
+
+func (x *Wakeup) Reset() {
+	*x = Wakeup{}
+	mi := &file_v1_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Wakeup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Wakeup) ProtoMessage() {}
+
+func (x *Wakeup) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Wakeup.ProtoReflect.Descriptor instead.
+func (*Wakeup) Descriptor() ([]byte, []int) {
+	return file_v1_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Wakeup) GetShard() uint32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+func (x *Wakeup) GetDueUtc() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DueUtc
+	}
+	return nil
+}
+
+var File_v1_service_proto protoreflect.FileDescriptor
+
+var file_v1_service_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x76, 0x31, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x02, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8a, 0x09, 0x0a, 0x05, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a,
+	0x09, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x72,
+	0x64, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x12, 0x3b, 0x0a,
+	0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x55, 0x74, 0x63, 0x12, 0x33, 0x0a, 0x07, 0x64, 0x75,
+	0x65, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x64, 0x75, 0x65, 0x55, 0x74, 0x63, 0x12,
+	0x48, 0x0a, 0x12, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x74, 0x69,
+	0x6c, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x10, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x55, 0x74, 0x63, 0x12, 0x37, 0x0a, 0x09, 0x72, 0x65, 0x74,
+	0x72, 0x79, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x72, 0x65, 0x74, 0x72, 0x79, 0x55,
+	0x74, 0x63, 0x12, 0x3b, 0x0a, 0x0b, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x75, 0x74,
+	0x63, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x55, 0x74, 0x63, 0x12,
+	0x3a, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0a, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x61,
+	0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x61, 0x74,
+	0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x5f, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x19,
+	0x0a, 0x08, 0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x68, 0x6f, 0x6f, 0x6b, 0x55, 0x72, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x6f, 0x6f,
+	0x6b, 0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x29, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x68, 0x6f, 0x6f, 0x6b, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x3d, 0x0a, 0x0c, 0x68, 0x6f,
+	0x6f, 0x6b, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x2a, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x48, 0x6f, 0x6f, 0x6b,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x68, 0x6f,
+	0x6f, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x6f,
+	0x6b, 0x5f, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x2b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x68, 0x6f,
+	0x6f, 0x6b, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x3f, 0x0a, 0x0d, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65,
+	0x72, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x32, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x65, 0x64, 0x55, 0x74, 0x63, 0x12, 0x32, 0x0a, 0x15, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x65, 0x64, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x18, 0x33, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x64,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x65, 0x72, 0x72, 0x18, 0x34, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x45, 0x72, 0x72,
+	0x12, 0x3b, 0x0a, 0x0b, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x63, 0x18,
+	0x35, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x55, 0x74, 0x63, 0x12, 0x33, 0x0a,
-	0x07, 0x64, 0x75, 0x65, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x64, 0x75, 0x65, 0x55,
-	0x74, 0x63, 0x12, 0x48, 0x0a, 0x12, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x75,
-	0x6e, 0x74, 0x69, 0x6c, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x10, 0x61, 0x73, 0x73, 0x69,
-	0x67, 0x6e, 0x65, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x55, 0x74, 0x63, 0x12, 0x37, 0x0a, 0x09,
-	0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x72, 0x65, 0x74,
-	0x72, 0x79, 0x55, 0x74, 0x63, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74,
-	0x18, 0x14, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12,
-	0x27, 0x0a, 0x0f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x77, 0x6f, 0x72, 0x6b,
-	0x65, 0x72, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e,
-	0x65, 0x64, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x19, 0x0a, 0x08, 0x68, 0x6f, 0x6f, 0x6b,
-	0x5f, 0x75, 0x72, 0x6c, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x68, 0x6f, 0x6f, 0x6b,
-	0x55, 0x72, 0x6c, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x6d, 0x65, 0x74, 0x68,
-	0x6f, 0x64, 0x18, 0x29, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x68, 0x6f, 0x6f, 0x6b, 0x4d, 0x65,
-	0x74, 0x68, 0x6f, 0x64, 0x12, 0x3d, 0x0a, 0x0c, 0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x68, 0x65, 0x61,
-	0x64, 0x65, 0x72, 0x73, 0x18, 0x2a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x76, 0x31, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x72, 0x2e, 0x48, 0x6f, 0x6f, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
-	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x68, 0x6f, 0x6f, 0x6b, 0x48, 0x65, 0x61, 0x64,
-	0x65, 0x72, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x62, 0x6f, 0x64, 0x79,
-	0x18, 0x2b, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x68, 0x6f, 0x6f, 0x6b, 0x42, 0x6f, 0x64, 0x79,
-	0x12, 0x3f, 0x0a, 0x0d, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x75, 0x74,
-	0x63, 0x18, 0x32, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x70, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x55, 0x74, 0x63, 0x12, 0x37, 0x0a,
+	0x09, 0x66, 0x69, 0x72, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x36, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x66, 0x69,
+	0x72, 0x65, 0x64, 0x55, 0x74, 0x63, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x48, 0x6f, 0x6f, 0x6b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x32, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x41, 0x72, 0x67,
+	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x92, 0x01, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69,
+	0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x52, 0x0c, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x55, 0x74,
-	0x63, 0x12, 0x32, 0x0a, 0x15, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x73,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x33, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x13, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
-	0x65, 0x64, 0x5f, 0x65, 0x72, 0x72, 0x18, 0x34, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x64, 0x65,
-	0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x45, 0x72, 0x72, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61,
+	0x61, 0x6d, 0x70, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x06, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x35, 0x0a, 0x0f, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x22, 0x81, 0x02, 0x0a, 0x10, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x32, 0x0a, 0x06, 0x62, 0x65, 0x66, 0x6f,
+	0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x47, 0x0a, 0x0b,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x25, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x61,
 	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
 	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x48, 0x6f, 0x6f, 0x6b, 0x48, 0x65, 0x61,
-	0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x32, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65,
-	0x72, 0x41, 0x72, 0x67, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x92, 0x01, 0x0a, 0x0e, 0x4c, 0x69,
-	0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12, 0x30, 0x0a, 0x05,
-	0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
-	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x32,
-	0x0a, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x62, 0x65, 0x66, 0x6f,
-	0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x35,
-	0x0a, 0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x41, 0x72, 0x67,
-	0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x81, 0x02, 0x0a, 0x10, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x94, 0x01, 0x0a, 0x10, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74,
 	0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x61, 0x66,
 	0x74, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
 	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
@@ -760,155 +1545,308 @@ var file_proto_v1_service_proto_rawDesc = []byte{
 	0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
 	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
 	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65,
-	0x12, 0x47, 0x0a, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18,
-	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x2e, 0x4d, 0x61, 0x74, 0x63,
-	0x68, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x6d, 0x61,
-	0x74, 0x63, 0x68, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x4d, 0x61, 0x74,
-	0x63, 0x68, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x37, 0x0a, 0x12, 0x4c, 0x69, 0x73,
-	0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x21, 0x0a, 0x06, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x09, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52, 0x06, 0x74, 0x69, 0x6d, 0x65,
-	0x72, 0x73, 0x22, 0x24, 0x0a, 0x12, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xa1, 0x01, 0x0a, 0x06, 0x57, 0x6f, 0x72,
-	0x6b, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x3b, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x52, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x55, 0x74, 0x63, 0x12, 0x3e, 0x0a, 0x0d,
-	0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x03, 0x20,
+	0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x22, 0x6d, 0x0a, 0x0f,
+	0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x12,
+	0x1f, 0x0a, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x52, 0x05, 0x74, 0x69, 0x6d, 0x65, 0x72,
+	0x12, 0x39, 0x0a, 0x0b, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72,
+	0x74, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52,
+	0x0a, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x22, 0x66, 0x0a, 0x14, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
+	0x73, 0x6b, 0x69, 0x70, 0x70, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x64, 0x22, 0xb2, 0x02, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x54, 0x69,
+	0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x12, 0x43, 0x0a, 0x0f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65,
+	0x64, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x64, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x45, 0x0a, 0x10, 0x64, 0x65, 0x6c,
+	0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52,
-	0x0b, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x55, 0x74, 0x63, 0x22, 0x55, 0x0a, 0x0f,
-	0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12,
-	0x42, 0x0a, 0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x61, 0x66, 0x74,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x41, 0x66,
-	0x74, 0x65, 0x72, 0x22, 0x3b, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65,
-	0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x07, 0x77, 0x6f,
-	0x72, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x76, 0x31,
-	0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73,
-	0x32, 0xa1, 0x02, 0x0a, 0x06, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x32, 0x0a, 0x0b, 0x43,
-	0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x09, 0x2e, 0x76, 0x31, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x72, 0x1a, 0x16, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74,
-	0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x3a, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x12, 0x2e,
-	0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67,
-	0x73, 0x1a, 0x16, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72,
-	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x29, 0x0a, 0x08, 0x47,
-	0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
-	0x54, 0x69, 0x6d, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x54,
-	0x69, 0x6d, 0x65, 0x72, 0x22, 0x00, 0x12, 0x3c, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x22, 0x00, 0x12, 0x3e, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69,
-	0x6d, 0x65, 0x72, 0x73, 0x12, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
-	0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
-	0x74, 0x79, 0x22, 0x00, 0x32, 0x48, 0x0a, 0x07, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12,
-	0x3d, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x13,
-	0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x41,
-	0x72, 0x67, 0x73, 0x1a, 0x17, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72,
-	0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x13,
-	0x5a, 0x11, 0x73, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73,
-	0x2f, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x0f, 0x64, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65,
+	0x12, 0x35, 0x0a, 0x07, 0x64, 0x75, 0x65, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x48, 0x00, 0x52,
+	0x06, 0x64, 0x75, 0x65, 0x55, 0x74, 0x63, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x70, 0x72, 0x65, 0x61,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x48, 0x00, 0x52, 0x06, 0x73, 0x70, 0x72, 0x65, 0x61, 0x64, 0x42, 0x0a, 0x0a, 0x08,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x22, 0x4f, 0x0a, 0x14, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49, 0x64, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x22, 0x98, 0x01, 0x0a, 0x14, 0x47, 0x65,
+	0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x73, 0x41, 0x72,
+	0x67, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x30,
+	0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72,
+	0x12, 0x32, 0x0a, 0x06, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x22, 0xb7, 0x02, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72,
+	0x79, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74,
+	0x5f, 0x75, 0x74, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x55, 0x74,
+	0x63, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x68, 0x61, 0x72, 0x64, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x68, 0x61, 0x72, 0x64, 0x4b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73,
+	0x5f, 0x70, 0x35, 0x30, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x50,
+	0x35, 0x30, 0x12, 0x3c, 0x0a, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x5f, 0x70,
+	0x39, 0x39, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x50, 0x39, 0x39,
+	0x12, 0x3c, 0x0a, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x5f, 0x6d, 0x61, 0x78,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x4d, 0x61, 0x78, 0x22, 0x43,
+	0x0a, 0x18, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x27, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x22, 0x37, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x21, 0x0a, 0x06, 0x74, 0x69, 0x6d,
+	0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x72, 0x52, 0x06, 0x74, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x22, 0x24, 0x0a, 0x12,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0xf3, 0x01, 0x0a, 0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x55, 0x74, 0x63, 0x12, 0x3e, 0x0a, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x73,
+	0x65, 0x65, 0x6e, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x6c, 0x61, 0x73, 0x74, 0x53,
+	0x65, 0x65, 0x6e, 0x55, 0x74, 0x63, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0e, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
+	0x27, 0x0a, 0x0f, 0x77, 0x68, 0x65, 0x65, 0x6c, 0x5f, 0x6f, 0x63, 0x63, 0x75, 0x70, 0x61, 0x6e,
+	0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x77, 0x68, 0x65, 0x65, 0x6c, 0x4f,
+	0x63, 0x63, 0x75, 0x70, 0x61, 0x6e, 0x63, 0x79, 0x22, 0x55, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74,
+	0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x12, 0x42, 0x0a, 0x0f, 0x6c,
+	0x61, 0x73, 0x74, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22,
+	0x3b, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x6f, 0x72,
+	0x6b, 0x65, 0x72, 0x52, 0x07, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x22, 0x2e, 0x0a, 0x10,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x57, 0x61, 0x6b, 0x65, 0x75, 0x70, 0x73, 0x41, 0x72, 0x67, 0x73,
+	0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x53, 0x0a, 0x06,
+	0x57, 0x61, 0x6b, 0x65, 0x75, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x68, 0x61, 0x72, 0x64, 0x12, 0x33, 0x0a, 0x07,
+	0x64, 0x75, 0x65, 0x5f, 0x75, 0x74, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x06, 0x64, 0x75, 0x65, 0x55, 0x74,
+	0x63, 0x2a, 0x67, 0x0a, 0x14, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x6c,
+	0x69, 0x63, 0x74, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x14, 0x49, 0x4d, 0x50,
+	0x4f, 0x52, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x4c, 0x49, 0x43, 0x54, 0x5f, 0x46, 0x41, 0x49,
+	0x4c, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x49, 0x4d, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x43, 0x4f,
+	0x4e, 0x46, 0x4c, 0x49, 0x43, 0x54, 0x5f, 0x53, 0x4b, 0x49, 0x50, 0x10, 0x01, 0x12, 0x1b, 0x0a,
+	0x17, 0x49, 0x4d, 0x50, 0x4f, 0x52, 0x54, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x4c, 0x49, 0x43, 0x54,
+	0x5f, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x43, 0x45, 0x10, 0x02, 0x32, 0xa9, 0x04, 0x0a, 0x06, 0x54,
+	0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x32, 0x0a, 0x0b, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x72, 0x12, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x1a,
+	0x16, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3a, 0x0a, 0x0a, 0x4c, 0x69, 0x73,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x16, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x29, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65,
+	0x72, 0x12, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x41,
+	0x72, 0x67, 0x73, 0x1a, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x22, 0x00,
+	0x12, 0x3c, 0x0a, 0x0b, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12,
+	0x13, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72,
+	0x41, 0x72, 0x67, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x3e,
+	0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x14,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73,
+	0x41, 0x72, 0x67, 0x73, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x33,
+	0x0a, 0x0c, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x14,
+	0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73,
+	0x41, 0x72, 0x67, 0x73, 0x1a, 0x09, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x41, 0x0a, 0x0c, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x54, 0x69, 0x6d,
+	0x65, 0x72, 0x73, 0x12, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x72, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d,
+	0x70, 0x6f, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x40, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79,
+	0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x12, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c,
+	0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x18, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x44,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x18, 0x2e, 0x76,
+	0x31, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x1c, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x44,
+	0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x79, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x48, 0x0a, 0x07, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x73, 0x12, 0x3d, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73,
+	0x12, 0x13, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72,
+	0x73, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x17, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x57,
+	0x6f, 0x72, 0x6b, 0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
+	0x32, 0x38, 0x0a, 0x07, 0x57, 0x61, 0x6b, 0x65, 0x75, 0x70, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x57,
+	0x61, 0x74, 0x63, 0x68, 0x12, 0x14, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x57,
+	0x61, 0x6b, 0x65, 0x75, 0x70, 0x73, 0x41, 0x72, 0x67, 0x73, 0x1a, 0x0a, 0x2e, 0x76, 0x31, 0x2e,
+	0x57, 0x61, 0x6b, 0x65, 0x75, 0x70, 0x22, 0x00, 0x30, 0x01, 0x42, 0x13, 0x5a, 0x11, 0x73, 0x61,
+	0x6e, 0x64, 0x6d, 0x61, 0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x73, 0x2f, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
-	file_proto_v1_service_proto_rawDescOnce sync.Once
-	file_proto_v1_service_proto_rawDescData = file_proto_v1_service_proto_rawDesc
+	file_v1_service_proto_rawDescOnce sync.Once
+	file_v1_service_proto_rawDescData = file_v1_service_proto_rawDesc
 )
 
-func file_proto_v1_service_proto_rawDescGZIP() []byte {
-	file_proto_v1_service_proto_rawDescOnce.Do(func() {
-		file_proto_v1_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_v1_service_proto_rawDescData)
+func file_v1_service_proto_rawDescGZIP() []byte {
+	file_v1_service_proto_rawDescOnce.Do(func() {
+		file_v1_service_proto_rawDescData = protoimpl.X.CompressGZIP(file_v1_service_proto_rawDescData)
 	})
-	return file_proto_v1_service_proto_rawDescData
-}
-
-var file_proto_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
-var file_proto_v1_service_proto_goTypes = []any{
-	(*Timer)(nil),                 // 0: v1.Timer
-	(*GetTimerArgs)(nil),          // 1: v1.GetTimerArgs
-	(*ListTimersArgs)(nil),        // 2: v1.ListTimersArgs
-	(*DeleteTimerArgs)(nil),       // 3: v1.DeleteTimerArgs
-	(*DeleteTimersArgs)(nil),      // 4: v1.DeleteTimersArgs
-	(*ListTimersResponse)(nil),    // 5: v1.ListTimersResponse
-	(*IdentifierResponse)(nil),    // 6: v1.IdentifierResponse
-	(*Worker)(nil),                // 7: v1.Worker
-	(*ListWorkersArgs)(nil),       // 8: v1.ListWorkersArgs
-	(*ListWorkersResponse)(nil),   // 9: v1.ListWorkersResponse
-	nil,                           // 10: v1.Timer.LabelsEntry
-	nil,                           // 11: v1.Timer.HookHeadersEntry
-	nil,                           // 12: v1.DeleteTimersArgs.MatchLabelsEntry
-	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 14: google.protobuf.Empty
-}
-var file_proto_v1_service_proto_depIdxs = []int32{
-	10, // 0: v1.Timer.labels:type_name -> v1.Timer.LabelsEntry
-	13, // 1: v1.Timer.created_utc:type_name -> google.protobuf.Timestamp
-	13, // 2: v1.Timer.due_utc:type_name -> google.protobuf.Timestamp
-	13, // 3: v1.Timer.assigned_until_utc:type_name -> google.protobuf.Timestamp
-	13, // 4: v1.Timer.retry_utc:type_name -> google.protobuf.Timestamp
-	11, // 5: v1.Timer.hook_headers:type_name -> v1.Timer.HookHeadersEntry
-	13, // 6: v1.Timer.delivered_utc:type_name -> google.protobuf.Timestamp
-	13, // 7: v1.ListTimersArgs.after:type_name -> google.protobuf.Timestamp
-	13, // 8: v1.ListTimersArgs.before:type_name -> google.protobuf.Timestamp
-	13, // 9: v1.DeleteTimersArgs.after:type_name -> google.protobuf.Timestamp
-	13, // 10: v1.DeleteTimersArgs.before:type_name -> google.protobuf.Timestamp
-	12, // 11: v1.DeleteTimersArgs.matchLabels:type_name -> v1.DeleteTimersArgs.MatchLabelsEntry
-	0,  // 12: v1.ListTimersResponse.timers:type_name -> v1.Timer
-	13, // 13: v1.Worker.created_utc:type_name -> google.protobuf.Timestamp
-	13, // 14: v1.Worker.last_seen_utc:type_name -> google.protobuf.Timestamp
-	13, // 15: v1.ListWorkersArgs.last_seen_after:type_name -> google.protobuf.Timestamp
-	7,  // 16: v1.ListWorkersResponse.workers:type_name -> v1.Worker
-	0,  // 17: v1.Timers.CreateTimer:input_type -> v1.Timer
-	2,  // 18: v1.Timers.ListTimers:input_type -> v1.ListTimersArgs
-	1,  // 19: v1.Timers.GetTimer:input_type -> v1.GetTimerArgs
-	3,  // 20: v1.Timers.DeleteTimer:input_type -> v1.DeleteTimerArgs
-	4,  // 21: v1.Timers.DeleteTimers:input_type -> v1.DeleteTimersArgs
-	8,  // 22: v1.Workers.ListWorkers:input_type -> v1.ListWorkersArgs
-	6,  // 23: v1.Timers.CreateTimer:output_type -> v1.IdentifierResponse
-	5,  // 24: v1.Timers.ListTimers:output_type -> v1.ListTimersResponse
-	0,  // 25: v1.Timers.GetTimer:output_type -> v1.Timer
-	14, // 26: v1.Timers.DeleteTimer:output_type -> google.protobuf.Empty
-	14, // 27: v1.Timers.DeleteTimers:output_type -> google.protobuf.Empty
-	9,  // 28: v1.Workers.ListWorkers:output_type -> v1.ListWorkersResponse
-	23, // [23:29] is the sub-list for method output_type
-	17, // [17:23] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
-}
-
-func init() { file_proto_v1_service_proto_init() }
-func file_proto_v1_service_proto_init() {
-	if File_proto_v1_service_proto != nil {
+	return file_v1_service_proto_rawDescData
+}
+
+var file_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
+var file_v1_service_proto_goTypes = []any{
+	(ImportConflictPolicy)(0),        // 0: v1.ImportConflictPolicy
+	(*Timer)(nil),                    // 1: v1.Timer
+	(*GetTimerArgs)(nil),             // 2: v1.GetTimerArgs
+	(*ListTimersArgs)(nil),           // 3: v1.ListTimersArgs
+	(*DeleteTimerArgs)(nil),          // 4: v1.DeleteTimerArgs
+	(*DeleteTimersArgs)(nil),         // 5: v1.DeleteTimersArgs
+	(*ExportTimersArgs)(nil),         // 6: v1.ExportTimersArgs
+	(*ImportTimerArgs)(nil),          // 7: v1.ImportTimerArgs
+	(*ImportTimersResponse)(nil),     // 8: v1.ImportTimersResponse
+	(*ReplayTimersArgs)(nil),         // 9: v1.ReplayTimersArgs
+	(*ReplayTimersResponse)(nil),     // 10: v1.ReplayTimersResponse
+	(*GetDeliveryStatsArgs)(nil),     // 11: v1.GetDeliveryStatsArgs
+	(*DeliveryStats)(nil),            // 12: v1.DeliveryStats
+	(*GetDeliveryStatsResponse)(nil), // 13: v1.GetDeliveryStatsResponse
+	(*ListTimersResponse)(nil),       // 14: v1.ListTimersResponse
+	(*IdentifierResponse)(nil),       // 15: v1.IdentifierResponse
+	(*Worker)(nil),                   // 16: v1.Worker
+	(*ListWorkersArgs)(nil),          // 17: v1.ListWorkersArgs
+	(*ListWorkersResponse)(nil),      // 18: v1.ListWorkersResponse
+	(*WatchWakeupsArgs)(nil),         // 19: v1.WatchWakeupsArgs
+	(*Wakeup)(nil),                   // 20: v1.Wakeup
+	nil,                              // 21: v1.Timer.LabelsEntry
+	nil,                              // 22: v1.Timer.HookHeadersEntry
+	nil,                              // 23: v1.DeleteTimersArgs.MatchLabelsEntry
+	(*timestamppb.Timestamp)(nil),    // 24: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),      // 25: google.protobuf.Duration
+	(*emptypb.Empty)(nil),            // 26: google.protobuf.Empty
+}
+var file_v1_service_proto_depIdxs = []int32{
+	21, // 0: v1.Timer.labels:type_name -> v1.Timer.LabelsEntry
+	24, // 1: v1.Timer.created_utc:type_name -> google.protobuf.Timestamp
+	24, // 2: v1.Timer.due_utc:type_name -> google.protobuf.Timestamp
+	24, // 3: v1.Timer.assigned_until_utc:type_name -> google.protobuf.Timestamp
+	24, // 4: v1.Timer.retry_utc:type_name -> google.protobuf.Timestamp
+	24, // 5: v1.Timer.expires_utc:type_name -> google.protobuf.Timestamp
+	25, // 6: v1.Timer.stale_after:type_name -> google.protobuf.Duration
+	22, // 7: v1.Timer.hook_headers:type_name -> v1.Timer.HookHeadersEntry
+	24, // 8: v1.Timer.delivered_utc:type_name -> google.protobuf.Timestamp
+	24, // 9: v1.Timer.expired_utc:type_name -> google.protobuf.Timestamp
+	24, // 10: v1.Timer.fired_utc:type_name -> google.protobuf.Timestamp
+	24, // 11: v1.ListTimersArgs.after:type_name -> google.protobuf.Timestamp
+	24, // 12: v1.ListTimersArgs.before:type_name -> google.protobuf.Timestamp
+	24, // 13: v1.DeleteTimersArgs.after:type_name -> google.protobuf.Timestamp
+	24, // 14: v1.DeleteTimersArgs.before:type_name -> google.protobuf.Timestamp
+	23, // 15: v1.DeleteTimersArgs.matchLabels:type_name -> v1.DeleteTimersArgs.MatchLabelsEntry
+	24, // 16: v1.ExportTimersArgs.after:type_name -> google.protobuf.Timestamp
+	24, // 17: v1.ExportTimersArgs.before:type_name -> google.protobuf.Timestamp
+	1,  // 18: v1.ImportTimerArgs.timer:type_name -> v1.Timer
+	0,  // 19: v1.ImportTimerArgs.on_conflict:type_name -> v1.ImportConflictPolicy
+	24, // 20: v1.ReplayTimersArgs.delivered_after:type_name -> google.protobuf.Timestamp
+	24, // 21: v1.ReplayTimersArgs.delivered_before:type_name -> google.protobuf.Timestamp
+	24, // 22: v1.ReplayTimersArgs.due_utc:type_name -> google.protobuf.Timestamp
+	25, // 23: v1.ReplayTimersArgs.spread:type_name -> google.protobuf.Duration
+	24, // 24: v1.GetDeliveryStatsArgs.after:type_name -> google.protobuf.Timestamp
+	24, // 25: v1.GetDeliveryStatsArgs.before:type_name -> google.protobuf.Timestamp
+	24, // 26: v1.DeliveryStats.bucket_utc:type_name -> google.protobuf.Timestamp
+	25, // 27: v1.DeliveryStats.lateness_p50:type_name -> google.protobuf.Duration
+	25, // 28: v1.DeliveryStats.lateness_p99:type_name -> google.protobuf.Duration
+	25, // 29: v1.DeliveryStats.lateness_max:type_name -> google.protobuf.Duration
+	12, // 30: v1.GetDeliveryStatsResponse.stats:type_name -> v1.DeliveryStats
+	1,  // 31: v1.ListTimersResponse.timers:type_name -> v1.Timer
+	24, // 32: v1.Worker.created_utc:type_name -> google.protobuf.Timestamp
+	24, // 33: v1.Worker.last_seen_utc:type_name -> google.protobuf.Timestamp
+	24, // 34: v1.ListWorkersArgs.last_seen_after:type_name -> google.protobuf.Timestamp
+	16, // 35: v1.ListWorkersResponse.workers:type_name -> v1.Worker
+	24, // 36: v1.Wakeup.due_utc:type_name -> google.protobuf.Timestamp
+	1,  // 37: v1.Timers.CreateTimer:input_type -> v1.Timer
+	3,  // 38: v1.Timers.ListTimers:input_type -> v1.ListTimersArgs
+	2,  // 39: v1.Timers.GetTimer:input_type -> v1.GetTimerArgs
+	4,  // 40: v1.Timers.DeleteTimer:input_type -> v1.DeleteTimerArgs
+	5,  // 41: v1.Timers.DeleteTimers:input_type -> v1.DeleteTimersArgs
+	6,  // 42: v1.Timers.ExportTimers:input_type -> v1.ExportTimersArgs
+	7,  // 43: v1.Timers.ImportTimers:input_type -> v1.ImportTimerArgs
+	9,  // 44: v1.Timers.ReplayTimers:input_type -> v1.ReplayTimersArgs
+	11, // 45: v1.Timers.GetDeliveryStats:input_type -> v1.GetDeliveryStatsArgs
+	17, // 46: v1.Workers.ListWorkers:input_type -> v1.ListWorkersArgs
+	19, // 47: v1.Wakeups.Watch:input_type -> v1.WatchWakeupsArgs
+	15, // 48: v1.Timers.CreateTimer:output_type -> v1.IdentifierResponse
+	14, // 49: v1.Timers.ListTimers:output_type -> v1.ListTimersResponse
+	1,  // 50: v1.Timers.GetTimer:output_type -> v1.Timer
+	26, // 51: v1.Timers.DeleteTimer:output_type -> google.protobuf.Empty
+	26, // 52: v1.Timers.DeleteTimers:output_type -> google.protobuf.Empty
+	1,  // 53: v1.Timers.ExportTimers:output_type -> v1.Timer
+	8,  // 54: v1.Timers.ImportTimers:output_type -> v1.ImportTimersResponse
+	10, // 55: v1.Timers.ReplayTimers:output_type -> v1.ReplayTimersResponse
+	13, // 56: v1.Timers.GetDeliveryStats:output_type -> v1.GetDeliveryStatsResponse
+	18, // 57: v1.Workers.ListWorkers:output_type -> v1.ListWorkersResponse
+	20, // 58: v1.Wakeups.Watch:output_type -> v1.Wakeup
+	48, // [48:59] is the sub-list for method output_type
+	37, // [37:48] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
+}
+
+func init() { file_v1_service_proto_init() }
+func file_v1_service_proto_init() {
+	if File_v1_service_proto != nil {
 		return
 	}
+	file_v1_service_proto_msgTypes[8].OneofWrappers = []any{
+		(*ReplayTimersArgs_DueUtc)(nil),
+		(*ReplayTimersArgs_Spread)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_proto_v1_service_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   13,
+			RawDescriptor: file_v1_service_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   23,
 			NumExtensions: 0,
-			NumServices:   2,
+			NumServices:   3,
 		},
-		GoTypes:           file_proto_v1_service_proto_goTypes,
-		DependencyIndexes: file_proto_v1_service_proto_depIdxs,
-		MessageInfos:      file_proto_v1_service_proto_msgTypes,
+		GoTypes:           file_v1_service_proto_goTypes,
+		DependencyIndexes: file_v1_service_proto_depIdxs,
+		EnumInfos:         file_v1_service_proto_enumTypes,
+		MessageInfos:      file_v1_service_proto_msgTypes,
 	}.Build()
-	File_proto_v1_service_proto = out.File
-	file_proto_v1_service_proto_rawDesc = nil
-	file_proto_v1_service_proto_goTypes = nil
-	file_proto_v1_service_proto_depIdxs = nil
+	File_v1_service_proto = out.File
+	file_v1_service_proto_rawDesc = nil
+	file_v1_service_proto_goTypes = nil
+	file_v1_service_proto_depIdxs = nil
 }
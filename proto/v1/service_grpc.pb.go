@@ -2,7 +2,7 @@
 // versions:
 // - protoc-gen-go-grpc v1.5.1
 // - protoc             v5.28.3
-// source: proto/v1/service.proto
+// source: v1/service.proto
 
 package v1
 
@@ -20,11 +20,15 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Timers_CreateTimer_FullMethodName  = "/v1.Timers/CreateTimer"
-	Timers_ListTimers_FullMethodName   = "/v1.Timers/ListTimers"
-	Timers_GetTimer_FullMethodName     = "/v1.Timers/GetTimer"
-	Timers_DeleteTimer_FullMethodName  = "/v1.Timers/DeleteTimer"
-	Timers_DeleteTimers_FullMethodName = "/v1.Timers/DeleteTimers"
+	Timers_CreateTimer_FullMethodName      = "/v1.Timers/CreateTimer"
+	Timers_ListTimers_FullMethodName       = "/v1.Timers/ListTimers"
+	Timers_GetTimer_FullMethodName         = "/v1.Timers/GetTimer"
+	Timers_DeleteTimer_FullMethodName      = "/v1.Timers/DeleteTimer"
+	Timers_DeleteTimers_FullMethodName     = "/v1.Timers/DeleteTimers"
+	Timers_ExportTimers_FullMethodName     = "/v1.Timers/ExportTimers"
+	Timers_ImportTimers_FullMethodName     = "/v1.Timers/ImportTimers"
+	Timers_ReplayTimers_FullMethodName     = "/v1.Timers/ReplayTimers"
+	Timers_GetDeliveryStats_FullMethodName = "/v1.Timers/GetDeliveryStats"
 )
 
 // TimersClient is the client API for Timers service.
@@ -36,6 +40,18 @@ type TimersClient interface {
 	GetTimer(ctx context.Context, in *GetTimerArgs, opts ...grpc.CallOption) (*Timer, error)
 	DeleteTimer(ctx context.Context, in *DeleteTimerArgs, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DeleteTimers(ctx context.Context, in *DeleteTimersArgs, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ExportTimers streams every pending timer due within the range that
+	// matches the selector, in due order.
+	ExportTimers(ctx context.Context, in *ExportTimersArgs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Timer], error)
+	// ImportTimers creates timers from a stream, resolving name conflicts
+	// per message. Timers received before a failure stay imported.
+	ImportTimers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportTimerArgs, ImportTimersResponse], error)
+	// ReplayTimers clones timers delivered within a range into new
+	// pending timers, e.g. after a receiver loses data.
+	ReplayTimers(ctx context.Context, in *ReplayTimersArgs, opts ...grpc.CallOption) (*ReplayTimersResponse, error)
+	// GetDeliveryStats returns the per-minute delivery lateness rollups
+	// within the range for the shard keys matching the selector.
+	GetDeliveryStats(ctx context.Context, in *GetDeliveryStatsArgs, opts ...grpc.CallOption) (*GetDeliveryStatsResponse, error)
 }
 
 type timersClient struct {
@@ -96,6 +112,58 @@ func (c *timersClient) DeleteTimers(ctx context.Context, in *DeleteTimersArgs, o
 	return out, nil
 }
 
+func (c *timersClient) ExportTimers(ctx context.Context, in *ExportTimersArgs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Timer], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Timers_ServiceDesc.Streams[0], Timers_ExportTimers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportTimersArgs, Timer]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Timers_ExportTimersClient = grpc.ServerStreamingClient[Timer]
+
+func (c *timersClient) ImportTimers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportTimerArgs, ImportTimersResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Timers_ServiceDesc.Streams[1], Timers_ImportTimers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportTimerArgs, ImportTimersResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Timers_ImportTimersClient = grpc.ClientStreamingClient[ImportTimerArgs, ImportTimersResponse]
+
+func (c *timersClient) ReplayTimers(ctx context.Context, in *ReplayTimersArgs, opts ...grpc.CallOption) (*ReplayTimersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayTimersResponse)
+	err := c.cc.Invoke(ctx, Timers_ReplayTimers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *timersClient) GetDeliveryStats(ctx context.Context, in *GetDeliveryStatsArgs, opts ...grpc.CallOption) (*GetDeliveryStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeliveryStatsResponse)
+	err := c.cc.Invoke(ctx, Timers_GetDeliveryStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TimersServer is the server API for Timers service.
 // All implementations must embed UnimplementedTimersServer
 // for forward compatibility.
@@ -105,6 +173,18 @@ type TimersServer interface {
 	GetTimer(context.Context, *GetTimerArgs) (*Timer, error)
 	DeleteTimer(context.Context, *DeleteTimerArgs) (*emptypb.Empty, error)
 	DeleteTimers(context.Context, *DeleteTimersArgs) (*emptypb.Empty, error)
+	// ExportTimers streams every pending timer due within the range that
+	// matches the selector, in due order.
+	ExportTimers(*ExportTimersArgs, grpc.ServerStreamingServer[Timer]) error
+	// ImportTimers creates timers from a stream, resolving name conflicts
+	// per message. Timers received before a failure stay imported.
+	ImportTimers(grpc.ClientStreamingServer[ImportTimerArgs, ImportTimersResponse]) error
+	// ReplayTimers clones timers delivered within a range into new
+	// pending timers, e.g. after a receiver loses data.
+	ReplayTimers(context.Context, *ReplayTimersArgs) (*ReplayTimersResponse, error)
+	// GetDeliveryStats returns the per-minute delivery lateness rollups
+	// within the range for the shard keys matching the selector.
+	GetDeliveryStats(context.Context, *GetDeliveryStatsArgs) (*GetDeliveryStatsResponse, error)
 	mustEmbedUnimplementedTimersServer()
 }
 
@@ -130,6 +210,18 @@ func (UnimplementedTimersServer) DeleteTimer(context.Context, *DeleteTimerArgs)
 func (UnimplementedTimersServer) DeleteTimers(context.Context, *DeleteTimersArgs) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteTimers not implemented")
 }
+func (UnimplementedTimersServer) ExportTimers(*ExportTimersArgs, grpc.ServerStreamingServer[Timer]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportTimers not implemented")
+}
+func (UnimplementedTimersServer) ImportTimers(grpc.ClientStreamingServer[ImportTimerArgs, ImportTimersResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportTimers not implemented")
+}
+func (UnimplementedTimersServer) ReplayTimers(context.Context, *ReplayTimersArgs) (*ReplayTimersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayTimers not implemented")
+}
+func (UnimplementedTimersServer) GetDeliveryStats(context.Context, *GetDeliveryStatsArgs) (*GetDeliveryStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryStats not implemented")
+}
 func (UnimplementedTimersServer) mustEmbedUnimplementedTimersServer() {}
 func (UnimplementedTimersServer) testEmbeddedByValue()                {}
 
@@ -241,6 +333,60 @@ func _Timers_DeleteTimers_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Timers_ExportTimers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportTimersArgs)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TimersServer).ExportTimers(m, &grpc.GenericServerStream[ExportTimersArgs, Timer]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Timers_ExportTimersServer = grpc.ServerStreamingServer[Timer]
+
+func _Timers_ImportTimers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TimersServer).ImportTimers(&grpc.GenericServerStream[ImportTimerArgs, ImportTimersResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Timers_ImportTimersServer = grpc.ClientStreamingServer[ImportTimerArgs, ImportTimersResponse]
+
+func _Timers_ReplayTimers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayTimersArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimersServer).ReplayTimers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Timers_ReplayTimers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimersServer).ReplayTimers(ctx, req.(*ReplayTimersArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Timers_GetDeliveryStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryStatsArgs)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TimersServer).GetDeliveryStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Timers_GetDeliveryStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TimersServer).GetDeliveryStats(ctx, req.(*GetDeliveryStatsArgs))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Timers_ServiceDesc is the grpc.ServiceDesc for Timers service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -268,9 +414,28 @@ var Timers_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteTimers",
 			Handler:    _Timers_DeleteTimers_Handler,
 		},
+		{
+			MethodName: "ReplayTimers",
+			Handler:    _Timers_ReplayTimers_Handler,
+		},
+		{
+			MethodName: "GetDeliveryStats",
+			Handler:    _Timers_GetDeliveryStats_Handler,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "proto/v1/service.proto",
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportTimers",
+			Handler:       _Timers_ExportTimers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportTimers",
+			Handler:       _Timers_ImportTimers_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "v1/service.proto",
 }
 
 const (
@@ -372,5 +537,114 @@ var Workers_ServiceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "proto/v1/service.proto",
+	Metadata: "v1/service.proto",
+}
+
+const (
+	Wakeups_Watch_FullMethodName = "/v1.Wakeups/Watch"
+)
+
+// WakeupsClient is the client API for Wakeups service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WakeupsClient interface {
+	// Watch streams a wakeup for each timer created through this server
+	// that's due before workers would next poll for it.
+	Watch(ctx context.Context, in *WatchWakeupsArgs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Wakeup], error)
+}
+
+type wakeupsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWakeupsClient(cc grpc.ClientConnInterface) WakeupsClient {
+	return &wakeupsClient{cc}
+}
+
+func (c *wakeupsClient) Watch(ctx context.Context, in *WatchWakeupsArgs, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Wakeup], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Wakeups_ServiceDesc.Streams[0], Wakeups_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchWakeupsArgs, Wakeup]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Wakeups_WatchClient = grpc.ServerStreamingClient[Wakeup]
+
+// WakeupsServer is the server API for Wakeups service.
+// All implementations must embed UnimplementedWakeupsServer
+// for forward compatibility.
+type WakeupsServer interface {
+	// Watch streams a wakeup for each timer created through this server
+	// that's due before workers would next poll for it.
+	Watch(*WatchWakeupsArgs, grpc.ServerStreamingServer[Wakeup]) error
+	mustEmbedUnimplementedWakeupsServer()
+}
+
+// UnimplementedWakeupsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWakeupsServer struct{}
+
+func (UnimplementedWakeupsServer) Watch(*WatchWakeupsArgs, grpc.ServerStreamingServer[Wakeup]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedWakeupsServer) mustEmbedUnimplementedWakeupsServer() {}
+func (UnimplementedWakeupsServer) testEmbeddedByValue()                 {}
+
+// UnsafeWakeupsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WakeupsServer will
+// result in compilation errors.
+type UnsafeWakeupsServer interface {
+	mustEmbedUnimplementedWakeupsServer()
+}
+
+func RegisterWakeupsServer(s grpc.ServiceRegistrar, srv WakeupsServer) {
+	// If the following call pancis, it indicates UnimplementedWakeupsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Wakeups_ServiceDesc, srv)
+}
+
+func _Wakeups_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchWakeupsArgs)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WakeupsServer).Watch(m, &grpc.GenericServerStream[WatchWakeupsArgs, Wakeup]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Wakeups_WatchServer = grpc.ServerStreamingServer[Wakeup]
+
+// Wakeups_ServiceDesc is the grpc.ServiceDesc for Wakeups service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Wakeups_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.Wakeups",
+	HandlerType: (*WakeupsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Wakeups_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "v1/service.proto",
 }
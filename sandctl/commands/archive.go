@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+
+	"sandman/pkg/archive"
+	"sandman/pkg/model"
+	"sandman/pkg/selector"
+)
+
+func Archive() *cli.Command {
+	return &cli.Command{
+		Name:  "archive",
+		Usage: "Inspect archived (culled) timers",
+		Commands: []*cli.Command{
+			archiveSearch(),
+		},
+	}
+}
+
+func archiveSearch() *cli.Command {
+	return &cli.Command{
+		Name:  "search",
+		Usage: "Scan the archive segments in a directory for matching timers",
+		Flags: DefaultFlags(
+			&cli.StringFlag{
+				Name:     "dir",
+				Usage:    "The archive directory (`archive.dir` in the controller config)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "label",
+				Aliases: []string{"l"},
+			},
+			&cli.TimestampFlag{
+				Name: "after",
+			},
+			&cli.TimestampFlag{
+				Name: "before",
+			},
+			&cli.TimestampFlag{
+				Name: "delivered-after",
+			},
+			&cli.TimestampFlag{
+				Name: "delivered-before",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "One of `jsonl` or `yaml`",
+				Value:   "jsonl",
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var encode func(any) error
+			switch output := cmd.String("output"); output {
+			case "jsonl":
+				encode = json.NewEncoder(os.Stdout).Encode
+			case "yaml":
+				enc := yaml.NewEncoder(os.Stdout)
+				defer enc.Close()
+				encode = enc.Encode
+			default:
+				return fmt.Errorf("archive search; invalid output %q", output)
+			}
+			q := archive.Query{
+				DueAfter:        cmd.Timestamp("after"),
+				DueBefore:       cmd.Timestamp("before"),
+				DeliveredAfter:  cmd.Timestamp("delivered-after"),
+				DeliveredBefore: cmd.Timestamp("delivered-before"),
+			}
+			if rawSelector := cmd.String("label"); rawSelector != "" {
+				var err error
+				q.Selector, err = selector.Parse(rawSelector)
+				if err != nil {
+					return fmt.Errorf("archive search; invalid selector: %w", err)
+				}
+			}
+			return archive.Search(cmd.String("dir"), q, func(t model.Timer) error {
+				return encode(t)
+			})
+		},
+	}
+}
@@ -9,6 +9,8 @@ func Root() *cli.Command {
 		Commands: []*cli.Command{
 			Timers(),
 			Workers(),
+			Stats(),
+			Archive(),
 		},
 	}
 }
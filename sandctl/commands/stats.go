@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	v1 "sandman/proto/v1"
+
+	"github.com/urfave/cli/v3"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+)
+
+func Stats() *cli.Command {
+	stats := &cli.Command{
+		Name:  "stats",
+		Usage: "Report sandman delivery stats",
+		Commands: []*cli.Command{
+			statsLateness(),
+		},
+	}
+	return stats
+}
+
+func statsLateness() *cli.Command {
+	return &cli.Command{
+		Name:  "lateness",
+		Usage: "Show per-minute delivery lateness (fired - due) by shard key",
+		Flags: DefaultClientFlags(
+			&cli.TimestampFlag{
+				Name: "after",
+			},
+			&cli.TimestampFlag{
+				Name: "before",
+			},
+			&cli.DurationFlag{
+				Name:  "last",
+				Usage: "Show the stats for this long up to now, if --after is not set",
+				Value: time.Hour,
+			},
+			&cli.StringFlag{
+				Name:    "selector",
+				Aliases: []string{"l"},
+				Usage:   "Select shard keys, e.g. shard_key=tenant-a",
+			},
+			&cli.DurationFlag{
+				Name:  "slo",
+				Usage: "Flag minutes whose p99 lateness exceeds this",
+				Value: 2 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Value:   "table",
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			c, err := createTimersClient(cmd)
+			if err != nil {
+				return fmt.Errorf("stats lateness; create client: %w", err)
+			}
+			before := time.Now().UTC()
+			if ts := cmd.Timestamp("before"); !ts.IsZero() {
+				before = ts
+			}
+			after := before.Add(-cmd.Duration("last"))
+			if ts := cmd.Timestamp("after"); !ts.IsZero() {
+				after = ts
+			}
+			res, err := c.GetDeliveryStats(ctx, &v1.GetDeliveryStatsArgs{
+				Selector: cmd.String("selector"),
+				After:    timestamppb.New(after),
+				Before:   timestamppb.New(before),
+			})
+			if err != nil {
+				return err
+			}
+			switch cmd.String("output") {
+			default:
+			case "table":
+				return printLatenessTable(res.GetStats(), cmd.Duration("slo"))
+			case "json":
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "\t")
+				return enc.Encode(res.GetStats())
+			case "yaml":
+				enc := yaml.NewEncoder(os.Stdout)
+				return enc.Encode(res.GetStats())
+			}
+			return nil
+		},
+	}
+}
+
+func printLatenessTable(stats []*v1.DeliveryStats, slo time.Duration) error {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "MINUTE\tSHARD KEY\tCOUNT\tP50\tP99\tMAX\t")
+	var total uint64
+	var over int
+	for _, ds := range stats {
+		p99 := ds.GetLatenessP99().AsDuration()
+		var flag string
+		if slo > 0 && p99 > slo {
+			flag = "over slo"
+			over++
+		}
+		total += ds.GetCount()
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%v\t%v\t%v\t%s\n",
+			ds.GetBucketUtc().AsTime().Format(time.RFC3339),
+			ds.GetShardKey(),
+			ds.GetCount(),
+			ds.GetLatenessP50().AsDuration(),
+			p99,
+			ds.GetLatenessMax().AsDuration(),
+			flag,
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("%d deliveries; %d of %d minutes over the %v p99 slo\n", total, over, len(stats), slo)
+	return nil
+}
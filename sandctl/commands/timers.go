@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	v1 "sandman/proto/v1"
 	"sandman/sandctl/viewmodel"
@@ -14,6 +18,7 @@ import (
 	"sandman/pkg/cliutil"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gopkg.in/yaml.v3"
 )
@@ -29,6 +34,9 @@ func Timers() *cli.Command {
 			timerList(),
 			timerGet(),
 			timerDelete(),
+			timerExport(),
+			timerImport(),
+			timerReplay(),
 		},
 	}
 	return timers
@@ -52,12 +60,23 @@ func timerGenerate() *cli.Command {
 				Name:    "priority",
 				Aliases: []string{"p"},
 			},
+			&cli.StringFlag{
+				Name: "shard-key",
+			},
+			&cli.StringFlag{
+				Name:  "ordering-key",
+				Usage: "Deliver timers sharing this key one at a time in due order",
+			},
 			&cli.TimestampFlag{
 				Name: "due-utc",
 			},
 			&cli.DurationFlag{
 				Name: "due-in",
 			},
+			&cli.DurationFlag{
+				Name:  "stale-after",
+				Usage: "Mark the timer expired instead of delivering it if it is claimed this long past due",
+			},
 			&cli.StringFlag{
 				Name:     "hook-url",
 				Required: true,
@@ -83,9 +102,12 @@ func timerGenerate() *cli.Command {
 				hookBodyData = base64.StdEncoding.EncodeToString(rawHookBodyData)
 			}
 			t := viewmodel.Timer{
-				Name:     cmd.String("name"),
-				Labels:   cmd.StringMap("label"),
-				Priority: uint32(cmd.Uint("priority")),
+				Name:        cmd.String("name"),
+				Labels:      cmd.StringMap("label"),
+				Priority:    uint32(cmd.Uint("priority")),
+				ShardKey:    cmd.String("shard-key"),
+				OrderingKey: cmd.String("ordering-key"),
+				StaleAfter:  cmd.Duration("stale-after"),
 				Hook: viewmodel.Hook{
 					URL:     cmd.String("hook-url"),
 					Method:  cmd.String("hook-method"),
@@ -260,3 +282,219 @@ func timerDelete() *cli.Command {
 		},
 	}
 }
+
+func timerExport() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "Write every pending timer in the range, in the format `create` and `import` read",
+		Flags: DefaultClientFlags(
+			&cli.TimestampFlag{
+				Name: "after",
+			},
+			&cli.TimestampFlag{
+				Name: "before",
+			},
+			&cli.StringFlag{
+				Name:    "label",
+				Aliases: []string{"l"},
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "One of `jsonl` or `yaml` (a multi-document stream)",
+				Value:   "jsonl",
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var encode func(any) error
+			switch output := cmd.String("output"); output {
+			case "jsonl":
+				encode = json.NewEncoder(os.Stdout).Encode
+			case "yaml":
+				enc := yaml.NewEncoder(os.Stdout)
+				defer enc.Close()
+				encode = enc.Encode
+			default:
+				return fmt.Errorf("timers export; invalid output %q", output)
+			}
+			c, err := createTimersClient(cmd)
+			if err != nil {
+				return fmt.Errorf("timers export; create client: %w", err)
+			}
+			args := &v1.ExportTimersArgs{
+				Selector: cmd.String("label"),
+			}
+			if after := cmd.Timestamp("after"); !after.IsZero() {
+				args.After = timestamppb.New(after)
+			}
+			if before := cmd.Timestamp("before"); !before.IsZero() {
+				args.Before = timestamppb.New(before)
+			}
+			stream, err := c.ExportTimers(ctx, args)
+			if err != nil {
+				return fmt.Errorf("timers export; failed: %w", err)
+			}
+			for {
+				t, err := stream.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return fmt.Errorf("timers export; failed: %w", err)
+				}
+				if err := encode(viewmodel.TimerFromProto(t)); err != nil {
+					return fmt.Errorf("timers export; write: %w", err)
+				}
+			}
+		},
+	}
+}
+
+func timerImport() *cli.Command {
+	return &cli.Command{
+		Name:  "import",
+		Usage: "Create timers from a jsonl or multi-document yaml file (e.g. from `export`)",
+		Flags: DefaultClientFlags(
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+			},
+			&cli.StringFlag{
+				Name:  "on-conflict",
+				Usage: "What to do when a timer name already exists; one of `fail`, `skip` or `replace`",
+				Value: "fail",
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			var onConflict v1.ImportConflictPolicy
+			switch value := cmd.String("on-conflict"); value {
+			case "fail":
+				onConflict = v1.ImportConflictPolicy_IMPORT_CONFLICT_FAIL
+			case "skip":
+				onConflict = v1.ImportConflictPolicy_IMPORT_CONFLICT_SKIP
+			case "replace":
+				onConflict = v1.ImportConflictPolicy_IMPORT_CONFLICT_REPLACE
+			default:
+				return fmt.Errorf("timers import; invalid on-conflict %q", value)
+			}
+			data, err := cliutil.FileOrStdin(cmd.String("file"))
+			if err != nil {
+				return fmt.Errorf("timers import; could not read file: %w", err)
+			}
+			timers, err := decodeTimers(data)
+			if err != nil {
+				return fmt.Errorf("timers import; could not unmarshal file: %w", err)
+			}
+			c, err := createTimersClient(cmd)
+			if err != nil {
+				return fmt.Errorf("timers import; create client: %w", err)
+			}
+			stream, err := c.ImportTimers(ctx)
+			if err != nil {
+				return fmt.Errorf("timers import; failed: %w", err)
+			}
+			for _, t := range timers {
+				if err := stream.Send(&v1.ImportTimerArgs{Timer: t.ToProto(), OnConflict: onConflict}); err != nil {
+					// the server closed the stream; the real error comes
+					// back from CloseAndRecv.
+					break
+				}
+			}
+			res, err := stream.CloseAndRecv()
+			if err != nil {
+				return fmt.Errorf("timers import; failed: %w", err)
+			}
+			fmt.Printf("imported timers; created=%d skipped=%d replaced=%d\n", res.GetCreated(), res.GetSkipped(), res.GetReplaced())
+			return nil
+		},
+	}
+}
+
+// decodeTimers reads timers written by `timers export`, either one json
+// object per line or a multi-document yaml stream.
+func decodeTimers(data []byte) (output []viewmodel.Timer, err error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, 16<<20)
+		var line int
+		for scanner.Scan() {
+			line++
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var t viewmodel.Timer
+			if err = json.Unmarshal(scanner.Bytes(), &t); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			output = append(output, t)
+		}
+		err = scanner.Err()
+		return
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var t viewmodel.Timer
+		if err = dec.Decode(&t); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			return
+		}
+		output = append(output, t)
+	}
+}
+
+func timerReplay() *cli.Command {
+	return &cli.Command{
+		Name:  "replay",
+		Usage: "Re-fire timers delivered within a range as new timers",
+		Flags: DefaultClientFlags(
+			&cli.TimestampFlag{
+				Name:     "delivered-after",
+				Required: true,
+			},
+			&cli.TimestampFlag{
+				Name:     "delivered-before",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:    "label",
+				Aliases: []string{"l"},
+			},
+			&cli.TimestampFlag{
+				Name:  "due-utc",
+				Usage: "When the replays are due (defaults to now)",
+			},
+			&cli.DurationFlag{
+				Name:  "spread",
+				Usage: "Stagger the replays evenly from now over this window",
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			args := &v1.ReplayTimersArgs{
+				Selector:        cmd.String("label"),
+				DeliveredAfter:  timestamppb.New(cmd.Timestamp("delivered-after")),
+				DeliveredBefore: timestamppb.New(cmd.Timestamp("delivered-before")),
+			}
+			dueUTC, spread := cmd.Timestamp("due-utc"), cmd.Duration("spread")
+			if !dueUTC.IsZero() && spread > 0 {
+				return fmt.Errorf("timers replay; only one of --due-utc or --spread may be set")
+			}
+			if !dueUTC.IsZero() {
+				args.Schedule = &v1.ReplayTimersArgs_DueUtc{DueUtc: timestamppb.New(dueUTC)}
+			} else if spread > 0 {
+				args.Schedule = &v1.ReplayTimersArgs_Spread{Spread: durationpb.New(spread)}
+			}
+			c, err := createTimersClient(cmd)
+			if err != nil {
+				return fmt.Errorf("timers replay; create client: %w", err)
+			}
+			res, err := c.ReplayTimers(ctx, args)
+			if err != nil {
+				return fmt.Errorf("timers replay; failed: %w", err)
+			}
+			fmt.Printf("replayed %d timers (replay id %s)\n", res.GetReplayed(), res.GetReplayId())
+			return nil
+		},
+	}
+}
@@ -6,36 +6,81 @@ import (
 
 	v1 "sandman/proto/v1"
 
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Timer struct {
-	Name     string            `yaml:"name"`
-	Labels   map[string]string `yaml:"labels,omitempty"`
-	Priority uint32            `yaml:"priority"`
-	ShardKey string            `yaml:"shard_key"`
-	DueUTC   time.Time         `yaml:"due_utc"`
-	Hook     Hook              `yaml:"hook"`
+	Name     string            `json:"name" yaml:"name"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Priority uint32            `json:"priority" yaml:"priority"`
+	ShardKey string            `json:"shard_key" yaml:"shard_key"`
+	// OrderingKey delivers timers sharing it (and the shard key) one at
+	// a time in due order.
+	OrderingKey string    `json:"ordering_key,omitempty" yaml:"ordering_key,omitempty"`
+	DueUTC      time.Time `json:"due_utc" yaml:"due_utc"`
+	// ExpiresUTC and StaleAfter are mutually exclusive ways to say when
+	// the timer is no longer worth delivering.
+	ExpiresUTC *time.Time    `json:"expires_utc,omitempty" yaml:"expires_utc,omitempty"`
+	StaleAfter time.Duration `json:"stale_after,omitempty" yaml:"stale_after,omitempty"`
+	Hook       Hook          `json:"hook" yaml:"hook"`
+}
+
+// TimerFromProto returns the view of a timer as read by `ToProto`, so
+// exported timers can be imported unchanged.
+//
+// Server-assigned and delivery fields (id, attempt, delivered_utc, ...)
+// are dropped; a stale_after is returned resolved as `ExpiresUTC`.
+func TimerFromProto(t *v1.Timer) Timer {
+	output := Timer{
+		Name:        t.GetName(),
+		Labels:      t.GetLabels(),
+		Priority:    t.GetPriority(),
+		ShardKey:    t.GetShardKey(),
+		OrderingKey: t.GetOrderingKey(),
+		DueUTC:      t.GetDueUtc().AsTime(),
+		Hook: Hook{
+			URL:     t.GetHookUrl(),
+			Method:  t.GetHookMethod(),
+			Headers: t.GetHookHeaders(),
+		},
+	}
+	if len(t.GetHookBody()) > 0 {
+		output.Hook.Body = base64.StdEncoding.EncodeToString(t.GetHookBody())
+	}
+	if t.GetExpiresUtc() != nil {
+		expiresUTC := t.GetExpiresUtc().AsTime()
+		output.ExpiresUTC = &expiresUTC
+	}
+	return output
 }
 
 func (t Timer) ToProto() *v1.Timer {
 	bodyData, _ := base64.StdEncoding.DecodeString(t.Hook.Body)
-	return &v1.Timer{
+	output := &v1.Timer{
 		Name:        t.Name,
 		Labels:      t.Labels,
 		Priority:    t.Priority,
 		ShardKey:    t.ShardKey,
+		OrderingKey: t.OrderingKey,
 		DueUtc:      timestamppb.New(t.DueUTC),
 		HookUrl:     t.Hook.URL,
 		HookMethod:  t.Hook.Method,
 		HookHeaders: t.Hook.Headers,
 		HookBody:    bodyData,
 	}
+	if t.ExpiresUTC != nil {
+		output.ExpiresUtc = timestamppb.New(*t.ExpiresUTC)
+	}
+	if t.StaleAfter > 0 {
+		output.StaleAfter = durationpb.New(t.StaleAfter)
+	}
+	return output
 }
 
 type Hook struct {
-	URL     string            `yaml:"url"`
-	Method  string            `yaml:"method,omitempty"`
-	Headers map[string]string `yaml:"headers,omitempty"`
-	Body    string            `yaml:"body,omitempty"`
+	URL     string            `json:"url" yaml:"url"`
+	Method  string            `json:"method,omitempty" yaml:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body    string            `json:"body,omitempty" yaml:"body,omitempty"`
 }
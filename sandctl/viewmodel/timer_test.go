@@ -0,0 +1,46 @@
+package viewmodel
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"sandman/pkg/assert"
+)
+
+func Test_Timer_roundTrip(t *testing.T) {
+	expiresUTC := time.Date(2024, 10, 19, 22, 0, 0, 0, time.UTC)
+	original := Timer{
+		Name:        "test-timer",
+		Labels:      map[string]string{"env": "prod", "team": "infra"},
+		Priority:    1000,
+		ShardKey:    "tenant-a",
+		OrderingKey: "account-1",
+		DueUTC:      time.Date(2024, 10, 19, 20, 19, 18, 170000000, time.UTC),
+		ExpiresUTC:  &expiresUTC,
+		Hook: Hook{
+			URL:     "https://hooks.example.com/fire",
+			Method:  "PUT",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+			Body:    base64.StdEncoding.EncodeToString([]byte(`{"hello":"world"}`)),
+		},
+	}
+
+	viaProto := TimerFromProto(original.ToProto())
+	assert.Equal(t, original, viaProto)
+
+	data, err := json.Marshal(viaProto)
+	assert.Nil(t, err)
+	var viaJSON Timer
+	assert.Nil(t, json.Unmarshal(data, &viaJSON))
+	assert.Equal(t, original, viaJSON)
+
+	data, err = yaml.Marshal(viaProto)
+	assert.Nil(t, err)
+	var viaYAML Timer
+	assert.Nil(t, yaml.Unmarshal(data, &viaYAML))
+	assert.Equal(t, original, viaYAML)
+}
@@ -10,11 +10,13 @@ import (
 	"time"
 
 	"sandman/pkg/apputil"
+	"sandman/pkg/archive"
 	"sandman/pkg/configutil"
 	"sandman/pkg/db"
 	"sandman/pkg/db/dbutil"
 	"sandman/pkg/db/migration"
 	"sandman/pkg/log"
+	"sandman/pkg/metrics"
 	"sandman/pkg/slant"
 
 	"sandman/pkg/config"
@@ -29,6 +31,9 @@ var (
 	flagMaxReplicas        = flag.Int("max-replicas", 0, "Maximum number of worker replicas (0 = unlimited)")
 	flagCullInterval       = flag.Duration("cull-interval", 0, "How often to sweep delivered/exhausted timers")
 	flagCullRetention      = flag.Duration("cull-retention", 0, "How long past due_utc a delivered timer is kept before cull")
+	flagCullBatchSize      = flag.Int("cull-batch-size", 0, "How many timers to archive and delete at a time (with an archive dir)")
+	flagStatsRetention     = flag.Duration("stats-retention", 0, "How long delivery stats rollups are kept")
+	flagOccupancyTarget    = flag.Float64("wheel-occupancy-target", 0, "Mean worker wheel occupancy to scale out past (0-1)")
 	flagNamespace          = flag.String("namespace", "", "Kubernetes namespace (k8s mode)")
 	flagDeployment         = flag.String("deployment", "", "Deployment name to scale (k8s mode)")
 	flagLeaseName          = flag.String("lease-name", "", "Lease name for leader election (k8s mode)")
@@ -44,6 +49,9 @@ type controlConfig struct {
 	MaxReplicas        int               `yaml:"max_replicas"`
 	CullInterval       time.Duration     `yaml:"cull_interval"`
 	CullRetention      time.Duration     `yaml:"cull_retention"`
+	CullBatchSize      int               `yaml:"cull_batch_size"`
+	StatsRetention     time.Duration     `yaml:"stats_retention"`
+	OccupancyTarget    float64           `yaml:"wheel_occupancy_target"`
 	K8s                control.K8sConfig `yaml:"k8s"`
 }
 
@@ -56,6 +64,9 @@ func (c *controlConfig) Resolve(ctx context.Context) error {
 		configutil.Set(&c.MaxReplicas, configutil.Lazy(flagMaxReplicas), configutil.Env[int]("MAX_REPLICAS")),
 		configutil.Set(&c.CullInterval, configutil.Lazy(flagCullInterval), configutil.Env[time.Duration]("CULL_INTERVAL")),
 		configutil.Set(&c.CullRetention, configutil.Lazy(flagCullRetention), configutil.Env[time.Duration]("CULL_RETENTION")),
+		configutil.Set(&c.CullBatchSize, configutil.Lazy(flagCullBatchSize), configutil.Env[int]("CULL_BATCH_SIZE")),
+		configutil.Set(&c.StatsRetention, configutil.Lazy(flagStatsRetention), configutil.Env[time.Duration]("STATS_RETENTION")),
+		configutil.Set(&c.OccupancyTarget, configutil.Lazy(flagOccupancyTarget), configutil.Env[float64]("WHEEL_OCCUPANCY_TARGET")),
 		configutil.Set(&c.K8s.Namespace, configutil.Lazy(flagNamespace), configutil.Env[string]("POD_NAMESPACE")),
 		configutil.Set(&c.K8s.Deployment, configutil.Lazy(flagDeployment), configutil.Env[string]("DEPLOYMENT_NAME")),
 		configutil.Set(&c.K8s.LeaseName, configutil.Lazy(flagLeaseName), configutil.Env[string]("LEASE_NAME"), configutil.Const("sandman-control")),
@@ -93,6 +104,19 @@ var entrypoint = apputil.DBEntryPoint[controlConfig]{
 			WorkerPollingInterval: cfg.Worker.PollingIntervalOrDefault(),
 			CullInterval:          cfg.CullInterval,
 			CullRetention:         cfg.CullRetention,
+			CullBatchSize:         cfg.CullBatchSize,
+			StatsRetention:        cfg.StatsRetention,
+			WheelOccupancyTarget:  cfg.OccupancyTarget,
+		}
+
+		var archiver *archive.Writer
+		if cfg.Archive.IsEnabled() {
+			var err error
+			archiver, err = archive.NewWriter(cfg.Archive)
+			if err != nil {
+				return err
+			}
+			defer archiver.Close()
 		}
 
 		logger := log.GetLogger(ctx)
@@ -136,9 +160,16 @@ var entrypoint = apputil.DBEntryPoint[controlConfig]{
 		}
 
 		ctrl := &control.Controller{
-			Config: ctrlCfg,
-			Model:  modelMgr,
-			Scaler: scaler,
+			Config:   ctrlCfg,
+			Model:    modelMgr,
+			Scaler:   scaler,
+			Archiver: archiver,
+		}
+
+		if cfg.Metrics.IsEnabled() {
+			muxes := make(apputil.HTTPMuxes)
+			muxes.For(cfg.Metrics.BindAddr).Handle("/metrics", metrics.Handler())
+			muxes.ListenAndServe(ctx)
 		}
 
 		logger.Info("starting controller", log.String("mode", cfg.Mode))
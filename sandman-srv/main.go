@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"sandman/pkg/apputil"
 	"sandman/pkg/db"
@@ -15,9 +17,13 @@ import (
 	"sandman/pkg/slant"
 
 	"sandman/pkg/config"
+	"sandman/pkg/egress"
 	"sandman/pkg/grpcutil"
+	"sandman/pkg/health"
+	"sandman/pkg/metrics"
 	"sandman/pkg/model"
 	"sandman/pkg/server"
+	"sandman/pkg/wakeup"
 	v1 "sandman/proto/v1"
 )
 
@@ -40,6 +46,7 @@ var entrypoint = apputil.DBEntryPoint[config.Config]{
 		logger := log.GetLogger(ctx)
 		var interceptors = []grpc.UnaryServerInterceptor{
 			grpcutil.Recover(),
+			grpcutil.Metrics(),
 			grpcutil.Logged(logger),
 		}
 
@@ -47,16 +54,58 @@ var entrypoint = apputil.DBEntryPoint[config.Config]{
 
 		s := grpc.NewServer(
 			serverOpts,
+			grpc.ChainStreamInterceptor(grpcutil.StreamMetrics()),
 		)
 
-		ts := server.TimerServer{Model: modelMgr}
+		egressPolicy, err := egress.New(cfg.Egress)
+		if err != nil {
+			return err
+		}
+		// Wakeups for timers due before the next poll go to workers
+		// watching this server, and on Postgres also over NOTIFY to
+		// workers listening on the database.
+		hub := wakeup.NewHub()
+		wakeups := wakeup.Publishers{hub}
+		if db.Dialect(dbc.Config.Dialect).Is(db.DialectPostgres) {
+			wakeups = append(wakeups, wakeup.Notifier{Conn: dbc})
+		}
+		ts := server.TimerServer{
+			Model:         modelMgr,
+			Egress:        egressPolicy,
+			ArchiveDir:    cfg.Archive.Dir,
+			Wakeups:       wakeups,
+			WakeupHorizon: cfg.Worker.PollingIntervalOrDefault(),
+		}
 		v1.RegisterTimersServer(s, ts)
 		ws := server.WorkerServer{Model: modelMgr}
 		v1.RegisterWorkersServer(s, ws)
+		v1.RegisterWakeupsServer(s, server.WakeupServer{Hub: hub})
+
+		// The gRPC health service tracks DB connectivity for the server as
+		// a whole ("") and each registered service. A drain flips every
+		// status to NOT_SERVING for good so load balancers stop routing
+		// new calls; in-flight and late calls are still served until the
+		// process is stopped.
+		hs := grpchealth.NewServer()
+		healthpb.RegisterHealthServer(s, hs)
+		healthServices := []string{"", v1.Timers_ServiceDesc.ServiceName, v1.Workers_ServiceDesc.ServiceName, v1.Wakeups_ServiceDesc.ServiceName}
+		go grpcutil.MonitorHealth(ctx, dbc.Ping, hs, healthServices, cfg.Health.CheckTimeoutOrDefault())
+
+		muxes := make(apputil.HTTPMuxes)
+		if cfg.Health.IsEnabled() {
+			health.New(
+				health.OptReadiness("db", dbc.Ping),
+				health.OptDrain(hs.Shutdown),
+				health.OptCheckTimeout(cfg.Health.CheckTimeoutOrDefault()),
+			).Register(muxes.For(cfg.Health.BindAddr))
+		}
+		if cfg.Metrics.IsEnabled() {
+			muxes.For(cfg.Metrics.BindAddr).Handle("/metrics", metrics.Handler())
+		}
+		muxes.ListenAndServe(ctx)
 
 		bindAddr := cfg.Server.BindAddr
 		var socketListener net.Listener
-		var err error
 		if after, ok := strings.CutPrefix(bindAddr, "unix://"); ok {
 			socketListener, err = net.Listen("unix", after)
 		} else {
@@ -4,10 +4,13 @@ import (
 	"context"
 	"expvar"
 	"flag"
-	"net/http"
 	"os"
 	"sandman/pkg/config"
+	"sandman/pkg/egress"
+	"sandman/pkg/health"
+	"sandman/pkg/metrics"
 	"sandman/pkg/model"
+	"sandman/pkg/wakeup"
 	"sandman/pkg/worker"
 
 	"sandman/pkg/apputil"
@@ -55,28 +58,44 @@ var entrypoint = apputil.DBEntryPoint[workerConfig]{
 		if err := modelMgr.Initialize(ctx); err != nil {
 			return err
 		}
+		egressPolicy, err := egress.New(cfg.Egress)
+		if err != nil {
+			return err
+		}
 		workerOpts := []worker.WorkerOption{
-			worker.OptBatchSize(cfg.Worker.BatchSizeOrDefault()),
-			worker.OptPollingInterval(cfg.Worker.PollingIntervalOrDefault()),
+			worker.OptConfig(cfg.Worker),
+			worker.OptEgressPolicy(egressPolicy),
 		}
-		if cfg.Worker.PrefetchWindow > 0 {
-			workerOpts = append(workerOpts, worker.OptPrefetchWindow(cfg.Worker.PrefetchWindow))
+		var wakeups wakeup.Sources
+		if db.Dialect(dbc.Config.Dialect).Is(db.DialectPostgres) {
+			wakeups = append(wakeups, wakeup.Listener{DSN: dbc.Config.CreateDSN()})
 		}
-		if cfg.Worker.DispatchTickInterval > 0 {
-			workerOpts = append(workerOpts, worker.OptDispatchTickInterval(cfg.Worker.DispatchTickInterval))
+		for _, addr := range cfg.Worker.WakeupAddrs {
+			wakeups = append(wakeups, wakeup.Client{Addr: addr, Hostname: cfg.Hostname})
 		}
-		if cfg.Worker.FlushInterval > 0 {
-			workerOpts = append(workerOpts, worker.OptFlushInterval(cfg.Worker.FlushInterval))
+		if len(wakeups) > 0 {
+			workerOpts = append(workerOpts, worker.OptWakeups(wakeups))
 		}
 		w := worker.New(cfg.Hostname, modelMgr, workerOpts...)
+
+		muxes := make(apputil.HTTPMuxes)
 		if cfg.ExpvarListenAddr != "" {
 			w.Vars().Publish()
-			go func() {
-				if err := http.ListenAndServe(cfg.ExpvarListenAddr, expvar.Handler()); err != nil {
-					log.GetLogger(ctx).Error("expvar server error", log.Any("err", err))
-				}
-			}()
+			muxes.For(cfg.ExpvarListenAddr).Handle("/", expvar.Handler())
+		}
+		if cfg.Metrics.IsEnabled() {
+			muxes.For(cfg.Metrics.BindAddr).Handle("/metrics", metrics.Handler())
+		}
+		if cfg.Health.IsEnabled() {
+			health.New(
+				health.OptLiveness("worker", w.Alive),
+				health.OptReadiness("db", dbc.Ping),
+				health.OptReadiness("worker", w.Ready),
+				health.OptDrain(w.Drain),
+				health.OptCheckTimeout(cfg.Health.CheckTimeoutOrDefault()),
+			).Register(muxes.For(cfg.Health.BindAddr))
 		}
+		muxes.ListenAndServe(ctx)
 		return w.Run(ctx)
 	},
 }
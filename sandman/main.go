@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"sandman/pkg/apputil"
+	"sandman/pkg/archive"
+	"sandman/pkg/configutil"
+	"sandman/pkg/db"
+	"sandman/pkg/db/dbutil"
+	"sandman/pkg/db/migration"
+	"sandman/pkg/graceful"
+	"sandman/pkg/log"
+	"sandman/pkg/slant"
+
+	"sandman/pkg/config"
+	"sandman/pkg/control"
+	"sandman/pkg/egress"
+	"sandman/pkg/grpcutil"
+	"sandman/pkg/health"
+	"sandman/pkg/metrics"
+	"sandman/pkg/model"
+	"sandman/pkg/server"
+	"sandman/pkg/wakeup"
+	"sandman/pkg/worker"
+	v1 "sandman/proto/v1"
+)
+
+var (
+	flagWorkers        = flag.Int("workers", 0, "How many workers to run in process")
+	flagCullInterval   = flag.Duration("cull-interval", 0, "How often to sweep delivered/exhausted timers")
+	flagCullRetention  = flag.Duration("cull-retention", 0, "How long past due_utc a delivered timer is kept before cull")
+	flagCullBatchSize  = flag.Int("cull-batch-size", 0, "How many timers to archive and delete at a time (with an archive dir)")
+	flagStatsRetention = flag.Duration("stats-retention", 0, "How long delivery stats rollups are kept")
+)
+
+// sandmanConfig is the all-in-one config: the shared config plus the
+// number of workers and the controller's cull settings. The controller
+// only logs its desired scale; there's nothing to scale in one process.
+type sandmanConfig struct {
+	config.Config `yaml:",inline"`
+
+	Workers        int           `yaml:"workers"`
+	CullInterval   time.Duration `yaml:"cull_interval"`
+	CullRetention  time.Duration `yaml:"cull_retention"`
+	CullBatchSize  int           `yaml:"cull_batch_size"`
+	StatsRetention time.Duration `yaml:"stats_retention"`
+}
+
+func (c *sandmanConfig) Resolve(ctx context.Context) error {
+	return configutil.Resolve(ctx,
+		(&c.Config).Resolve,
+		configutil.Set(&c.Workers, configutil.Lazy(flagWorkers), configutil.Env[int]("WORKERS"), configutil.Const(1)),
+		configutil.Set(&c.CullInterval, configutil.Lazy(flagCullInterval), configutil.Env[time.Duration]("CULL_INTERVAL")),
+		configutil.Set(&c.CullRetention, configutil.Lazy(flagCullRetention), configutil.Env[time.Duration]("CULL_RETENTION")),
+		configutil.Set(&c.CullBatchSize, configutil.Lazy(flagCullBatchSize), configutil.Env[int]("CULL_BATCH_SIZE")),
+		configutil.Set(&c.StatsRetention, configutil.Lazy(flagStatsRetention), configutil.Env[time.Duration]("STATS_RETENTION")),
+	)
+}
+
+var entrypoint = apputil.DBEntryPoint[sandmanConfig]{
+	Setup: func(_ context.Context, _ sandmanConfig) error {
+		return nil
+	},
+	Migrate: func(ctx context.Context, _ sandmanConfig, dbc *db.Connection) error {
+		return model.Migrations(
+			migration.OptLog(log.GetLogger(ctx)),
+		).Apply(ctx, dbc)
+	},
+	Start: func(ctx context.Context, cfg sandmanConfig, dbc *db.Connection) error {
+		slant.Print(os.Stdout, "sandman")
+		modelMgr := &model.Manager{
+			BaseManager: dbutil.NewBaseManager(dbc),
+		}
+		if err := modelMgr.Initialize(ctx); err != nil {
+			return err
+		}
+		logger := log.GetLogger(ctx)
+
+		egressPolicy, err := egress.New(cfg.Egress)
+		if err != nil {
+			return err
+		}
+		var archiver *archive.Writer
+		if cfg.Archive.IsEnabled() {
+			archiver, err = archive.NewWriter(cfg.Archive)
+			if err != nil {
+				return err
+			}
+			defer archiver.Close()
+		}
+
+		// The in-process workers subscribe to the server's wakeups
+		// directly; there's no one else to tell.
+		hub := wakeup.NewHub()
+
+		s := grpc.NewServer(
+			grpc.ChainUnaryInterceptor(
+				grpcutil.Recover(),
+				grpcutil.Metrics(),
+				grpcutil.Logged(logger),
+			),
+			grpc.ChainStreamInterceptor(grpcutil.StreamMetrics()),
+		)
+		v1.RegisterTimersServer(s, server.TimerServer{
+			Model:         modelMgr,
+			Egress:        egressPolicy,
+			ArchiveDir:    cfg.Archive.Dir,
+			Wakeups:       hub,
+			WakeupHorizon: cfg.Worker.PollingIntervalOrDefault(),
+		})
+		v1.RegisterWorkersServer(s, server.WorkerServer{Model: modelMgr})
+		v1.RegisterWakeupsServer(s, server.WakeupServer{Hub: hub})
+
+		// As in sandman-srv, the gRPC health service tracks DB
+		// connectivity, and a drain flips it to NOT_SERVING for good.
+		hs := grpchealth.NewServer()
+		healthpb.RegisterHealthServer(s, hs)
+		healthServices := []string{"", v1.Timers_ServiceDesc.ServiceName, v1.Workers_ServiceDesc.ServiceName, v1.Wakeups_ServiceDesc.ServiceName}
+		go grpcutil.MonitorHealth(ctx, dbc.Ping, hs, healthServices, cfg.Health.CheckTimeoutOrDefault())
+
+		bindAddr := cfg.Server.BindAddr
+		var socketListener net.Listener
+		if after, ok := strings.CutPrefix(bindAddr, "unix://"); ok {
+			socketListener, err = net.Listen("unix", after)
+		} else {
+			socketListener, err = net.Listen("tcp", bindAddr)
+		}
+		if err != nil {
+			return err
+		}
+		services := []graceful.Service{
+			newHosted(ctx, func(context.Context) error {
+				logger.Info("listening", log.String("addr", bindAddr))
+				return s.Serve(socketListener)
+			}, func(<-chan struct{}) {
+				s.GracefulStop()
+			}),
+		}
+
+		workers := make([]*worker.Worker, 0, cfg.Workers)
+		for index := range cfg.Workers {
+			w := worker.New(fmt.Sprintf("%s-%02d", cfg.Hostname, index), modelMgr,
+				worker.OptConfig(cfg.Worker),
+				worker.OptEgressPolicy(egressPolicy),
+				worker.OptWakeups(hub),
+			)
+			workers = append(workers, w)
+			// a worker is drained before it's stopped so its wheel
+			// empties and it deregisters.
+			services = append(services, newHosted(ctx, w.Run, func(done <-chan struct{}) {
+				w.Drain()
+				select {
+				case <-w.Drained():
+				case <-done:
+				}
+			}))
+		}
+
+		ctrl := &control.Controller{
+			Config: control.Config{
+				WorkerBatchSize:       cfg.Worker.BatchSizeOrDefault(),
+				WorkerPollingInterval: cfg.Worker.PollingIntervalOrDefault(),
+				CullInterval:          cfg.CullInterval,
+				CullRetention:         cfg.CullRetention,
+				CullBatchSize:         cfg.CullBatchSize,
+				StatsRetention:        cfg.StatsRetention,
+			},
+			Model:    modelMgr,
+			Scaler:   &control.LogScaler{},
+			Archiver: archiver,
+		}
+		services = append(services, newHosted(ctx, ctrl.Run, nil))
+
+		muxes := make(apputil.HTTPMuxes)
+		if cfg.Metrics.IsEnabled() {
+			muxes.For(cfg.Metrics.BindAddr).Handle("/metrics", metrics.Handler())
+		}
+		if cfg.Health.IsEnabled() {
+			healthOpts := []health.Option{
+				health.OptReadiness("db", dbc.Ping),
+				health.OptDrain(func() {
+					hs.Shutdown()
+					for _, w := range workers {
+						w.Drain()
+					}
+				}),
+				health.OptCheckTimeout(cfg.Health.CheckTimeoutOrDefault()),
+			}
+			for index, w := range workers {
+				healthOpts = append(healthOpts,
+					health.OptLiveness(fmt.Sprintf("worker-%02d", index), w.Alive),
+					health.OptReadiness(fmt.Sprintf("worker-%02d", index), w.Ready),
+				)
+			}
+			health.New(healthOpts...).Register(muxes.For(cfg.Health.BindAddr))
+		}
+		muxes.ListenAndServe(ctx)
+
+		logger.Info("starting sandman", log.Int("workers", len(workers)))
+		return graceful.StartForShutdown(ctx, services...)
+	},
+}
+
+// hosted runs a blocking func as a graceful.Service. Stop calls drain,
+// if set, to wind the func down, then cancels its context and waits for
+// it to return. drain is handed a channel closed once it has returned,
+// in case it returned on its own first.
+type hosted struct {
+	run   func(context.Context) error
+	drain func(done <-chan struct{})
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newHosted(ctx context.Context, run func(context.Context) error, drain func(done <-chan struct{})) *hosted {
+	ctx, cancel := context.WithCancel(ctx)
+	return &hosted{
+		run:    run,
+		drain:  drain,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+func (h *hosted) Start(_ context.Context) error {
+	defer close(h.done)
+	return h.run(h.ctx)
+}
+
+func (h *hosted) Restart(_ context.Context) error {
+	return nil
+}
+
+func (h *hosted) Stop(_ context.Context) error {
+	if h.drain != nil {
+		h.drain(h.done)
+	}
+	h.cancel()
+	<-h.done
+	return nil
+}
+
+func init() {
+	entrypoint.Init()
+}
+
+func main() {
+	entrypoint.Main()
+}